@@ -2,15 +2,28 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/martin/atl-cli/internal/config"
 	"github.com/martin/atl-cli/internal/confluence"
 	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/martin/atl-cli/internal/jira"
 	"github.com/spf13/cobra"
 )
 
-var pageFormat string
+var (
+	pageFormat          string
+	pageJiraStatus      bool
+	pageVersion         int
+	pageResolveIncludes bool
+	pageSection         string
+	pageChunkChars      int
+	pageChunkTokens     int
+	pageBodyFormat      string
+)
 
 var confluenceCmd = &cobra.Command{
 	Use:   "confluence",
@@ -25,13 +38,40 @@ var confluencePageCmd = &cobra.Command{
 }
 
 var confluencePageGetCmd = &cobra.Command{
-	Use:   "get <page-id>",
-	Short: "Get a Confluence page by ID",
-	Long:  "Retrieves content of a Confluence page and outputs as JSON",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		pageID := args[0]
+	Use:   "get <page-id|url>",
+	Short: "Get a Confluence page by ID or URL",
+	Long: `Retrieves content of a Confluence page and outputs as JSON.
+
+The page can be given by ID or by a link to it on the configured site,
+including /wiki/x/ tiny links.
 
+--section narrows the output to one section of the Markdown body: the
+heading that best matches the given text, down to the next heading of the
+same or a higher level. Headings match case-insensitively and tolerate small
+differences in spelling; see "confluence page outline" for the headings.
+
+--format chunks splits the Markdown body along headings into chunks for a
+retrieval index, written as JSON Lines: one object per chunk with the page
+id, title, version, heading path, chunk index and content. Chunks stay
+within --chunk-chars characters and --chunk-tokens estimated tokens (4000
+characters if neither is set); code blocks and tables are never split, so a
+single large one can exceed the limit.
+
+--body-format chooses the representation requested from Confluence:
+storage (the default), atlas_doc_format (ADF, as authored in the cloud
+editor) or view (rendered HTML). The markdown formats convert ADF with the
+same renderer as Jira content, and view like storage format. ADF is
+converted without looking anything up: mentions keep the names stored in the
+document, attachments are not linked to the site, and --jira-status and
+--resolve-includes cannot be used.`,
+	Example: `  atl-cli confluence page get 12345678
+  atl-cli confluence page get 12345678 --section "Rollout plan" --format body-only
+  atl-cli confluence page get 12345678 --format chunks --chunk-tokens 500
+  atl-cli confluence page get 12345678 --body-format atlas_doc_format --format markdown
+  atl-cli confluence page get https://acme.atlassian.net/wiki/spaces/ENG/pages/12345678/Runbook
+  atl-cli confluence page get https://acme.atlassian.net/wiki/x/RAAB --format markdown`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load and validate config
 		cfg, err := config.LoadFromEnv()
 		if err != nil {
@@ -41,21 +81,49 @@ var confluencePageGetCmd = &cobra.Command{
 			return outputError(httpclient.NewConfigError(err.Error()))
 		}
 
+		pageID, err := confluence.ParsePageRef(args[0], cfg)
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
 		// Create client with debug flag from root command
 		client := confluence.NewClient(cfg, debug)
 
+		if pageVersion < 0 {
+			return outputError(httpclient.NewValidationError("--version must be a positive number"))
+		}
+		if cmd.Flags().Changed("section") && strings.TrimSpace(pageSection) == "" {
+			return outputError(httpclient.NewValidationError("--section cannot be empty"))
+		}
+		chunkOpts := confluence.ChunkOptions{MaxChars: pageChunkChars, MaxTokens: pageChunkTokens}
+		if err := chunkOpts.Validate(); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if err := confluence.ValidateBodyFormat(pageBodyFormat); err != nil {
+			return outputError(httpclient.NewValidationError("--body-format: " + err.Error()))
+		}
+		if pageFormat == "chunks" && pageSection != "" {
+			return outputError(httpclient.NewValidationError("--section cannot be used with --format chunks"))
+		}
+		if pageBodyFormat == confluence.BodyFormatADF && (pageJiraStatus || pageResolveIncludes) {
+			return outputError(httpclient.NewValidationError("--jira-status and --resolve-includes cannot be used with --body-format " + confluence.BodyFormatADF))
+		}
+
 		// Get page
-		page, err := client.GetPage(context.Background(), pageID)
+		page, err := client.GetPageFormat(context.Background(), pageID, pageVersion, pageBodyFormat)
 		if err != nil {
-			// Validation errors
-			if verr := confluence.ValidatePageID(pageID); verr != nil {
-				return outputError(httpclient.NewValidationError(verr.Error()))
+			return outputError(clientErrorResponse(err))
+		}
+
+		// Report what the Markdown conversion lost once the output is written
+		defer func() {
+			for _, w := range page.Warnings() {
+				writeWarning(os.Stderr, "lossy_conversion", w)
 			}
-			// Other errors (API errors are already formatted)
-			return outputError(&httpclient.ErrorResponse{
-				Error:   httpclient.ErrTypeUnknown,
-				Message: err.Error(),
-			})
+		}()
+
+		if pageSection != "" {
+			return writePageSection(client, page)
 		}
 
 		// Output page based on format
@@ -63,20 +131,325 @@ var confluencePageGetCmd = &cobra.Command{
 		case "json":
 			return page.Write(os.Stdout)
 		case "markdown":
+			resolvePageReferences(client, page)
 			return page.WriteMarkdown(os.Stdout)
 		case "body-only":
+			resolvePageReferences(client, page)
 			return page.WriteBodyOnly(os.Stdout)
+		case "chunks":
+			resolvePageReferences(client, page)
+			chunks, err := page.Chunks(chunkOpts)
+			if err != nil {
+				return outputError(clientErrorResponse(err))
+			}
+			return chunks.Write(os.Stdout)
 		default:
-			return outputError(httpclient.NewValidationError("invalid format: " + pageFormat + " (valid: json, markdown, body-only)"))
+			return outputError(httpclient.NewValidationError("invalid format: " + pageFormat + " (valid: json, markdown, body-only, chunks)"))
+		}
+	},
+}
+
+// Flags for confluence page create
+var (
+	pageCreateSpace      string
+	pageCreateTitle      string
+	pageCreateParent     string
+	pageCreateFile       string
+	pageCreateTemplate   string
+	pageCreateVars       []string
+	pageCreateBodyFormat string
+)
+
+var confluencePageCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a Confluence page from Markdown",
+	Long: `Creates a new Confluence page from a Markdown file (use "-" for stdin).
+
+Fenced code blocks become code macros, admonition blockquotes
+("> **Info:** ..." or "> [!NOTE]") become info/warning/note/tip macros,
+and <details> sections become expand macros.
+
+With --template, the page is created from a template: a Markdown file with
+YAML frontmatter (space, parent, title, labels) whose title and body can use
+Go template variables such as {{.incident}}, set with --var. The template
+can be given by path, or by name from the template search path (see
+"confluence template list"). --space, --title and --parent override the
+template's values; the body always comes from the template, so --file
+cannot be used with --template.
+
+--body-format atlas_doc_format publishes the page as an ADF document, as the
+cloud editor stores it, instead of storage format.`,
+	Example: `  atl-cli confluence page create --space ENG --title "Runbook" --file runbook.md
+  atl-cli confluence page create --template postmortem --var incident=INC-42 --var date=2026-10-18`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Load and validate config
+		cfg, err := config.LoadFromEnv()
+		if err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+		if err := cfg.Validate(); err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+
+		page := &confluence.NewPage{}
+		var markdown string
+		var labels []string
+
+		// If template is provided, load and process it
+		if pageCreateTemplate != "" {
+			if pageCreateFile != "" {
+				return outputError(httpclient.NewValidationError("--file cannot be used with --template; the template provides the body"))
+			}
+			path, err := confluence.FindTemplate(pageCreateTemplate, config.TemplatePath())
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+			tmpl, err := confluence.LoadPageTemplate(path)
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+			vars, err := jira.ParseVarFlags(pageCreateVars)
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+			parsed, err := tmpl.Apply(vars)
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+
+			page.SpaceKey = parsed.Space
+			page.Title = parsed.Title
+			page.ParentID = parsed.Parent
+			markdown = parsed.Body
+			labels = parsed.Labels
+		} else if len(pageCreateVars) > 0 {
+			return outputError(httpclient.NewValidationError("--var requires --template"))
+		}
+
+		// Command-line flags override template values
+		if pageCreateSpace != "" {
+			page.SpaceKey = pageCreateSpace
+		}
+		if pageCreateTitle != "" {
+			page.Title = pageCreateTitle
+		}
+		if pageCreateParent != "" {
+			page.ParentID = pageCreateParent
+		}
+
+		// Validate required fields
+		if page.SpaceKey == "" {
+			return outputError(httpclient.NewValidationError("--space is required"))
+		}
+		if page.Title == "" {
+			return outputError(httpclient.NewValidationError("--title is required"))
+		}
+		if pageCreateFile == "" && pageCreateTemplate == "" {
+			return outputError(httpclient.NewValidationError("--file is required"))
+		}
+		if pageCreateBodyFormat != confluence.BodyFormatStorage && pageCreateBodyFormat != confluence.BodyFormatADF {
+			return outputError(httpclient.NewValidationError("invalid body format: " + pageCreateBodyFormat + " (valid: storage, atlas_doc_format)"))
+		}
+		for _, label := range labels {
+			if err := confluence.ValidateLabel(label); err != nil {
+				return outputError(httpclient.NewValidationError("template label: " + err.Error()))
+			}
 		}
+
+		if pageCreateFile != "" {
+			content, err := readInput([]string{pageCreateFile})
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+			markdown = string(content)
+		}
+
+		if pageCreateBodyFormat == confluence.BodyFormatADF {
+			doc, err := json.Marshal(confluence.MarkdownToADF(markdown))
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+			page.Body = string(doc)
+			page.BodyFormat = confluence.BodyFormatADF
+		} else {
+			page.Body = confluence.MarkdownToStorage(markdown)
+		}
+		if err := page.Validate(); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		for _, w := range confluence.MarkdownWarnings(markdown) {
+			writeWarning(os.Stderr, "lossy_conversion", w)
+		}
+
+		// Create client and page
+		client := confluence.NewClient(cfg, debug)
+		created, err := client.CreatePage(context.Background(), page)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+
+		// The page exists at this point, so a labelling failure is only a warning
+		if len(labels) > 0 {
+			updated, err := client.UpdateLabels(context.Background(), created.ID, labels, nil)
+			if err != nil {
+				writeWarning(os.Stderr, "labels_not_added", "page created, but its labels could not be added: "+err.Error())
+			} else {
+				created.Labels = updated.Labels
+			}
+		}
+
+		return created.Write(os.Stdout)
+	},
+}
+
+// Flags for confluence page update
+var (
+	pageUpdateFile          string
+	pageUpdateTitle         string
+	pageUpdateMessage       string
+	pageUpdateExpectVersion int
+	pageUpdateMinorEdit     bool
+)
+
+var confluencePageUpdateCmd = &cobra.Command{
+	Use:   "update <page-id>",
+	Short: "Update a Confluence page from Markdown",
+	Long: `Replaces the content of a Confluence page with a Markdown file (use "-" for stdin).
+
+The page is submitted as the next version after the one currently stored.
+If someone else edits the page in between, the update fails with a conflict
+error instead of overwriting their changes. Use --expect-version to require
+that the page is still at a version you have already seen.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pageID := args[0]
+
+		// Load and validate config
+		cfg, err := config.LoadFromEnv()
+		if err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+		if err := cfg.Validate(); err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+
+		if err := confluence.ValidatePageID(pageID); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if pageUpdateFile == "" {
+			return outputError(httpclient.NewValidationError("--file is required"))
+		}
+
+		markdown, err := readInput([]string{pageUpdateFile})
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		update := &confluence.PageUpdate{
+			Title:         pageUpdateTitle,
+			Body:          confluence.MarkdownToStorage(string(markdown)),
+			Message:       pageUpdateMessage,
+			MinorEdit:     pageUpdateMinorEdit,
+			ExpectVersion: pageUpdateExpectVersion,
+		}
+		if err := update.Validate(); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		for _, w := range confluence.MarkdownWarnings(string(markdown)) {
+			writeWarning(os.Stderr, "lossy_conversion", w)
+		}
+
+		client := confluence.NewClient(cfg, debug)
+		updated, err := client.UpdatePage(context.Background(), pageID, update)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+
+		return updated.Write(os.Stdout)
 	},
 }
 
+// writePageSection writes the section of the page selected by --section.
+// The section is always Markdown, so the json format writes it as markdown
+// does.
+func writePageSection(client *confluence.Client, page *confluence.Page) error {
+	if pageFormat != "json" && pageFormat != "markdown" && pageFormat != "body-only" {
+		return outputError(httpclient.NewValidationError("invalid format: " + pageFormat + " (valid: json, markdown, body-only)"))
+	}
+	resolvePageReferences(client, page)
+	section, err := page.Section(pageSection)
+	if err != nil {
+		return outputError(clientErrorResponse(err))
+	}
+	if pageFormat == "body-only" {
+		_, err := fmt.Fprintln(os.Stdout, section.Body)
+		return err
+	}
+	return section.Write(os.Stdout)
+}
+
+// resolvePageReferences looks up what page get needs to render the page as
+// Markdown. Includes are resolved first so that mentions and issues in the
+// included pages are looked up too. ADF bodies are converted without
+// references, so nothing is looked up for them.
+func resolvePageReferences(client *confluence.Client, page *confluence.Page) {
+	if page.BodyFormat == confluence.BodyFormatADF {
+		return
+	}
+	if pageResolveIncludes {
+		client.ResolveIncludes(context.Background(), page)
+	}
+	client.ResolveReferences(context.Background(), page, pageJiraStatus)
+}
+
+// newConfluenceClient loads the configuration and creates a Confluence client.
+// Configuration errors are written to stderr and returned as an exit error.
+func newConfluenceClient() (*confluence.Client, error) {
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		return nil, outputError(httpclient.NewConfigError(err.Error()))
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, outputError(httpclient.NewConfigError(err.Error()))
+	}
+	return confluence.NewClient(cfg, debug), nil
+}
+
 func init() {
 	rootCmd.AddCommand(confluenceCmd)
 	confluenceCmd.AddCommand(confluencePageCmd)
 	confluencePageCmd.AddCommand(confluencePageGetCmd)
+	confluencePageCmd.AddCommand(confluencePageCreateCmd)
+	confluencePageCmd.AddCommand(confluencePageUpdateCmd)
 
 	confluencePageGetCmd.Flags().StringVar(&pageFormat, "format", "json",
-		"Output format: json (default), markdown, body-only")
+		"Output format: json (default), markdown, body-only, chunks")
+	confluencePageGetCmd.Flags().IntVar(&pageVersion, "version", 0, "Get this historical version instead of the current one")
+	confluencePageGetCmd.Flags().BoolVar(&pageJiraStatus, "jira-status", false,
+		"Show the current status of Jira issues referenced by jira macros (markdown formats)")
+	confluencePageGetCmd.Flags().StringVar(&pageSection, "section", "",
+		"Only output the section under the heading matching this text (markdown)")
+	confluencePageGetCmd.Flags().IntVar(&pageChunkChars, "chunk-chars", 0, "Maximum characters per chunk (chunks format)")
+	confluencePageGetCmd.Flags().IntVar(&pageChunkTokens, "chunk-tokens", 0, "Maximum estimated tokens per chunk (chunks format)")
+	confluencePageGetCmd.Flags().StringVar(&pageBodyFormat, "body-format", confluence.BodyFormatStorage,
+		"Body representation to fetch: storage, atlas_doc_format, view")
+	confluencePageGetCmd.Flags().BoolVar(&pageResolveIncludes, "resolve-includes", false,
+		"Inline pages referenced by include and excerpt-include macros (markdown formats)")
+
+	confluencePageCreateCmd.Flags().StringVar(&pageCreateSpace, "space", "", "Space key (e.g., ENG)")
+	confluencePageCreateCmd.Flags().StringVar(&pageCreateTitle, "title", "", "Page title")
+	confluencePageCreateCmd.Flags().StringVar(&pageCreateParent, "parent", "", "Parent page ID (optional)")
+	confluencePageCreateCmd.Flags().StringVar(&pageCreateFile, "file", "", "Markdown file to publish (\"-\" for stdin)")
+	confluencePageCreateCmd.Flags().StringVar(&pageCreateTemplate, "template", "", "Template file or name on the template search path")
+	confluencePageCreateCmd.Flags().StringArrayVar(&pageCreateVars, "var", nil, "Template variable (key=value), repeatable")
+	confluencePageCreateCmd.Flags().StringVar(&pageCreateBodyFormat, "body-format", confluence.BodyFormatStorage,
+		"Format to publish the body in: storage, atlas_doc_format")
+
+	confluencePageUpdateCmd.Flags().StringVar(&pageUpdateFile, "file", "", "Markdown file to publish (\"-\" for stdin)")
+	confluencePageUpdateCmd.Flags().StringVar(&pageUpdateTitle, "title", "", "New page title (default: keep current title)")
+	confluencePageUpdateCmd.Flags().StringVar(&pageUpdateMessage, "message", "", "Version message")
+	confluencePageUpdateCmd.Flags().IntVar(&pageUpdateExpectVersion, "expect-version", 0, "Fail with a conflict unless the page is at this version")
+	confluencePageUpdateCmd.Flags().BoolVar(&pageUpdateMinorEdit, "minor-edit", false, "Do not notify page watchers")
 }
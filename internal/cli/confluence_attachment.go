@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence page attachments
+var attachmentsDownloadDir string
+
+var confluencePageAttachmentsCmd = &cobra.Command{
+	Use:   "attachments <page-id>",
+	Short: "List or download the attachments of a Confluence page",
+	Long: `Lists the attachments of a page as a JSON array with each file's name,
+media type, size, version and download URL.
+
+With --download, every attachment is also streamed into the given directory
+(created if needed), replacing files with the same name, and its local path
+is included in the output.`,
+	Example: `  atl-cli confluence page attachments 12345678
+  atl-cli confluence page attachments 12345678 --download ./files`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		var attachments confluence.AttachmentList
+		if attachmentsDownloadDir != "" {
+			attachments, err = client.DownloadAttachments(context.Background(), args[0], attachmentsDownloadDir)
+		} else {
+			attachments, err = client.ListAttachments(context.Background(), args[0])
+		}
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return attachments.Write(os.Stdout)
+	},
+}
+
+var confluencePageAttachCmd = &cobra.Command{
+	Use:   "attach <page-id> <file>...",
+	Short: "Upload files as attachments to a Confluence page",
+	Long: `Uploads one or more files to a page. A file whose name matches an existing
+attachment is uploaded as a new version of that attachment.
+
+The uploaded attachments are written to stdout as a JSON array.`,
+	Example: `  atl-cli confluence page attach 12345678 design.pdf diagram.png`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pageID, files := args[0], args[1:]
+		if err := confluence.ValidatePageID(pageID); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		for _, name := range files {
+			if info, err := os.Stat(name); err != nil || info.IsDir() {
+				return outputError(httpclient.NewValidationError(name + " is not a readable file"))
+			}
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		uploaded := confluence.AttachmentList{}
+		for _, name := range files {
+			f, err := os.Open(name)
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+			att, err := client.UploadAttachment(context.Background(), pageID, filepath.Base(name), f)
+			f.Close()
+			if err != nil {
+				return outputError(clientErrorResponse(err))
+			}
+			uploaded = append(uploaded, att)
+		}
+		return uploaded.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluencePageCmd.AddCommand(confluencePageAttachmentsCmd)
+	confluencePageCmd.AddCommand(confluencePageAttachCmd)
+
+	confluencePageAttachmentsCmd.Flags().StringVar(&attachmentsDownloadDir, "download", "", "Download every attachment into this directory")
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/martin/atl-cli/internal/config"
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence blog get
+var (
+	blogFormat     string
+	blogJiraStatus bool
+)
+
+// defaultBlogListLimit bounds blog list when --limit is not given, since
+// every post is returned with its body.
+const defaultBlogListLimit = 25
+
+// Flags for confluence blog list
+var (
+	blogListSpace  string
+	blogListSince  string
+	blogListUntil  string
+	blogListLimit  int
+	blogListFormat string
+)
+
+// Flags for confluence blog create
+var (
+	blogCreateSpace string
+	blogCreateTitle string
+	blogCreateFile  string
+)
+
+var confluenceBlogCmd = &cobra.Command{
+	Use:   "blog",
+	Short: "Confluence blog post commands",
+	Long:  "Commands for reading and publishing Confluence blog posts",
+}
+
+var confluenceBlogGetCmd = &cobra.Command{
+	Use:   "get <blog-id|url>",
+	Short: "Get a Confluence blog post by ID or URL",
+	Long: `Retrieves a blog post and outputs it as JSON, in the same format as
+"confluence page get". The post can be given by ID or by a link to it on the
+configured site.`,
+	Example: `  atl-cli confluence blog get 98765
+  atl-cli confluence blog get https://acme.atlassian.net/wiki/spaces/ENG/blog/2024/03/01/98765/Release+notes --format markdown`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if blogFormat != "json" && blogFormat != "markdown" && blogFormat != "body-only" {
+			return outputError(httpclient.NewValidationError("invalid format: " + blogFormat + " (valid: json, markdown, body-only)"))
+		}
+
+		cfg, err := config.LoadFromEnv()
+		if err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+		if err := cfg.Validate(); err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+
+		postID, err := confluence.ParseBlogPostRef(args[0], cfg)
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client := confluence.NewClient(cfg, debug)
+
+		post, err := client.GetBlogPost(context.Background(), postID)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+
+		switch blogFormat {
+		case "markdown":
+			client.ResolveReferences(context.Background(), post, blogJiraStatus)
+			return post.WriteMarkdown(os.Stdout)
+		case "body-only":
+			client.ResolveReferences(context.Background(), post, blogJiraStatus)
+			return post.WriteBodyOnly(os.Stdout)
+		default:
+			return post.Write(os.Stdout)
+		}
+	},
+}
+
+var confluenceBlogListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Confluence blog posts",
+	Long: `Lists blog posts with their bodies as a JSON array, newest first. At
+most 25 posts are listed unless --limit says otherwise; --limit 0 lists all
+matching posts.
+
+--since and --until filter by publication date and take a date (2024-03-01)
+or a timestamp (2024-03-01T09:00:00Z); a date given to --until includes that
+whole day.`,
+	Example: `  atl-cli confluence blog list --space ENG --limit 5
+  atl-cli confluence blog list --space ENG --since 2024-01-01 --until 2024-03-31 --format markdown`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := confluence.BlogListOptions{SpaceKey: blogListSpace, Limit: blogListLimit}
+		if blogListSpace != "" {
+			if err := confluence.ValidateSpaceKey(blogListSpace); err != nil {
+				return outputError(httpclient.NewValidationError("--space: " + err.Error()))
+			}
+		}
+		if blogListLimit < 0 {
+			return outputError(httpclient.NewValidationError("--limit cannot be negative"))
+		}
+		if blogListFormat != "json" && blogListFormat != "markdown" {
+			return outputError(httpclient.NewValidationError("invalid format: " + blogListFormat + " (valid: json, markdown)"))
+		}
+		var err error
+		if opts.Since, err = parseDateFlag("--since", blogListSince, false); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if opts.Until, err = parseDateFlag("--until", blogListUntil, true); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		posts, err := client.ListBlogPosts(context.Background(), opts)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		if blogListFormat == "markdown" {
+			for _, post := range posts {
+				client.ResolveReferences(context.Background(), post, false)
+			}
+			return posts.WriteMarkdown(os.Stdout)
+		}
+		return posts.Write(os.Stdout)
+	},
+}
+
+var confluenceBlogCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Publish a Confluence blog post from Markdown",
+	Long: `Publishes a blog post in a space from a Markdown file (use "-" for stdin),
+converted as for "confluence page create".`,
+	Example: `  atl-cli confluence blog create --space ENG --title "Release notes" --file notes.md`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if blogCreateFile == "" {
+			return outputError(httpclient.NewValidationError("--file is required"))
+		}
+		markdown, err := readInput([]string{blogCreateFile})
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		post := &confluence.NewPage{
+			SpaceKey: blogCreateSpace,
+			Title:    blogCreateTitle,
+			Body:     confluence.MarkdownToStorage(string(markdown)),
+		}
+		if err := post.Validate(); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		for _, w := range confluence.MarkdownWarnings(string(markdown)) {
+			writeWarning(os.Stderr, "lossy_conversion", w)
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		created, err := client.CreateBlogPost(context.Background(), post)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return created.Write(os.Stdout)
+	},
+}
+
+// parseDateFlag parses a date (2006-01-02, in UTC) or RFC 3339 timestamp
+// flag. An empty value gives the zero time. With endOfDay, a date refers to
+// the end of that day, so that a range ending on it includes the whole day.
+func parseDateFlag(name, value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: invalid date %q (expected 2006-01-02 or an RFC 3339 timestamp)", name, value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func init() {
+	confluenceCmd.AddCommand(confluenceBlogCmd)
+	confluenceBlogCmd.AddCommand(confluenceBlogGetCmd)
+	confluenceBlogCmd.AddCommand(confluenceBlogListCmd)
+	confluenceBlogCmd.AddCommand(confluenceBlogCreateCmd)
+
+	confluenceBlogGetCmd.Flags().StringVar(&blogFormat, "format", "json", "Output format: json, markdown, or body-only")
+	confluenceBlogGetCmd.Flags().BoolVar(&blogJiraStatus, "jira-status", false, "Look up the current status of linked Jira issues (markdown formats)")
+
+	confluenceBlogListCmd.Flags().StringVar(&blogListSpace, "space", "", "Only list posts in this space")
+	confluenceBlogListCmd.Flags().StringVar(&blogListSince, "since", "", "Only list posts published on or after this date")
+	confluenceBlogListCmd.Flags().StringVar(&blogListUntil, "until", "", "Only list posts published on or before this date")
+	confluenceBlogListCmd.Flags().IntVar(&blogListLimit, "limit", defaultBlogListLimit, "Maximum number of posts (0 for all)")
+	confluenceBlogListCmd.Flags().StringVar(&blogListFormat, "format", "json", "Output format: json or markdown")
+
+	confluenceBlogCreateCmd.Flags().StringVar(&blogCreateSpace, "space", "", "Space key (e.g., ENG)")
+	confluenceBlogCreateCmd.Flags().StringVar(&blogCreateTitle, "title", "", "Post title")
+	confluenceBlogCreateCmd.Flags().StringVar(&blogCreateFile, "file", "", "Markdown file to publish (\"-\" for stdin)")
+	confluenceBlogCreateCmd.MarkFlagRequired("space")
+	confluenceBlogCreateCmd.MarkFlagRequired("title")
+	confluenceBlogCreateCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{name: "empty", value: ""},
+		{name: "date", value: "2024-03-01", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "date at end of day", value: "2024-03-31", endOfDay: true, want: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "timestamp", value: "2024-03-01T09:30:00Z", endOfDay: true, want: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "invalid", value: "March 1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDateFlag("--since", tt.value, tt.endOfDay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDateFlag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseDateFlag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence page comment add
+var (
+	commentAddFile    string
+	commentAddReplyTo string
+)
+
+var confluencePageCommentsCmd = &cobra.Command{
+	Use:   "comments <page-id>",
+	Short: "List the comments on a Confluence page",
+	Long: `Lists the footer and inline comments of a page as a JSON array of threads,
+footer comments first. Each comment has its author, creation date, Markdown
+body and nested replies. Inline comments also carry their resolution status
+and the highlighted page text they are anchored to.`,
+	Example: `  atl-cli confluence page comments 12345678`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		comments, err := client.ListComments(context.Background(), args[0])
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return comments.Write(os.Stdout)
+	},
+}
+
+var confluencePageCommentCmd = &cobra.Command{
+	Use:   "comment",
+	Short: "Confluence page comment commands",
+	Long:  "Commands for posting comments on Confluence pages",
+}
+
+var confluencePageCommentAddCmd = &cobra.Command{
+	Use:   "add <page-id>",
+	Short: "Post a comment on a Confluence page",
+	Long: `Posts a Markdown file (use "-" for stdin) as a footer comment on a page.
+
+With --reply-to, the comment is posted as a reply to that footer or inline
+comment instead. The new comment is written to stdout as JSON.`,
+	Example: `  atl-cli confluence page comment add 12345678 --file review.md
+  atl-cli confluence page comment add 12345678 --file reply.md --reply-to 98765`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		markdown, err := readInput([]string{commentAddFile})
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		comment := &confluence.NewComment{
+			PageID:  args[0],
+			ReplyTo: commentAddReplyTo,
+			Body:    confluence.MarkdownToStorage(string(markdown)),
+		}
+		if err := comment.Validate(); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		for _, w := range confluence.MarkdownWarnings(string(markdown)) {
+			writeWarning(os.Stderr, "lossy_conversion", w)
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		created, err := client.AddComment(context.Background(), comment)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return created.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluencePageCmd.AddCommand(confluencePageCommentsCmd)
+	confluencePageCmd.AddCommand(confluencePageCommentCmd)
+	confluencePageCommentCmd.AddCommand(confluencePageCommentAddCmd)
+
+	confluencePageCommentAddCmd.Flags().StringVar(&commentAddFile, "file", "", "Markdown file to post (\"-\" for stdin)")
+	confluencePageCommentAddCmd.Flags().StringVar(&commentAddReplyTo, "reply-to", "", "ID of the comment to reply to")
+	confluencePageCommentAddCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence page labels
+var (
+	labelsAdd    []string
+	labelsRemove []string
+)
+
+// Flags for confluence page property set and delete
+var propertyExpectVersion int
+
+var confluencePageLabelsCmd = &cobra.Command{
+	Use:   "labels <page-id>",
+	Short: "List, add or remove the labels of a Confluence page",
+	Long: `Outputs the labels of a page as JSON. With --add and --remove (repeatable
+or comma-separated), the labels are changed first and the resulting set is
+output. Removing a label the page does not have is not an error.`,
+	Example: `  atl-cli confluence page labels 12345678
+  atl-cli confluence page labels 12345678 --add runbook,owner-team-x --remove draft`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		for _, label := range append(append([]string{}, labelsAdd...), labelsRemove...) {
+			if err := confluence.ValidateLabel(label); err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		labels, err := client.UpdateLabels(context.Background(), args[0], labelsAdd, labelsRemove)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return labels.Write(os.Stdout)
+	},
+}
+
+var confluencePagePropertyCmd = &cobra.Command{
+	Use:   "property",
+	Short: "Confluence content property commands",
+	Long: `Commands for reading and writing content properties: JSON values stored on
+a page under a key, for automation metadata.
+
+Writes are versioned. An update is submitted as the version after the one
+read, so a concurrent writer causes a conflict error instead of a lost
+update; pass --expect-version to also require the version you last saw.`,
+}
+
+var confluencePagePropertyGetCmd = &cobra.Command{
+	Use:     "get <page-id> <key>",
+	Short:   "Get a content property of a Confluence page",
+	Example: `  atl-cli confluence page property get 12345678 owner`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validatePropertyArgs(args); err != nil {
+			return err
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		prop, err := client.GetProperty(context.Background(), args[0], args[1])
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return prop.Write(os.Stdout)
+	},
+}
+
+var confluencePagePropertySetCmd = &cobra.Command{
+	Use:   "set <page-id> <key> [json]",
+	Short: "Create or update a content property of a Confluence page",
+	Long: `Sets a content property to a JSON value, given as an argument or read from
+stdin when omitted. The property is created if it does not exist.`,
+	Example: `  atl-cli confluence page property set 12345678 owner '{"team": "payments"}'
+  atl-cli confluence page property set 12345678 owner '{"team": "core"}' --expect-version 3`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validatePropertyArgs(args); err != nil {
+			return err
+		}
+		if propertyExpectVersion < 0 {
+			return outputError(httpclient.NewValidationError("--expect-version must be a positive number"))
+		}
+
+		var value []byte
+		if len(args) == 3 {
+			value = []byte(args[2])
+		} else {
+			data, err := readInput(nil)
+			if err != nil {
+				return outputError(httpclient.NewValidationError(err.Error()))
+			}
+			value = data
+		}
+		if !json.Valid(value) {
+			return outputError(httpclient.NewValidationError("property value must be valid JSON"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		prop, err := client.SetProperty(context.Background(), args[0], args[1], value, propertyExpectVersion)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return prop.Write(os.Stdout)
+	},
+}
+
+var confluencePagePropertyDeleteCmd = &cobra.Command{
+	Use:   "delete <page-id> <key>",
+	Short: "Delete a content property of a Confluence page",
+	Long:  "Deletes a content property and outputs it as it was before the deletion.",
+	Example: `  atl-cli confluence page property delete 12345678 owner
+  atl-cli confluence page property delete 12345678 owner --expect-version 4`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validatePropertyArgs(args); err != nil {
+			return err
+		}
+		if propertyExpectVersion < 0 {
+			return outputError(httpclient.NewValidationError("--expect-version must be a positive number"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		prop, err := client.DeleteProperty(context.Background(), args[0], args[1], propertyExpectVersion)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return prop.Write(os.Stdout)
+	},
+}
+
+// validatePropertyArgs validates the page ID and key arguments of the
+// property commands, writing a validation error if either is invalid.
+func validatePropertyArgs(args []string) error {
+	if err := confluence.ValidatePageID(args[0]); err != nil {
+		return outputError(httpclient.NewValidationError(err.Error()))
+	}
+	if err := confluence.ValidatePropertyKey(args[1]); err != nil {
+		return outputError(httpclient.NewValidationError(err.Error()))
+	}
+	return nil
+}
+
+func init() {
+	confluencePageCmd.AddCommand(confluencePageLabelsCmd)
+	confluencePageCmd.AddCommand(confluencePagePropertyCmd)
+	confluencePagePropertyCmd.AddCommand(confluencePagePropertyGetCmd)
+	confluencePagePropertyCmd.AddCommand(confluencePagePropertySetCmd)
+	confluencePagePropertyCmd.AddCommand(confluencePagePropertyDeleteCmd)
+
+	confluencePageLabelsCmd.Flags().StringSliceVar(&labelsAdd, "add", nil, "Labels to add (repeatable or comma-separated)")
+	confluencePageLabelsCmd.Flags().StringSliceVar(&labelsRemove, "remove", nil, "Labels to remove (repeatable or comma-separated)")
+
+	for _, cmd := range []*cobra.Command{confluencePagePropertySetCmd, confluencePagePropertyDeleteCmd} {
+		cmd.Flags().IntVar(&propertyExpectVersion, "expect-version", 0, "Fail with a conflict unless the property is at this version")
+	}
+}
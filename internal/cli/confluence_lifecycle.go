@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence page move
+var (
+	moveParent   string
+	movePosition string
+)
+
+// Flags for confluence page copy
+var (
+	copyToParent        string
+	copyTitle           string
+	copyIncludeChildren bool
+	copyTitlePrefix     string
+)
+
+// Flags for confluence page archive and delete
+var lifecycleYes bool
+
+// CopyProgress is written to stderr as a JSON line after each page is copied.
+type CopyProgress struct {
+	Progress string `json:"progress"`
+	Done     int    `json:"done"`
+	Total    int    `json:"total"`
+	SourceID string `json:"sourceId"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+}
+
+var confluencePageMoveCmd = &cobra.Command{
+	Use:   "move <page-id>",
+	Short: "Move a Confluence page and its descendants",
+	Long: `Moves a page, with its descendants, relative to the --parent page. With the
+default --position append the page becomes the last child of --parent; with
+before or after it becomes a sibling placed just before or after it.
+
+The moved page is written to stdout as JSON with its new parent.`,
+	Example: `  atl-cli confluence page move 12345678 --parent 87654321
+  atl-cli confluence page move 12345678 --parent 11223344 --position before`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if err := confluence.ValidatePageID(moveParent); err != nil {
+			return outputError(httpclient.NewValidationError("--parent: " + err.Error()))
+		}
+		switch movePosition {
+		case confluence.MoveBefore, confluence.MoveAfter, confluence.MoveAppend:
+		default:
+			return outputError(httpclient.NewValidationError("--position must be before, after or append"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		change, err := client.MovePage(context.Background(), args[0], moveParent, movePosition)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return change.Write(os.Stdout)
+	},
+}
+
+var confluencePageCopyCmd = &cobra.Command{
+	Use:   "copy <page-id>",
+	Short: "Copy a Confluence page, optionally with its descendants",
+	Long: `Copies a page with its attachments, labels and properties under the
+--to-parent page. --title renames the copy; with --include-children the
+whole subtree is copied, keeping the titles and order of the descendants.
+--title-prefix is prepended to the title of every copied page (except a root
+renamed with --title).
+
+Progress is written to stderr as one JSON line per copied page. The result,
+including a mapping from each source page ID to the ID of its copy, is
+written to stdout as JSON. Confluence rejects duplicate titles within a
+space, so a copy into the same space needs --title or --title-prefix, and
+with --include-children it needs --title-prefix.
+
+If a page fails to copy, the copies already made are kept: the result is
+still written to stdout, marked "incomplete", before the error.`,
+	Example: `  atl-cli confluence page copy 12345678 --to-parent 87654321
+  atl-cli confluence page copy 12345678 --to-parent 87654321 --title "Handbook 2025"
+  atl-cli confluence page copy 12345678 --to-parent 55555555 --include-children
+  atl-cli confluence page copy 12345678 --to-parent 87654321 --include-children --title-prefix "2025 "`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if err := confluence.ValidatePageID(copyToParent); err != nil {
+			return outputError(httpclient.NewValidationError("--to-parent: " + err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		progress := json.NewEncoder(os.Stderr)
+		result, err := client.CopyPage(context.Background(), confluence.CopyOptions{
+			SourceID:        args[0],
+			ParentID:        copyToParent,
+			Title:           copyTitle,
+			IncludeChildren: copyIncludeChildren,
+			TitlePrefix:     copyTitlePrefix,
+			Progress: func(done, total int, page *confluence.CopiedPage) {
+				progress.Encode(&CopyProgress{
+					Progress: "copied",
+					Done:     done,
+					Total:    total,
+					SourceID: page.SourceID,
+					ID:       page.ID,
+					Title:    page.Title,
+				})
+			},
+		})
+		if err != nil {
+			// Report the copies that were made, so they can be found again
+			if result != nil && len(result.Pages) > 0 {
+				result.Write(os.Stdout)
+			}
+			return outputError(clientErrorResponse(err))
+		}
+		return result.Write(os.Stdout)
+	},
+}
+
+var confluencePageArchiveCmd = &cobra.Command{
+	Use:   "archive <page-id>",
+	Short: "Archive a Confluence page",
+	Long: `Archives a page. Confluence archives in the background, so the page may
+take a moment to disappear from the space.
+
+Asks for confirmation on a terminal; --yes is required when stdin is not a
+terminal.`,
+	Example: `  atl-cli confluence page archive 12345678
+  atl-cli confluence page archive 12345678 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDestructive(args[0], "Archive", (*confluence.Client).ArchivePage)
+	},
+}
+
+var confluencePageDeleteCmd = &cobra.Command{
+	Use:   "delete <page-id>",
+	Short: "Move a Confluence page to the trash",
+	Long: `Moves a page to the space trash, from where "page restore" can bring it
+back. Its children move up to the page's parent.
+
+Asks for confirmation on a terminal; --yes is required when stdin is not a
+terminal.`,
+	Example: `  atl-cli confluence page delete 12345678
+  atl-cli confluence page delete 12345678 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDestructive(args[0], "Delete", (*confluence.Client).DeletePage)
+	},
+}
+
+var confluencePageRestoreCmd = &cobra.Command{
+	Use:     "restore <page-id>",
+	Short:   "Restore a Confluence page from the trash",
+	Example: `  atl-cli confluence page restore 12345678`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		change, err := client.RestorePage(context.Background(), args[0])
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return change.Write(os.Stdout)
+	},
+}
+
+// runDestructive confirms and runs a destructive operation on a page.
+func runDestructive(id, verb string, op func(*confluence.Client, context.Context, string) (*confluence.PageChange, error)) error {
+	if err := confluence.ValidatePageID(id); err != nil {
+		return outputError(httpclient.NewValidationError(err.Error()))
+	}
+	if errResp := confirm(fmt.Sprintf("%s page %s?", verb, id), lifecycleYes, os.Stdin, os.Stderr); errResp != nil {
+		return outputError(errResp)
+	}
+
+	client, err := newConfluenceClient()
+	if err != nil {
+		return err
+	}
+
+	change, err := op(client, context.Background(), id)
+	if err != nil {
+		return outputError(clientErrorResponse(err))
+	}
+	return change.Write(os.Stdout)
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+var stdinIsTerminal = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirm asks a yes/no question on out and reads the answer from in, unless
+// yes is set. When stdin is not a terminal there is nobody to ask, so the
+// operation is refused unless yes is set.
+func confirm(prompt string, yes bool, in io.Reader, out io.Writer) *httpclient.ErrorResponse {
+	if yes {
+		return nil
+	}
+	if !stdinIsTerminal() {
+		return httpclient.NewValidationError("refusing to continue without --yes when stdin is not a terminal")
+	}
+
+	fmt.Fprintf(out, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return httpclient.NewValidationError("aborted")
+}
+
+func init() {
+	confluencePageCmd.AddCommand(confluencePageMoveCmd)
+	confluencePageCmd.AddCommand(confluencePageCopyCmd)
+	confluencePageCmd.AddCommand(confluencePageArchiveCmd)
+	confluencePageCmd.AddCommand(confluencePageDeleteCmd)
+	confluencePageCmd.AddCommand(confluencePageRestoreCmd)
+
+	confluencePageMoveCmd.Flags().StringVar(&moveParent, "parent", "", "ID of the target page (required)")
+	confluencePageMoveCmd.Flags().StringVar(&movePosition, "position", confluence.MoveAppend, "Position relative to the target: before, after or append")
+	confluencePageMoveCmd.MarkFlagRequired("parent")
+
+	confluencePageCopyCmd.Flags().StringVar(&copyToParent, "to-parent", "", "ID of the page to copy under (required)")
+	confluencePageCopyCmd.Flags().StringVar(&copyTitle, "title", "", "Title of the copy (defaults to the source title)")
+	confluencePageCopyCmd.Flags().BoolVar(&copyIncludeChildren, "include-children", false, "Copy the descendants of the page too")
+	confluencePageCopyCmd.Flags().StringVar(&copyTitlePrefix, "title-prefix", "", "Prefix for the title of every copied page, for copies within the same space")
+	confluencePageCopyCmd.MarkFlagRequired("to-parent")
+
+	for _, cmd := range []*cobra.Command{confluencePageArchiveCmd, confluencePageDeleteCmd} {
+		cmd.Flags().BoolVarP(&lifecycleYes, "yes", "y", false, "Skip the confirmation prompt")
+	}
+}
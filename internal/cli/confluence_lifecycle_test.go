@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	origTerminal := stdinIsTerminal
+	defer func() { stdinIsTerminal = origTerminal }()
+
+	tests := []struct {
+		name     string
+		yes      bool
+		terminal bool
+		answer   string
+		wantErr  bool
+		wantAsk  bool
+	}{
+		{name: "yes flag", yes: true, terminal: false},
+		{name: "no terminal without yes", terminal: false, wantErr: true},
+		{name: "confirmed", terminal: true, answer: "y\n", wantAsk: true},
+		{name: "confirmed in full", terminal: true, answer: "Yes\n", wantAsk: true},
+		{name: "declined", terminal: true, answer: "n\n", wantErr: true, wantAsk: true},
+		{name: "empty answer", terminal: true, answer: "\n", wantErr: true, wantAsk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdinIsTerminal = func() bool { return tt.terminal }
+			var out bytes.Buffer
+
+			errResp := confirm("Delete page 123?", tt.yes, strings.NewReader(tt.answer), &out)
+			if (errResp != nil) != tt.wantErr {
+				t.Errorf("confirm() error = %v, wantErr %v", errResp, tt.wantErr)
+			}
+			if asked := strings.Contains(out.String(), "Delete page 123? [y/N]"); asked != tt.wantAsk {
+				t.Errorf("prompt written = %v, want %v (output %q)", asked, tt.wantAsk, out.String())
+			}
+		})
+	}
+}
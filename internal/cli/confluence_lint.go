@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence lint links
+var lintConcurrency int
+
+var confluenceLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Confluence content checks",
+	Long:  "Commands that check the content of a Confluence space for problems",
+}
+
+var confluenceLintLinksCmd = &cobra.Command{
+	Use:   "links <space-key>",
+	Short: "Report broken links and orphan pages in a space",
+	Long: `Checks every link between pages in a space and outputs a JSON report of the
+broken links on each page and of the orphan pages.
+
+Links are read from the storage format of every page: <ac:link> page links
+and other page references, and plain links to pages on the configured site.
+A link is broken if its target page, or the space it names, does not exist.
+Links to other spaces are checked too, but only pages in this space are
+reported as orphans: pages no other page links to. The space homepage is
+never an orphan.
+
+Page bodies are read with the page list. Links to pages outside the space
+are looked up concurrently, with at most --concurrency requests in flight.`,
+	Example: `  atl-cli confluence lint links ENG
+  atl-cli confluence lint links ENG --concurrency 8`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidateSpaceKey(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if lintConcurrency < 1 {
+			return outputError(httpclient.NewValidationError("--concurrency must be at least 1"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		report, err := client.LintLinks(context.Background(), confluence.LinkLintOptions{
+			SpaceKey:    args[0],
+			Concurrency: lintConcurrency,
+		})
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return report.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluenceCmd.AddCommand(confluenceLintCmd)
+	confluenceLintCmd.AddCommand(confluenceLintLinksCmd)
+
+	confluenceLintLinksCmd.Flags().IntVar(&lintConcurrency, "concurrency", confluence.DefaultLintConcurrency, "Maximum number of link lookups in flight")
+}
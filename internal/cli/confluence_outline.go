@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/martin/atl-cli/internal/config"
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+var confluencePageOutlineCmd = &cobra.Command{
+	Use:   "outline <page-id|url>",
+	Short: "Show the heading tree of a Confluence page",
+	Long: `Outputs the headings of a page's Markdown body as a JSON tree. Each heading
+has its level, text and the anchor that links to it (append "#<anchor>" to
+the page URL), with the headings under it as children.
+
+Use a heading's text with "confluence page get --section" to fetch just that
+section.`,
+	Example: `  atl-cli confluence page outline 12345678
+  atl-cli confluence page outline https://acme.atlassian.net/wiki/spaces/ENG/pages/12345678/Design`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadFromEnv()
+		if err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+		if err := cfg.Validate(); err != nil {
+			return outputError(httpclient.NewConfigError(err.Error()))
+		}
+
+		pageID, err := confluence.ParsePageRef(args[0], cfg)
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client := confluence.NewClient(cfg, debug)
+		page, err := client.GetPage(context.Background(), pageID)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+
+		// Headings may contain mentions and links, which render as in page get
+		client.ResolveReferences(context.Background(), page, false)
+		outline, err := page.Outline()
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return outline.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluencePageCmd.AddCommand(confluencePageOutlineCmd)
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence report stale
+var (
+	staleSpaces    []string
+	staleOlderThan string
+	staleLabels    []string
+	staleSort      string
+	staleFormat    string
+)
+
+var confluenceReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Confluence content reports",
+	Long:  "Commands that report on the content of Confluence spaces",
+}
+
+var confluenceReportStaleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List pages that have not been modified for a while",
+	Long: `Lists the pages in one or more spaces that were last modified longer ago than
+--older-than, with their last author, version count and labels (such as
+owner-* labels), oldest first.
+
+The pages are found with a CQL search, so large spaces are not crawled.
+--label restricts the report to pages with all of the given labels. A label
+ending in "*", such as owner-*, matches any label with that prefix; CQL has
+no label wildcards, so prefixes are matched after the search. --sort orders
+by modified, title, author, versions or label; prefix the key with "-" to
+sort in descending order. Sorting by label groups pages by their first label
+matching the first --label prefix (or by their first label), with unlabelled
+pages last.
+
+The report is written as JSON, or with --format csv as CSV with a header
+row and labels separated by semicolons.`,
+	Example: `  atl-cli confluence report stale --space ENG --older-than 365d
+  atl-cli confluence report stale --space ENG --space OPS --older-than 26w --label runbook
+  atl-cli confluence report stale --space ENG --older-than 1y --sort -versions --format csv > stale.csv
+  atl-cli confluence report stale --space ENG --older-than 1y --label 'owner-*' --sort label`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		age, err := confluence.ParseAge(staleOlderThan)
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if staleFormat != "json" && staleFormat != "csv" {
+			return outputError(httpclient.NewValidationError("invalid format: " + staleFormat + " (valid: json, csv)"))
+		}
+		opts := confluence.StaleOptions{
+			SpaceKeys: staleSpaces,
+			Before:    time.Now().Add(-age),
+			Labels:    staleLabels,
+			Sort:      staleSort,
+		}
+		if err := opts.Validate(); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		report, err := client.StaleReport(context.Background(), opts)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		if staleFormat == "csv" {
+			return report.WriteCSV(os.Stdout)
+		}
+		return report.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluenceCmd.AddCommand(confluenceReportCmd)
+	confluenceReportCmd.AddCommand(confluenceReportStaleCmd)
+
+	confluenceReportStaleCmd.Flags().StringSliceVar(&staleSpaces, "space", nil, "Space key (required, repeatable or comma-separated)")
+	confluenceReportStaleCmd.Flags().StringVar(&staleOlderThan, "older-than", "365d", "Minimum time since the last modification (e.g. 365d, 26w, 1y)")
+	confluenceReportStaleCmd.Flags().StringSliceVar(&staleLabels, "label", nil, "Only include pages with this label, or a label prefix ending in * (repeatable or comma-separated)")
+	confluenceReportStaleCmd.Flags().StringVar(&staleSort, "sort", "modified", "Sort by "+strings.Join(confluence.StaleSortKeys, ", ")+"; prefix with - for descending")
+	confluenceReportStaleCmd.Flags().StringVar(&staleFormat, "format", "json", "Output format: json or csv")
+}
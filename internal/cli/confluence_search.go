@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence search
+var (
+	searchCQL   string
+	searchText  string
+	searchLimit int
+)
+
+var confluenceSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search Confluence content with CQL",
+	Long: `Searches Confluence content with a CQL query and outputs all matching
+results as JSON.
+
+Use --text for a full-text search without writing CQL; the text is escaped
+for you. When both --cql and --text are given they are combined with AND.`,
+	Example: `  atl-cli confluence search --cql 'space = ENG and label = runbook'
+  atl-cli confluence search --text 'disk full'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cql := searchCQL
+		if searchText != "" {
+			if cql != "" {
+				cql = "(" + cql + ") AND " + confluence.TextQuery(searchText)
+			} else {
+				cql = confluence.TextQuery(searchText)
+			}
+		}
+		if cql == "" {
+			return outputError(httpclient.NewValidationError("--cql or --text is required"))
+		}
+		if searchLimit < 0 {
+			return outputError(httpclient.NewValidationError("--limit cannot be negative"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		results, err := client.Search(context.Background(), cql, searchLimit)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+
+		output := &confluence.SearchResults{CQL: cql, Results: results}
+		return output.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluenceCmd.AddCommand(confluenceSearchCmd)
+
+	confluenceSearchCmd.Flags().StringVar(&searchCQL, "cql", "", "CQL query")
+	confluenceSearchCmd.Flags().StringVar(&searchText, "text", "", "Full-text search terms (escaped automatically)")
+	confluenceSearchCmd.Flags().IntVar(&searchLimit, "limit", 0, "Maximum number of results (default: all)")
+}
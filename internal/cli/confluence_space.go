@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence space list
+var spaceListType string
+
+var confluenceSpaceCmd = &cobra.Command{
+	Use:   "space",
+	Short: "Confluence space operations",
+	Long:  `Commands for working with Confluence spaces.`,
+}
+
+var confluenceSpaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Confluence spaces",
+	Long: `Lists all Confluence spaces visible to you as a JSON array.
+
+Each space includes its key, name, type, homepage ID and description.`,
+	Example: `  atl-cli confluence space list
+  atl-cli confluence space list --type global`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if spaceListType != "" && spaceListType != "global" && spaceListType != "personal" {
+			return outputError(httpclient.NewValidationError("--type must be global or personal"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		spaces, err := client.ListSpaces(context.Background(), spaceListType)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return spaces.Write(os.Stdout)
+	},
+}
+
+var confluenceSpaceGetCmd = &cobra.Command{
+	Use:   "get <space-key>",
+	Short: "Get a Confluence space by key",
+	Long: `Retrieves a Confluence space by its key and outputs it as JSON.
+
+The output includes the key, name, type, homepage ID and description.`,
+	Example: `  atl-cli confluence space get ENG`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		if err := confluence.ValidateSpaceKey(key); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		space, err := client.GetSpace(context.Background(), key)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return space.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluenceCmd.AddCommand(confluenceSpaceCmd)
+	confluenceSpaceCmd.AddCommand(confluenceSpaceListCmd)
+	confluenceSpaceCmd.AddCommand(confluenceSpaceGetCmd)
+
+	confluenceSpaceListCmd.Flags().StringVar(&spaceListType, "type", "", "Filter by space type: global or personal")
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence sync push
+var (
+	syncSpace  string
+	syncRoot   string
+	syncDryRun bool
+	syncPrune  bool
+)
+
+var confluenceSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync Markdown files with Confluence",
+	Long:  "Commands for keeping a directory of Markdown files in sync with Confluence pages",
+}
+
+var confluenceSyncPushCmd = &cobra.Command{
+	Use:   "push <dir>",
+	Short: "Publish a directory of Markdown files as Confluence pages",
+	Long: `Creates or updates one page per Markdown file in <dir> under the --root
+page, keeping the folder hierarchy: a directory's index.md becomes the page
+for that directory (an empty page is created if it has none), and the
+top-level index.md updates the root page itself.
+
+Page titles come from a "title" front matter field or a leading "# Heading",
+and otherwise from the file name. Relative links between files become
+Confluence page links.
+
+The mapping from files to pages is kept in .atl-sync.json in <dir>, and a page
+is only uploaded when its content, title or position has changed.
+
+Use --dry-run to print the plan without changing anything, and --prune to
+archive pages whose source files were deleted. The plan (or the changes
+made) is written to stdout as JSON.`,
+	Example: `  atl-cli confluence sync push ./docs --space ENG --root 12345678 --dry-run
+  atl-cli confluence sync push ./docs --space ENG --root 12345678 --prune`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidateSpaceKey(syncSpace); err != nil {
+			return outputError(httpclient.NewValidationError("--space: " + err.Error()))
+		}
+		if err := confluence.ValidatePageID(syncRoot); err != nil {
+			return outputError(httpclient.NewValidationError("--root: " + err.Error()))
+		}
+		if info, err := os.Stat(args[0]); err != nil || !info.IsDir() {
+			return outputError(httpclient.NewValidationError(args[0] + " is not a directory"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		plan, err := client.SyncPush(context.Background(), confluence.SyncOptions{
+			Dir:      args[0],
+			SpaceKey: syncSpace,
+			RootID:   syncRoot,
+			DryRun:   syncDryRun,
+			Prune:    syncPrune,
+		})
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return plan.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluenceCmd.AddCommand(confluenceSyncCmd)
+	confluenceSyncCmd.AddCommand(confluenceSyncPushCmd)
+
+	confluenceSyncPushCmd.Flags().StringVar(&syncSpace, "space", "", "Space key (required)")
+	confluenceSyncPushCmd.Flags().StringVar(&syncRoot, "root", "", "ID of the page to publish under (required)")
+	confluenceSyncPushCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Print the plan without changing anything")
+	confluenceSyncPushCmd.Flags().BoolVar(&syncPrune, "prune", false, "Archive pages whose source files were deleted")
+	confluenceSyncPushCmd.MarkFlagRequired("space")
+	confluenceSyncPushCmd.MarkFlagRequired("root")
+}
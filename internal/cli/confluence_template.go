@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/martin/atl-cli/internal/config"
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+var confluenceTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Confluence page template commands",
+	Long:  "Commands for working with the page templates used by \"confluence page create --template\"",
+}
+
+var confluenceTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List page templates on the template search path",
+	Long: `Lists the page templates (*.md files) on the template search path as a
+JSON array, with their frontmatter and the variables they use.
+
+The search path is the directories in ATL_CLI_TEMPLATE_PATH, separated like
+PATH. If it is not set, templates are read from .atl/templates in the
+current directory and atl-cli/templates in the user config directory
+(~/.config on Linux). A template in an earlier directory hides one with the
+same name in a later one.`,
+	Example: `  atl-cli confluence template list
+  ATL_CLI_TEMPLATE_PATH=./templates atl-cli confluence template list`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates, err := confluence.ListTemplates(config.TemplatePath())
+		if err != nil {
+			return outputError(&httpclient.ErrorResponse{
+				Error:   httpclient.ErrTypeUnknown,
+				Message: err.Error(),
+			})
+		}
+		return templates.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluenceCmd.AddCommand(confluenceTemplateCmd)
+	confluenceTemplateCmd.AddCommand(confluenceTemplateListCmd)
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence page tree
+var pageTreeDepth int
+
+var confluencePageChildrenCmd = &cobra.Command{
+	Use:   "children <page-id>",
+	Short: "List the direct children of a Confluence page",
+	Long: `Lists the direct children of a page as a JSON array in position order.
+
+Each child includes its id, title, status, position and child count.`,
+	Example: `  atl-cli confluence page children 12345678`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		children, err := client.GetChildren(context.Background(), args[0])
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return children.Write(os.Stdout)
+	},
+}
+
+var confluencePageTreeCmd = &cobra.Command{
+	Use:   "tree <page-id>",
+	Short: "Show a Confluence page tree as nested JSON",
+	Long: `Outputs a page and its descendants as nested JSON, expanded to --depth
+levels below the page (1 lists direct children only).
+
+Pages at the depth limit report hasChildren without a children list, so
+you can continue from them with another tree or children call.`,
+	Example: `  atl-cli confluence page tree 12345678 --depth 2`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if pageTreeDepth < 1 {
+			return outputError(httpclient.NewValidationError("--depth must be at least 1"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		tree, err := client.GetTree(context.Background(), args[0], pageTreeDepth)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return tree.Write(os.Stdout)
+	},
+}
+
+var confluencePageAncestorsCmd = &cobra.Command{
+	Use:   "ancestors <page-id>",
+	Short: "List the ancestors of a Confluence page",
+	Long: `Lists the ancestors of a page as a JSON array (a breadcrumb), starting at
+the top of the space and ending with the page's parent.`,
+	Example: `  atl-cli confluence page ancestors 12345678`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		ancestors, err := client.GetAncestors(context.Background(), args[0])
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return ancestors.Write(os.Stdout)
+	},
+}
+
+func init() {
+	confluencePageCmd.AddCommand(confluencePageChildrenCmd)
+	confluencePageCmd.AddCommand(confluencePageTreeCmd)
+	confluencePageCmd.AddCommand(confluencePageAncestorsCmd)
+
+	confluencePageTreeCmd.Flags().IntVar(&pageTreeDepth, "depth", 3, "Number of levels to expand below the page")
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// Flags for confluence page diff
+var (
+	pageDiffFrom   int
+	pageDiffTo     int
+	pageDiffFormat string
+)
+
+var confluencePageVersionsCmd = &cobra.Command{
+	Use:   "versions <page-id>",
+	Short: "List the version history of a Confluence page",
+	Long: `Lists the versions of a page as a JSON array, newest first, with each
+version's number, author, date, message and whether it was a minor edit.`,
+	Example: `  atl-cli confluence page versions 12345678`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		versions, err := client.ListVersions(context.Background(), args[0])
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		return versions.Write(os.Stdout)
+	},
+}
+
+var confluencePageDiffCmd = &cobra.Command{
+	Use:   "diff <page-id>",
+	Short: "Compare two versions of a Confluence page",
+	Long: `Converts two versions of a page to Markdown and compares them.
+
+The default output is a unified diff. With --format sections, the output is
+JSON listing each section (keyed by its heading) that was added, removed or
+modified, with its Markdown before and after. --to defaults to the current
+version.`,
+	Example: `  atl-cli confluence page diff 12345678 --from 4 --to 6
+  atl-cli confluence page diff 12345678 --from 4 --format sections`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confluence.ValidatePageID(args[0]); err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+		if pageDiffFrom < 1 {
+			return outputError(httpclient.NewValidationError("--from must be a positive version number"))
+		}
+		if pageDiffTo < 0 {
+			return outputError(httpclient.NewValidationError("--to must be a positive version number"))
+		}
+		if pageDiffFormat != "unified" && pageDiffFormat != "sections" {
+			return outputError(httpclient.NewValidationError("invalid format: " + pageDiffFormat + " (valid: unified, sections)"))
+		}
+
+		client, err := newConfluenceClient()
+		if err != nil {
+			return err
+		}
+
+		diff, err := client.DiffPage(context.Background(), args[0], pageDiffFrom, pageDiffTo)
+		if err != nil {
+			return outputError(clientErrorResponse(err))
+		}
+		if pageDiffFormat == "sections" {
+			return diff.Write(os.Stdout)
+		}
+		_, err = fmt.Fprint(os.Stdout, diff.Unified())
+		return err
+	},
+}
+
+func init() {
+	confluencePageCmd.AddCommand(confluencePageVersionsCmd)
+	confluencePageCmd.AddCommand(confluencePageDiffCmd)
+
+	confluencePageDiffCmd.Flags().IntVar(&pageDiffFrom, "from", 0, "Version to compare from (required)")
+	confluencePageDiffCmd.Flags().IntVar(&pageDiffTo, "to", 0, "Version to compare to (default: current version)")
+	confluencePageDiffCmd.Flags().StringVar(&pageDiffFormat, "format", "unified", "Output format: unified, sections")
+	confluencePageDiffCmd.MarkFlagRequired("from")
+}
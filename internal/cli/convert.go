@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/confluence"
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/martin/atl-cli/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+// Flags for convert
+var (
+	convertFrom string
+	convertTo   string
+)
+
+// Warning is a non-fatal notice written to stderr as JSON.
+type Warning struct {
+	Warning string `json:"warning"`
+	Message string `json:"message"`
+}
+
+// converter transforms input in one format into output in another, returning
+// notes about anything that could not be represented in the target format.
+type converter func(input string) (output string, warnings []string, err error)
+
+// conversionOrder lists the supported conversions for help and error messages.
+var conversionOrder = []string{"markdown→adf", "markdown→storage", "adf→markdown", "storage→markdown", "storage→text"}
+
+// converters maps each supported conversion, keyed by "from→to".
+var converters = map[string]converter{
+	"markdown→adf":     convertMarkdownToADF,
+	"markdown→storage": convertMarkdownToStorage,
+	"adf→markdown":     convertADFToMarkdown,
+	"storage→markdown": convertStorageToMarkdown,
+	"storage→text":     convertStorageToText,
+}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [file]",
+	Short: "Convert between Markdown, ADF, Confluence storage format and plain text",
+	Long: `Converts a document between formats without contacting Atlassian.
+
+Reads from the given file, or from stdin when no file (or "-") is given, and
+writes the result to stdout. Supported conversions:
+
+  --from markdown --to adf
+  --from markdown --to storage
+  --from adf      --to markdown
+  --from storage  --to markdown
+  --from storage  --to text
+
+Content that cannot be represented in the target format is reported as JSON
+warnings on stderr.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		convert, ok := converters[convertFrom+"→"+convertTo]
+		if !ok {
+			return outputError(httpclient.NewValidationError(
+				fmt.Sprintf("unsupported conversion %q to %q (valid: %s)",
+					convertFrom, convertTo, validConversions())))
+		}
+
+		input, err := readInput(args)
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		output, warnings, err := convert(string(input))
+		if err != nil {
+			if errResp := adfErrorResponse(err); errResp != nil {
+				return outputError(errResp)
+			}
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
+		for _, w := range warnings {
+			writeWarning(os.Stderr, "lossy_conversion", w)
+		}
+
+		if _, err := io.WriteString(os.Stdout, output); err != nil {
+			return err
+		}
+		_, err = io.WriteString(os.Stdout, "\n")
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().StringVar(&convertFrom, "from", "", "Input format: markdown, adf, storage")
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "Output format: markdown, adf, storage, text")
+	convertCmd.MarkFlagRequired("from")
+	convertCmd.MarkFlagRequired("to")
+}
+
+// readInput reads the file named by args[0], or stdin if no file or "-" is given.
+func readInput(args []string) ([]byte, error) {
+	if len(args) == 0 || args[0] == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file: %w", err)
+	}
+	return data, nil
+}
+
+// writeWarning writes a warning as JSON to w.
+func writeWarning(w io.Writer, kind, message string) {
+	encoder := json.NewEncoder(w)
+	encoder.Encode(&Warning{Warning: kind, Message: message})
+}
+
+func validConversions() string {
+	names := make([]string, 0, len(conversionOrder))
+	for _, pair := range conversionOrder {
+		names = append(names, strings.Replace(pair, "→", " to ", 1))
+	}
+	return strings.Join(names, ", ")
+}
+
+func convertMarkdownToADF(input string) (string, []string, error) {
+	doc := jira.TextToADF(input)
+	if doc == nil {
+		doc = &jira.ADFDoc{Type: "doc", Version: 1, Content: []jira.ADFNode{}}
+	}
+	if err := jira.ValidateADF(doc); err != nil {
+		return "", nil, err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+	return string(data), jira.MarkdownWarnings(input), nil
+}
+
+func convertMarkdownToStorage(input string) (string, []string, error) {
+	return confluence.MarkdownToStorage(input), confluence.MarkdownWarnings(input), nil
+}
+
+func convertADFToMarkdown(input string) (string, []string, error) {
+	var doc jira.ADFDoc
+	if err := json.Unmarshal([]byte(input), &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse ADF document: %w", err)
+	}
+	if err := jira.ValidateADF(&doc); err != nil {
+		return "", nil, err
+	}
+	markdown, warnings := jira.ADFToMarkdown(&doc)
+	return markdown, warnings, nil
+}
+
+func convertStorageToMarkdown(input string) (string, []string, error) {
+	markdown, err := confluence.ToMarkdown(input)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert storage format: %w", err)
+	}
+	return markdown, confluence.StorageWarnings(input), nil
+}
+
+func convertStorageToText(input string) (string, []string, error) {
+	text, err := confluence.ToPlainText(input)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert storage format: %w", err)
+	}
+	return text, confluence.StorageWarnings(input), nil
+}
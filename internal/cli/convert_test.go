@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/jira"
+)
+
+func TestConverters_MarkdownToADF(t *testing.T) {
+	output, warnings, err := converters["markdown→adf"]("# Title\n\n> quoted")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc jira.ADFDoc
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if doc.Type != "doc" || len(doc.Content) != 2 {
+		t.Errorf("unexpected document: %s", output)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "blockquotes") {
+		t.Errorf("expected blockquote warning, got %v", warnings)
+	}
+}
+
+func TestConverters_ADFToMarkdown_Invalid(t *testing.T) {
+	_, _, err := converters["adf→markdown"](`{"type":"doc","version":1,"content":[{"type":"heading"}]}`)
+	if errResp := adfErrorResponse(err); errResp == nil || errResp.Path != "/content/0/attrs" {
+		t.Errorf("expected ADF validation error at /content/0/attrs, got %v", err)
+	}
+
+	_, _, err = converters["adf→markdown"](`not json`)
+	if err == nil || adfErrorResponse(err) != nil {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestConverters_AllListed(t *testing.T) {
+	if len(conversionOrder) != len(converters) {
+		t.Fatalf("conversionOrder has %d entries, converters has %d", len(conversionOrder), len(converters))
+	}
+	for _, pair := range conversionOrder {
+		if converters[pair] == nil {
+			t.Errorf("conversion %q has no converter", pair)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"errors"
+	"os"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/martin/atl-cli/internal/jira"
+)
+
+// adfErrorResponse returns a validation error pointing at the offending node
+// if err is an ADF validation failure, or nil otherwise.
+func adfErrorResponse(err error) *httpclient.ErrorResponse {
+	var adfErr *jira.ADFValidationError
+	if !errors.As(err, &adfErr) {
+		return nil
+	}
+	return &httpclient.ErrorResponse{
+		Error:   httpclient.ErrTypeValidation,
+		Message: adfErr.Error(),
+		Path:    adfErr.Path,
+	}
+}
+
+// clientErrorResponse converts an error returned by an API client into an
+// error response, keeping the error type of structured API errors.
+func clientErrorResponse(err error) *httpclient.ErrorResponse {
+	if errResp := adfErrorResponse(err); errResp != nil {
+		return errResp
+	}
+	var apiErr *httpclient.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Response
+	}
+	return &httpclient.ErrorResponse{
+		Error:   httpclient.ErrTypeUnknown,
+		Message: err.Error(),
+	}
+}
+
+// outputError writes an error response to stderr and returns an error to signal non-zero exit
+func outputError(errResp *httpclient.ErrorResponse) error {
+	errResp.Write(os.Stderr)
+	return &exitError{code: 1}
+}
+
+// exitError is used to signal a non-zero exit code
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return ""
+}
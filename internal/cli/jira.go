@@ -25,13 +25,15 @@ var jiraIssueCmd = &cobra.Command{
 }
 
 var jiraIssueGetCmd = &cobra.Command{
-	Use:   "get <issue-key>",
-	Short: "Get a Jira issue by key",
-	Long:  "Retrieves details of a Jira issue and outputs as JSON",
-	Args:  cobra.ExactArgs(1),
+	Use:   "get <issue-key|url>",
+	Short: "Get a Jira issue by key or URL",
+	Long: `Retrieves details of a Jira issue and outputs as JSON.
+
+The issue can be given by key or by a link to it on the configured site.`,
+	Example: `  atl-cli jira issue get PROJ-123
+  atl-cli jira issue get "https://acme.atlassian.net/browse/PROJ-123?focusedCommentId=10001"`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		issueKey := args[0]
-
 		// Load and validate config
 		cfg, err := config.LoadFromEnv()
 		if err != nil {
@@ -41,6 +43,11 @@ var jiraIssueGetCmd = &cobra.Command{
 			return outputError(httpclient.NewConfigError(err.Error()))
 		}
 
+		issueKey, err := jira.ParseIssueRef(args[0], cfg)
+		if err != nil {
+			return outputError(httpclient.NewValidationError(err.Error()))
+		}
+
 		// Create client with debug flag from root command
 		client := jira.NewClient(cfg, debug)
 
@@ -197,6 +204,10 @@ var jiraIssueCreateCmd = &cobra.Command{
 		client := jira.NewClient(cfg, debug)
 		created, err := client.CreateIssue(context.Background(), req)
 		if err != nil {
+			// Malformed description documents are reported with their location
+			if errResp := adfErrorResponse(err); errResp != nil {
+				return outputError(errResp)
+			}
 			return outputError(&httpclient.ErrorResponse{
 				Error:   httpclient.ErrTypeUnknown,
 				Message: err.Error(),
@@ -224,18 +235,3 @@ func init() {
 	jiraIssueCreateCmd.Flags().StringVar(&createTemplate, "template", "", "Path to template file")
 	jiraIssueCreateCmd.Flags().StringArrayVar(&createVars, "var", nil, "Template variable (key=value), repeatable")
 }
-
-// outputError writes an error response to stderr and returns an error to signal non-zero exit
-func outputError(errResp *httpclient.ErrorResponse) error {
-	errResp.Write(os.Stderr)
-	return &exitError{code: 1}
-}
-
-// exitError is used to signal a non-zero exit code
-type exitError struct {
-	code int
-}
-
-func (e *exitError) Error() string {
-	return ""
-}
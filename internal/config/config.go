@@ -2,7 +2,11 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // Config holds Atlassian API configuration
@@ -17,6 +21,10 @@ const (
 	EnvSite  = "ATL_CLI_SITE"
 	EnvEmail = "ATL_CLI_EMAIL"
 	EnvToken = "ATL_CLI_TOKEN"
+
+	// EnvTemplatePath lists directories searched for page templates,
+	// separated like PATH.
+	EnvTemplatePath = "ATL_CLI_TEMPLATE_PATH"
 )
 
 // LoadFromEnv loads configuration from environment variables
@@ -30,6 +38,27 @@ func LoadFromEnv() (*Config, error) {
 	return cfg, nil
 }
 
+// TemplatePath returns the directories searched for templates, in order:
+// those in ATL_CLI_TEMPLATE_PATH if it is set, otherwise .atl/templates in
+// the working directory and atl-cli/templates in the user config directory.
+func TemplatePath() []string {
+	if env := os.Getenv(EnvTemplatePath); env != "" {
+		var dirs []string
+		for _, dir := range filepath.SplitList(env) {
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		return dirs
+	}
+
+	dirs := []string{filepath.Join(".atl", "templates")}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(configDir, "atl-cli", "templates"))
+	}
+	return dirs
+}
+
 // Validate checks that all required configuration is present
 func (c *Config) Validate() error {
 	if c.Site == "" {
@@ -48,3 +77,29 @@ func (c *Config) Validate() error {
 func (c *Config) BaseURL() string {
 	return "https://" + c.Site
 }
+
+// ParseSiteURL parses a link pasted by a user, such as a page or issue URL,
+// and checks that it points at the configured site. A missing scheme is
+// taken to be https; any other scheme is rejected. Site-relative links, such
+// as /browse/PROJ-1, are resolved against the configured site.
+func (c *Config) ParseSiteURL(raw string) (*url.URL, error) {
+	switch {
+	case strings.HasPrefix(raw, "//"):
+		raw = "https:" + raw
+	case strings.HasPrefix(raw, "/"):
+		raw = c.BaseURL() + raw
+	case !strings.Contains(raw, "://"):
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid URL %q: only https URLs are supported", raw)
+	}
+	if !strings.EqualFold(u.Host, c.Site) {
+		return nil, fmt.Errorf("URL host %q does not match the configured site %q (%s)", u.Host, c.Site, EnvSite)
+	}
+	return u, nil
+}
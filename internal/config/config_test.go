@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -160,3 +161,53 @@ func TestLoadFromEnv_EmptyEnv(t *testing.T) {
 		t.Error("expected Validate to fail with empty config")
 	}
 }
+
+func TestConfig_ParseSiteURL(t *testing.T) {
+	cfg := &Config{Site: "acme.atlassian.net"}
+
+	tests := []struct {
+		name    string
+		raw     string
+		path    string
+		wantErr string
+	}{
+		{name: "https URL", raw: "https://acme.atlassian.net/browse/PROJ-1", path: "/browse/PROJ-1"},
+		{name: "host case", raw: "https://ACME.atlassian.net/wiki/x/AbCd", path: "/wiki/x/AbCd"},
+		{name: "no scheme", raw: "acme.atlassian.net/wiki/x/AbCd", path: "/wiki/x/AbCd"},
+		{name: "site-relative", raw: "/browse/PROJ-1?focusedCommentId=10001", path: "/browse/PROJ-1"},
+		{name: "protocol-relative other site", raw: "//other.atlassian.net/browse/PROJ-1", wantErr: "does not match the configured site"},
+		{name: "other site", raw: "https://other.atlassian.net/browse/PROJ-1", wantErr: "does not match the configured site"},
+		{name: "http", raw: "http://acme.atlassian.net/browse/PROJ-1", wantErr: "only https"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := cfg.ParseSiteURL(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u.Path != tt.path {
+				t.Errorf("path = %q, want %q", u.Path, tt.path)
+			}
+		})
+	}
+}
+
+func TestTemplatePath(t *testing.T) {
+	t.Setenv(EnvTemplatePath, strings.Join([]string{"/srv/templates", "", "team"}, string(os.PathListSeparator)))
+	got := TemplatePath()
+	if len(got) != 2 || got[0] != "/srv/templates" || got[1] != "team" {
+		t.Errorf("unexpected search path: %q", got)
+	}
+
+	t.Setenv(EnvTemplatePath, "")
+	got = TemplatePath()
+	if len(got) == 0 || got[0] != filepath.Join(".atl", "templates") {
+		t.Errorf("unexpected default search path: %q", got)
+	}
+}
@@ -0,0 +1,147 @@
+package confluence
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/jira"
+)
+
+// Body formats, as named by the v2 API's body-format parameter.
+const (
+	BodyFormatStorage = "storage"
+	BodyFormatADF     = "atlas_doc_format"
+	BodyFormatView    = "view"
+)
+
+// ValidateBodyFormat checks that format is a body format pages can be
+// read in.
+func ValidateBodyFormat(format string) error {
+	switch format {
+	case BodyFormatStorage, BodyFormatADF, BodyFormatView:
+		return nil
+	}
+	return fmt.Errorf("invalid body format %q (valid: %s, %s, %s)", format, BodyFormatStorage, BodyFormatADF, BodyFormatView)
+}
+
+// adfPanelTypes maps Confluence admonition macros to ADF panel types.
+var adfPanelTypes = map[string]string{
+	"info":    "info",
+	"note":    "note",
+	"tip":     "success",
+	"warning": "warning",
+}
+
+// ADFToMarkdown converts an ADF document, as JSON, to Markdown with the
+// renderer used for Jira content. The second return value lists content
+// that could not be represented in Markdown, such as macros.
+func ADFToMarkdown(body string) (string, []string, error) {
+	if strings.TrimSpace(body) == "" {
+		return "", nil, nil
+	}
+	var doc jira.ADFDoc
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse ADF body: %w", err)
+	}
+	markdown, warnings := jira.ADFToMarkdown(&doc)
+	return markdown, warnings, nil
+}
+
+// MarkdownToADF converts Markdown to an ADF document, producing the same
+// constructs as MarkdownToStorage: admonition blockquotes become panels and
+// <details> sections become expands. Content that ADF does not allow where
+// it appears is adapted: expands inside expands become nested expands, and
+// in panels and blockquotes, headings become bold paragraphs and other
+// containers are replaced by their content.
+func MarkdownToADF(markdown string) *jira.ADFDoc {
+	content := markdownToADFNodes(markdown)
+	if content == nil {
+		content = []jira.ADFNode{}
+	}
+	return &jira.ADFDoc{Type: "doc", Version: 1, Content: content}
+}
+
+// markdownToADFNodes converts Markdown to block-level ADF nodes.
+func markdownToADFNodes(markdown string) []jira.ADFNode {
+	var nodes []jira.ADFNode
+	for _, block := range splitMarkdownBlocks(markdown) {
+		body := strings.Join(block.lines, "\n")
+		switch block.kind {
+		case "quote":
+			nodes = append(nodes, quoteToADF(block.lines))
+		case "details":
+			expand := jira.ADFNode{Type: "expand", Content: fitADFContent("expand", markdownToADFNodes(body))}
+			if block.title != "" {
+				expand.Attrs = map[string]interface{}{"title": block.title}
+			}
+			nodes = append(nodes, expand)
+		default:
+			nodes = append(nodes, jira.ParseMarkdownToADFNodes(body)...)
+		}
+	}
+	return nodes
+}
+
+// quoteToADF converts blockquote lines (with the ">" markers removed) to a
+// panel if the first line carries an admonition label, or a blockquote.
+func quoteToADF(lines []string) jira.ADFNode {
+	macro, lines := splitAdmonition(lines)
+	content := markdownToADFNodes(strings.Join(lines, "\n"))
+	if macro == "" {
+		return jira.ADFNode{Type: "blockquote", Content: fitADFContent("blockquote", content)}
+	}
+	return jira.ADFNode{
+		Type:    "panel",
+		Attrs:   map[string]interface{}{"panelType": adfPanelTypes[macro]},
+		Content: fitADFContent("panel", content),
+	}
+}
+
+// fitADFContent adapts the children of a parent node to what ADF allows
+// in it. An expand becomes a nestedExpand where that is allowed, a heading
+// becomes a bold paragraph, and any other container that is not allowed,
+// including an expand that cannot nest, is replaced by its content, after
+// the expand's title as a bold paragraph.
+func fitADFContent(parent string, nodes []jira.ADFNode) []jira.ADFNode {
+	var fitted []jira.ADFNode
+	for _, node := range nodes {
+		if jira.ADFAllowsChild(parent, node.Type) {
+			fitted = append(fitted, node)
+			continue
+		}
+		switch {
+		case node.Type == "expand" && jira.ADFAllowsChild(parent, "nestedExpand"):
+			node.Type = "nestedExpand"
+			node.Content = fitADFContent("nestedExpand", node.Content)
+			fitted = append(fitted, node)
+		case node.Type == "heading" && jira.ADFAllowsChild(parent, "paragraph"):
+			fitted = append(fitted, jira.ADFNode{Type: "paragraph", Content: boldADFText(node.Content)})
+		case len(node.Content) > 0:
+			if title, _ := node.Attrs["title"].(string); title != "" && jira.ADFAllowsChild(parent, "paragraph") {
+				fitted = append(fitted, jira.ADFNode{Type: "paragraph", Content: boldADFText([]jira.ADFNode{{Type: "text", Text: title}})})
+			}
+			fitted = append(fitted, fitADFContent(parent, node.Content)...)
+		default:
+			fitted = append(fitted, node)
+		}
+	}
+	return fitted
+}
+
+// boldADFText returns inline nodes with a strong mark added to their text.
+// Code text is left alone, since ADF does not combine code with strong.
+func boldADFText(nodes []jira.ADFNode) []jira.ADFNode {
+	bold := make([]jira.ADFNode, len(nodes))
+	for i, node := range nodes {
+		bold[i] = node
+		if node.Type != "text" || slices.ContainsFunc(node.Marks, func(m jira.ADFMark) bool {
+			return m.Type == "code" || m.Type == "strong"
+		}) {
+			continue
+		}
+		bold[i].Marks = append(slices.Clone(node.Marks), jira.ADFMark{Type: "strong"})
+	}
+	return bold
+}
@@ -0,0 +1,259 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/jira"
+)
+
+func TestMarkdownToADF(t *testing.T) {
+	markdown := "# Runbook\n\n> **Warning:** Page the on-call first.\n\n> Just a quote\n\n" +
+		"<details><summary>Logs</summary>\n\nSee `journalctl`.\n\n</details>\n\n```go\nfmt.Println()\n```"
+	doc := MarkdownToADF(markdown)
+
+	if err := jira.ValidateADF(doc); err != nil {
+		t.Fatalf("document does not match the ADF schema: %v", err)
+	}
+
+	var types []string
+	for _, node := range doc.Content {
+		types = append(types, node.Type)
+	}
+	if got := strings.Join(types, ","); got != "heading,panel,blockquote,expand,codeBlock" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+	if doc.Content[1].Attrs["panelType"] != "warning" {
+		t.Errorf("unexpected panel attrs: %v", doc.Content[1].Attrs)
+	}
+	if doc.Content[3].Attrs["title"] != "Logs" {
+		t.Errorf("unexpected expand attrs: %v", doc.Content[3].Attrs)
+	}
+
+	// The panel text does not keep the label
+	data, _ := json.Marshal(doc.Content[1])
+	if strings.Contains(string(data), "Warning:") || !strings.Contains(string(data), "Page the on-call first.") {
+		t.Errorf("unexpected panel content: %s", data)
+	}
+}
+
+// adfOutline describes the block structure of ADF nodes, such as
+// "expand(paragraph)", with bold paragraphs marked by a "*".
+func adfOutline(nodes []jira.ADFNode) string {
+	var parts []string
+	for _, node := range nodes {
+		part := node.Type
+		if node.Type == "paragraph" && len(node.Content) > 0 && len(node.Content[0].Marks) > 0 && node.Content[0].Marks[len(node.Content[0].Marks)-1].Type == "strong" {
+			part += "*"
+		}
+		if node.Type != "paragraph" && node.Type != "heading" && len(node.Content) > 0 {
+			part += "(" + adfOutline(node.Content) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestMarkdownToADF_Nesting(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "details in details",
+			markdown: "<details><summary>Outer</summary>\n\nA\n\n<details><summary>Inner</summary>\n\nB\n\n</details>\n\n</details>",
+			want:     "expand(paragraph,nestedExpand(paragraph))",
+		},
+		{
+			name: "details three deep",
+			markdown: "<details><summary>One</summary>\n\n<details><summary>Two</summary>\n\n" +
+				"<details><summary>Three</summary>\n\nC\n\n</details>\n\n</details>\n\n</details>",
+			want: "expand(nestedExpand(paragraph*,paragraph))",
+		},
+		{
+			name:     "details in an admonition",
+			markdown: "> **Note:** Heads up\n>\n> <details><summary>More</summary>\n>\n> Hidden\n>\n> </details>",
+			want:     "panel(paragraph,paragraph*,paragraph)",
+		},
+		{
+			name:     "quote in a quote",
+			markdown: "> Outer\n>\n> > Inner",
+			want:     "blockquote(paragraph,paragraph)",
+		},
+		{
+			name:     "heading in a quote",
+			markdown: "> ## Title\n>\n> Text",
+			want:     "blockquote(paragraph*,paragraph)",
+		},
+		{
+			name:     "heading and quote in an admonition",
+			markdown: "> [!TIP]\n> ## Title\n>\n> > Quoted",
+			want:     "panel(heading,paragraph)",
+		},
+		{
+			name:     "admonition in details",
+			markdown: "<details><summary>More</summary>\n\n> **Warning:** Careful\n\n</details>",
+			want:     "expand(panel(paragraph))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := MarkdownToADF(tt.markdown)
+			if err := jira.ValidateADF(doc); err != nil {
+				t.Errorf("document does not match the ADF schema: %v", err)
+			}
+			if got := adfOutline(doc.Content); got != tt.want {
+				t.Errorf("structure = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToADF_Empty(t *testing.T) {
+	doc := MarkdownToADF("")
+	if doc.Type != "doc" || doc.Content == nil || len(doc.Content) != 0 {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+}
+
+func TestADFToMarkdown(t *testing.T) {
+	body := `{"type":"doc","version":1,"content":[` +
+		`{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Plan"}]},` +
+		`{"type":"paragraph","content":[{"type":"text","text":"Ship ","marks":[{"type":"strong"}]},{"type":"text","text":"it"}]}]}`
+	got, _, err := ADFToMarkdown(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "## Plan\n\n**Ship **it" && got != "## Plan\n\n**Ship** it" {
+		t.Errorf("unexpected markdown: %q", got)
+	}
+
+	if _, _, err := ADFToMarkdown("not json"); err == nil {
+		t.Error("expected error for invalid ADF")
+	}
+}
+
+func TestPage_Warnings(t *testing.T) {
+	page := &Page{
+		BodyFormat: BodyFormatADF,
+		Body:       `{"type":"doc","version":1,"content":[{"type":"blockCard","attrs":{}},{"type":"paragraph","content":[{"type":"text","text":"Kept"}]}]}`,
+	}
+	if _, err := page.Markdown(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if warnings := page.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "blockCard") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	// The warnings are those of the last conversion
+	page.Body = `{"type":"doc","version":1,"content":[]}`
+	if _, err := page.Markdown(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if warnings := page.Warnings(); len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestValidateBodyFormat(t *testing.T) {
+	for _, format := range []string{"storage", "atlas_doc_format", "view"} {
+		if err := ValidateBodyFormat(format); err != nil {
+			t.Errorf("%s: unexpected error: %v", format, err)
+		}
+	}
+	if err := ValidateBodyFormat("wiki"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestClient_GetPageFormat(t *testing.T) {
+	adf := `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Hello from ADF"}]}]}`
+	tests := []struct {
+		format   string
+		body     string
+		markdown string
+	}{
+		{format: "atlas_doc_format", body: adf, markdown: "Hello from ADF"},
+		{format: "view", body: `<p>Hello <strong>view</strong></p>`, markdown: "Hello **view**"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/wiki/api/v2/pages/1":
+					if got := r.URL.Query().Get("body-format"); got != tt.format {
+						t.Errorf("expected body-format=%s, got %s", tt.format, got)
+					}
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"id": "1", "title": "Hello", "spaceId": "9",
+						"version": map[string]int{"number": 2},
+						"body":    map[string]interface{}{tt.format: map[string]string{"value": tt.body}},
+					})
+				case "/wiki/api/v2/spaces/9":
+					w.Write([]byte(`{"id": "9", "key": "ENG"}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			page, err := newTestClient(server).GetPageFormat(context.Background(), "1", 0, tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if page.BodyFormat != tt.format || page.Body != tt.body {
+				t.Errorf("unexpected page: %+v", page)
+			}
+			got, err := page.Markdown()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.markdown {
+				t.Errorf("markdown = %q, want %q", got, tt.markdown)
+			}
+		})
+	}
+}
+
+func TestClient_CreatePage_ADF(t *testing.T) {
+	var sent createPageRequest
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wiki/api/v2/spaces":
+			w.Write([]byte(`{"results": [{"id": "9", "key": "ENG"}]}`))
+		case "/wiki/api/v2/pages":
+			data, _ := io.ReadAll(r.Body)
+			json.Unmarshal(data, &sent)
+			w.Write([]byte(`{"id": "5", "title": "New", "version": {"number": 1}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	body, _ := json.Marshal(MarkdownToADF("> [!TIP]\n> Use ADF"))
+	_, err := client.CreatePage(context.Background(), &NewPage{SpaceKey: "ENG", Title: "New", Body: string(body), BodyFormat: BodyFormatADF})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.Body.Representation != "atlas_doc_format" || !strings.Contains(sent.Body.Value, `"panelType":"success"`) {
+		t.Errorf("unexpected request body: %+v", sent.Body)
+	}
+
+	// Documents that do not match the schema are rejected before sending
+	_, err = client.CreatePage(context.Background(), &NewPage{SpaceKey: "ENG", Title: "New", Body: `{"type":"doc","version":1,"content":[{"type":"bogus"}]}`, BodyFormat: BodyFormatADF})
+	var adfErr *jira.ADFValidationError
+	if err == nil || !errors.As(err, &adfErr) {
+		t.Errorf("expected ADF validation error, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Attachment represents a file attached to a Confluence page.
+type Attachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	MediaType   string `json:"mediaType"`
+	FileSize    int64  `json:"fileSize"`
+	Version     int    `json:"version"`
+	DownloadURL string `json:"downloadUrl"`
+	// Path is set when the attachment was downloaded to a local file.
+	Path string `json:"path,omitempty"`
+
+	// DownloadLink is the download path relative to the wiki base.
+	DownloadLink string `json:"-"`
+}
+
+// AttachmentList is a list of attachments written as a JSON array.
+type AttachmentList []*Attachment
+
+// Write writes the attachments as a JSON array to the given writer.
+func (l AttachmentList) Write(w interface{ Write([]byte) (int, error) }) error {
+	if l == nil {
+		l = AttachmentList{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// apiAttachmentListResponse represents a page of v2 attachments.
+type apiAttachmentListResponse struct {
+	Results []struct {
+		ID           string `json:"id"`
+		Title        string `json:"title"`
+		MediaType    string `json:"mediaType"`
+		FileSize     int64  `json:"fileSize"`
+		DownloadLink string `json:"downloadLink"`
+		Version      struct {
+			Number int `json:"number"`
+		} `json:"version"`
+	} `json:"results"`
+}
+
+// apiContentAttachment is an attachment as returned by the v1 content API.
+type apiContentAttachment struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Extensions struct {
+		MediaType string `json:"mediaType"`
+		FileSize  int64  `json:"fileSize"`
+	} `json:"extensions"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		Download string `json:"download"`
+	} `json:"_links"`
+}
+
+// ListAttachments returns all attachments of a page.
+func (c *Client) ListAttachments(ctx context.Context, pageID string) (AttachmentList, error) {
+	if err := ValidatePageID(pageID); err != nil {
+		return nil, err
+	}
+
+	attachments := AttachmentList{}
+	path := fmt.Sprintf("/wiki/api/v2/pages/%s/attachments?limit=250", pageID)
+	err := c.getPaged(ctx, path, func(body []byte) error {
+		var resp apiAttachmentListResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, r := range resp.Results {
+			attachments = append(attachments, &Attachment{
+				ID:           r.ID,
+				Filename:     r.Title,
+				MediaType:    r.MediaType,
+				FileSize:     r.FileSize,
+				Version:      r.Version.Number,
+				DownloadURL:  c.downloadURL(r.DownloadLink),
+				DownloadLink: r.DownloadLink,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DownloadAttachment streams the content of an attachment to w.
+func (c *Client) DownloadAttachment(ctx context.Context, att *Attachment, w io.Writer) error {
+	if att.DownloadLink == "" {
+		return fmt.Errorf("attachment %s has no download link", att.ID)
+	}
+
+	// Download links are relative to the wiki base
+	req, err := c.httpClient.NewRequest(ctx, "GET", c.downloadURL(att.DownloadLink), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := c.httpClient.DoTransfer(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("request timed out")
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.handleError(resp)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download attachment %s: %w", att.Filename, err)
+	}
+	return nil
+}
+
+// downloadURL returns the absolute URL of a download link.
+func (c *Client) downloadURL(link string) string {
+	if link == "" {
+		return ""
+	}
+	// Download links are relative to the wiki base
+	return c.cfg.BaseURL() + "/wiki" + link
+}
+
+// DownloadAttachments downloads every attachment of a page into dir,
+// streaming each one to disk, and returns the attachments with their local
+// paths set. Existing files with the same name are replaced.
+func (c *Client) DownloadAttachments(ctx context.Context, pageID, dir string) (AttachmentList, error) {
+	attachments, err := c.ListAttachments(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, att := range attachments {
+		name, err := attachmentFileName(att.Filename)
+		if err != nil {
+			return nil, err
+		}
+		dest := filepath.Join(dir, name)
+
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(c.DownloadAttachment(ctx, att, pw))
+		}()
+		err = writeFileAtomic(dest, pr)
+		pr.Close()
+		if err != nil {
+			return nil, err
+		}
+		att.Path = dest
+	}
+	return attachments, nil
+}
+
+// attachmentFileName returns a safe local file name for an attachment,
+// rejecting names that would escape the download directory.
+func attachmentFileName(name string) (string, error) {
+	base := filepath.Base(filepath.FromSlash(name))
+	if name == "" || base != name || base == "." || base == ".." || strings.ContainsRune(name, '\\') {
+		return "", fmt.Errorf("attachment %q has an unsafe file name", name)
+	}
+	return base, nil
+}
+
+// UploadAttachment attaches a file to a page, streaming it as a multipart
+// upload. If the page already has an attachment with the same name, a new
+// version of that attachment is created.
+func (c *Client) UploadAttachment(ctx context.Context, pageID, name string, r io.Reader) (*Attachment, error) {
+	if err := ValidatePageID(pageID); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", name)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = mw.WriteField("minorEdit", "false")
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	defer pr.Close()
+
+	// PUT creates the attachment, or a new version if the name is taken
+	reqURL := fmt.Sprintf("%s/wiki/rest/api/content/%s/child/attachment", c.cfg.BaseURL(), url.PathEscape(pageID))
+	req, err := c.httpClient.NewRequest(ctx, "PUT", reqURL, pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("X-Atlassian-Token", "nocheck")
+
+	resp, err := c.httpClient.DoTransfer(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("request timed out")
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// New attachments come back as a list, new versions as a single item
+	var list struct {
+		Results []apiContentAttachment `json:"results"`
+	}
+	var result apiContentAttachment
+	if err := json.Unmarshal(body, &list); err == nil && len(list.Results) > 0 {
+		result = list.Results[0]
+	} else if err := json.Unmarshal(body, &result); err != nil || result.ID == "" {
+		return nil, fmt.Errorf("failed to parse response")
+	}
+
+	return &Attachment{
+		ID:           result.ID,
+		Filename:     result.Title,
+		MediaType:    result.Extensions.MediaType,
+		FileSize:     result.Extensions.FileSize,
+		Version:      result.Version.Number,
+		DownloadURL:  c.downloadURL(result.Links.Download),
+		DownloadLink: result.Links.Download,
+	}, nil
+}
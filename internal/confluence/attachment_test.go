@@ -0,0 +1,165 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_ListAttachments(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wiki/api/v2/pages/123/attachments" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{{
+				"id": "att1", "title": "spec.pdf", "mediaType": "application/pdf", "fileSize": 2048,
+				"downloadLink": "/download/attachments/123/spec.pdf?version=2",
+				"version":      map[string]int{"number": 2},
+			}},
+		})
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	attachments, err := client.ListAttachments(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(attachments))
+	}
+	att := attachments[0]
+	if att.Filename != "spec.pdf" || att.MediaType != "application/pdf" || att.FileSize != 2048 || att.Version != 2 {
+		t.Errorf("unexpected attachment: %+v", att)
+	}
+	if want := server.URL + "/wiki/download/attachments/123/spec.pdf?version=2"; att.DownloadURL != want {
+		t.Errorf("expected download URL %s, got %s", want, att.DownloadURL)
+	}
+}
+
+func TestClient_DownloadAttachments(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wiki/api/v2/pages/123/attachments":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]interface{}{
+					{"id": "att1", "title": "a.txt", "downloadLink": "/download/attachments/123/a.txt"},
+					{"id": "att2", "title": "b.txt", "downloadLink": "/download/attachments/123/b.txt"},
+				},
+			})
+		case "/wiki/download/attachments/123/a.txt":
+			w.Write([]byte("first"))
+		case "/wiki/download/attachments/123/b.txt":
+			w.Write([]byte("second"))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	dir := t.TempDir()
+	attachments, err := client.DownloadAttachments(context.Background(), "123", dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, att := range attachments {
+		if att.Path != filepath.Join(dir, att.Filename) {
+			t.Errorf("unexpected path for %s: %s", att.Filename, att.Path)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "b.txt")); string(data) != "second" {
+		t.Errorf("unexpected content of b.txt: %q", data)
+	}
+}
+
+func TestAttachmentFileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"diagram.png", false},
+		{"release notes v2.pdf", false},
+		{"../secret", true},
+		{"dir/file.txt", true},
+		{`dir\file.txt`, true},
+		{"..", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := attachmentFileName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("attachmentFileName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_UploadAttachment(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		version  int
+	}{
+		{
+			name:     "new attachment",
+			response: `{"results": [{"id": "att9", "title": "spec.pdf", "extensions": {"mediaType": "application/pdf", "fileSize": 5}, "version": {"number": 1}, "_links": {"download": "/download/attachments/123/spec.pdf"}}]}`,
+			version:  1,
+		},
+		{
+			name:     "new version of an existing attachment",
+			response: `{"id": "att9", "title": "spec.pdf", "extensions": {"mediaType": "application/pdf", "fileSize": 5}, "version": {"number": 3}, "_links": {"download": "/download/attachments/123/spec.pdf"}}`,
+			version:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "PUT" || r.URL.Path != "/wiki/rest/api/content/123/child/attachment" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("X-Atlassian-Token") != "nocheck" {
+					t.Error("expected X-Atlassian-Token: nocheck")
+				}
+				file, header, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("expected multipart file: %v", err)
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				data, _ := io.ReadAll(file)
+				if header.Filename != "spec.pdf" || string(data) != "%PDF-" {
+					t.Errorf("unexpected upload %s: %q", header.Filename, data)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			client := newTestClient(server)
+
+			att, err := client.UploadAttachment(context.Background(), "123", "spec.pdf", strings.NewReader("%PDF-"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if att.ID != "att9" || att.Filename != "spec.pdf" || att.Version != tt.version || att.FileSize != 5 {
+				t.Errorf("unexpected attachment: %+v", att)
+			}
+			if !strings.HasSuffix(att.DownloadURL, "/wiki/download/attachments/123/spec.pdf") {
+				t.Errorf("unexpected download URL: %s", att.DownloadURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// PageList is a list of pages or blog posts written as a JSON array.
+type PageList []*Page
+
+// Write writes the pages as a JSON array to the given writer.
+func (l PageList) Write(w interface{ Write([]byte) (int, error) }) error {
+	if l == nil {
+		l = PageList{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// WriteMarkdown writes the pages as a JSON array with their bodies converted
+// to Markdown.
+func (l PageList) WriteMarkdown(w interface{ Write([]byte) (int, error) }) error {
+	converted := PageList{}
+	for _, p := range l {
+		markdown, err := ToMarkdownWithReferences(p.Body, p.refs)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s to markdown: %w", p.ID, err)
+		}
+		c := *p
+		c.Body = markdown
+		converted = append(converted, &c)
+	}
+	return converted.Write(w)
+}
+
+// GetBlogPost retrieves a blog post by its ID, resolving its space key.
+func (c *Client) GetBlogPost(ctx context.Context, id string) (*Page, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/blogposts/%s?body-format=storage", c.cfg.BaseURL(), url.PathEscape(id))
+	body, err := c.doJSON(ctx, "GET", reqURL, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	post, err := ParseAPIResponse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key is informational, as for pages
+	if post.SpaceID != "" {
+		if space, err := c.GetSpaceByID(ctx, post.SpaceID); err == nil {
+			post.SpaceKey = space.Key
+		}
+	}
+	return post, nil
+}
+
+// BlogListOptions filters ListBlogPosts.
+type BlogListOptions struct {
+	SpaceKey string    // optional; all spaces if empty
+	Since    time.Time // optional; only posts published at or after this time
+	Until    time.Time // optional; only posts published before this time
+	Limit    int       // optional; 0 returns all matching posts
+}
+
+// errEnoughPosts stops paging once the listing has all the posts it needs.
+var errEnoughPosts = errors.New("enough blog posts")
+
+// ListBlogPosts returns blog posts with their bodies, newest first. Posts are
+// filtered by publication date as the API has no date filter; since they are
+// listed newest first, paging stops at the first post older than Since.
+func (c *Client) ListBlogPosts(ctx context.Context, opts BlogListOptions) (PageList, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("limit cannot be negative")
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Until.After(opts.Since) {
+		return nil, fmt.Errorf("the end of the date range must be after its start")
+	}
+
+	query := url.Values{}
+	query.Set("body-format", "storage")
+	query.Set("sort", "-created-date")
+	query.Set("limit", "100")
+
+	var space *Space
+	if opts.SpaceKey != "" {
+		var err error
+		if space, err = c.GetSpace(ctx, opts.SpaceKey); err != nil {
+			return nil, err
+		}
+		query.Set("space-id", space.ID)
+	}
+
+	posts := PageList{}
+	err := c.getPaged(ctx, "/wiki/api/v2/blogposts?"+query.Encode(), func(body []byte) error {
+		var resp struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, r := range resp.Results {
+			post, err := ParseAPIResponse(r)
+			if err != nil {
+				return err
+			}
+			published, err := time.Parse(time.RFC3339, post.Created)
+			if err != nil {
+				return fmt.Errorf("blog post %s has an invalid publication date %q", post.ID, post.Created)
+			}
+			if !opts.Since.IsZero() && published.Before(opts.Since) {
+				return errEnoughPosts
+			}
+			if !opts.Until.IsZero() && !published.Before(opts.Until) {
+				continue
+			}
+
+			if space != nil {
+				post.SpaceKey = space.Key
+			} else if s, err := c.GetSpaceByID(ctx, post.SpaceID); err == nil {
+				post.SpaceKey = s.Key
+			}
+			posts = append(posts, post)
+			if opts.Limit > 0 && len(posts) >= opts.Limit {
+				return errEnoughPosts
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnoughPosts) {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// CreateBlogPost publishes a blog post in a space. Blog posts have no parent,
+// so post.ParentID must be empty.
+func (c *Client) CreateBlogPost(ctx context.Context, post *NewPage) (*PublishedPage, error) {
+	if err := post.Validate(); err != nil {
+		return nil, err
+	}
+	if post.ParentID != "" {
+		return nil, fmt.Errorf("blog posts cannot have a parent page")
+	}
+
+	spaceID, err := c.lookupSpaceID(ctx, post.SpaceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &createPageRequest{
+		SpaceID: spaceID,
+		Status:  "current",
+		Title:   post.Title,
+		Body: pageBody{
+			Representation: "storage",
+			Value:          post.Body,
+		},
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/blogposts", c.cfg.BaseURL())
+	body, err := c.doJSON(ctx, "POST", reqURL, payload, http.StatusOK, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiPageResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &PublishedPage{
+		ID:      resp.ID,
+		Title:   resp.Title,
+		Version: resp.Version.Number,
+		URL:     c.webURL(resp.Links.Base, resp.Links.WebUI, resp.ID),
+	}, nil
+}
@@ -0,0 +1,201 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+// blogPostJSON returns a v2 blog post with the given publication date.
+func blogPostJSON(id, created string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        id,
+		"title":     "Post " + id,
+		"spaceId":   "65011",
+		"createdAt": created,
+		"version":   map[string]interface{}{"number": 1, "createdAt": created},
+		"body":      map[string]interface{}{"storage": map[string]string{"value": "<p>News " + id + "</p>"}},
+	}
+}
+
+func TestClient_GetBlogPost(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wiki/api/v2/blogposts/98765":
+			if r.URL.Query().Get("body-format") != "storage" {
+				t.Errorf("expected body-format=storage, got %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(blogPostJSON("98765", "2024-03-01T09:00:00.000Z"))
+		case "/wiki/api/v2/spaces/65011":
+			w.Write([]byte(`{"id": "65011", "key": "ENG", "name": "Engineering"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	post, err := client.GetBlogPost(context.Background(), "98765")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if post.Title != "Post 98765" || post.SpaceKey != "ENG" || post.Created != "2024-03-01T09:00:00.000Z" {
+		t.Errorf("unexpected post: %+v", post)
+	}
+	if post.Body != "<p>News 98765</p>" {
+		t.Errorf("unexpected body: %q", post.Body)
+	}
+}
+
+func TestClient_ListBlogPosts(t *testing.T) {
+	// Two pages of posts, newest first
+	pages := [][]map[string]interface{}{
+		{
+			blogPostJSON("5", "2024-04-02T10:00:00.000Z"),
+			blogPostJSON("4", "2024-03-20T10:00:00.000Z"),
+		},
+		{
+			blogPostJSON("3", "2024-03-05T10:00:00.000Z"),
+			blogPostJSON("2", "2024-02-10T10:00:00.000Z"),
+			blogPostJSON("1", "2024-01-10T10:00:00.000Z"),
+		},
+	}
+	var requests int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wiki/api/v2/spaces":
+			w.Write([]byte(`{"results": [{"id": "65011", "key": "ENG"}]}`))
+		case "/wiki/api/v2/blogposts":
+			if r.URL.Query().Get("space-id") != "65011" || r.URL.Query().Get("sort") != "-created-date" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			page := 0
+			fmt.Sscan(r.URL.Query().Get("cursor"), &page)
+			requests++
+			resp := map[string]interface{}{"results": pages[page]}
+			if page+1 < len(pages) {
+				resp["_links"] = map[string]string{"next": fmt.Sprintf("/wiki/api/v2/blogposts?space-id=65011&sort=-created-date&cursor=%d", page+1)}
+			}
+			json.NewEncoder(w).Encode(resp)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	tests := []struct {
+		name         string
+		opts         BlogListOptions
+		want         []string
+		wantRequests int
+	}{
+		{
+			name:         "all posts",
+			opts:         BlogListOptions{SpaceKey: "ENG"},
+			want:         []string{"5", "4", "3", "2", "1"},
+			wantRequests: 2,
+		},
+		{
+			name: "date range",
+			opts: BlogListOptions{
+				SpaceKey: "ENG",
+				Since:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				Until:    time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want:         []string{"4", "3"},
+			wantRequests: 2,
+		},
+		{
+			name:         "since stops paging",
+			opts:         BlogListOptions{SpaceKey: "ENG", Since: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+			want:         []string{"5", "4"},
+			wantRequests: 2,
+		},
+		{
+			name:         "limit",
+			opts:         BlogListOptions{SpaceKey: "ENG", Limit: 2},
+			want:         []string{"5", "4"},
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			posts, err := client.ListBlogPosts(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, p := range posts {
+				ids = append(ids, p.ID)
+				if p.SpaceKey != "ENG" {
+					t.Errorf("post %s has space key %q", p.ID, p.SpaceKey)
+				}
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got posts %v, want %v", ids, tt.want)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("made %d list requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestClient_ListBlogPosts_InvalidRange(t *testing.T) {
+	client := NewClient(&config.Config{Site: "test.atlassian.net"}, false)
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.ListBlogPosts(context.Background(), BlogListOptions{Since: day, Until: day})
+	if err == nil || !strings.Contains(err.Error(), "date range") {
+		t.Errorf("expected date range error, got %v", err)
+	}
+}
+
+func TestClient_CreateBlogPost(t *testing.T) {
+	var req createPageRequest
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/spaces":
+			w.Write([]byte(`{"results": [{"id": "65011", "key": "ENG"}]}`))
+		case r.Method == "POST" && r.URL.Path == "/wiki/api/v2/blogposts":
+			json.NewDecoder(r.Body).Decode(&req)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "98765", "title": "Release notes", "version": {"number": 1}, "_links": {"base": "https://acme.atlassian.net/wiki", "webui": "/spaces/ENG/blog/2024/03/01/98765"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	created, err := client.CreateBlogPost(context.Background(), &NewPage{SpaceKey: "ENG", Title: "Release notes", Body: "<p>Shipped</p>"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.SpaceID != "65011" || req.ParentID != "" || req.Body.Value != "<p>Shipped</p>" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if created.ID != "98765" || created.URL != "https://acme.atlassian.net/wiki/spaces/ENG/blog/2024/03/01/98765" {
+		t.Errorf("unexpected result: %+v", created)
+	}
+
+	_, err = client.CreateBlogPost(context.Background(), &NewPage{SpaceKey: "ENG", Title: "Child", ParentID: "123"})
+	if err == nil || !strings.Contains(err.Error(), "cannot have a parent") {
+		t.Errorf("expected parent error, got %v", err)
+	}
+}
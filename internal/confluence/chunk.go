@@ -0,0 +1,284 @@
+package confluence
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultChunkChars is the chunk size used when ChunkOptions sets no limit.
+const DefaultChunkChars = 4000
+
+// ChunkOptions limits the size of chunks. A chunk stays within every limit
+// that is set, except that code blocks and tables are never split and may
+// exceed them on their own.
+type ChunkOptions struct {
+	MaxChars int `json:"maxChars,omitempty"`
+	// MaxTokens is compared against an estimate of four characters per
+	// token, which is close enough for English prose with common tokenizers.
+	MaxTokens int `json:"maxTokens,omitempty"`
+}
+
+// Validate checks that the limits are not negative.
+func (o ChunkOptions) Validate() error {
+	if o.MaxChars < 0 {
+		return fmt.Errorf("chunk size in characters cannot be negative")
+	}
+	if o.MaxTokens < 0 {
+		return fmt.Errorf("chunk size in tokens cannot be negative")
+	}
+	return nil
+}
+
+// fits reports whether text is within the limits.
+func (o ChunkOptions) fits(text string) bool {
+	chars := utf8.RuneCountInString(text)
+	if o.MaxChars == 0 && o.MaxTokens == 0 {
+		return chars <= DefaultChunkChars
+	}
+	if o.MaxChars > 0 && chars > o.MaxChars {
+		return false
+	}
+	return o.MaxTokens == 0 || (chars+3)/4 <= o.MaxTokens
+}
+
+// Chunk is a piece of a page's Markdown body, sized for a retrieval index.
+type Chunk struct {
+	PageID  string `json:"pageId"`
+	Title   string `json:"title"`
+	Version int    `json:"version"`
+	// HeadingPath lists the headings the chunk is under, outermost first.
+	HeadingPath []string `json:"headingPath"`
+	ChunkIndex  int      `json:"chunkIndex"`
+	Content     string   `json:"content"`
+}
+
+// ChunkList is the chunks of one or more pages.
+type ChunkList []*Chunk
+
+// Write writes the chunks as JSON Lines, one chunk per line.
+func (l ChunkList) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	for _, chunk := range l {
+		if err := encoder.Encode(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Chunks splits the page's Markdown body into chunks; see ChunkMarkdown.
+func (p *Page) Chunks(opts ChunkOptions) (ChunkList, error) {
+	markdown, err := p.Markdown()
+	if err != nil {
+		return nil, err
+	}
+	return p.chunksOf(markdown, opts), nil
+}
+
+// chunksOf splits the given Markdown version of the page body into chunks.
+func (p *Page) chunksOf(markdown string, opts ChunkOptions) ChunkList {
+	var chunks ChunkList
+	for i, c := range ChunkMarkdown(markdown, opts) {
+		chunks = append(chunks, &Chunk{
+			PageID:      p.ID,
+			Title:       p.Title,
+			Version:     p.Version,
+			HeadingPath: c.HeadingPath,
+			ChunkIndex:  i,
+			Content:     c.Content,
+		})
+	}
+	return chunks
+}
+
+// MarkdownChunk is a piece of a Markdown document and the headings it is
+// under.
+type MarkdownChunk struct {
+	HeadingPath []string
+	Content     string
+}
+
+// ChunkMarkdown splits a Markdown document along its headings into chunks
+// within the size limits. A section is kept together with its subsections
+// while they fit; larger sections are split between paragraphs, then
+// between lines, then between words. Fenced code blocks and tables are
+// never split.
+func ChunkMarkdown(markdown string, opts ChunkOptions) []MarkdownChunk {
+	type openHeading struct {
+		level int
+		text  string
+	}
+	var stack []openHeading
+	var chunks []MarkdownChunk
+	var current *MarkdownChunk
+	currentLevel := 0
+
+	for _, s := range splitMarkdownSections(markdown) {
+		if s.level > 0 {
+			for len(stack) > 0 && stack[len(stack)-1].level >= s.level {
+				stack = stack[:len(stack)-1]
+			}
+			if s.heading != "" {
+				stack = append(stack, openHeading{s.level, headingText(s.heading)})
+			}
+		}
+		path := make([]string, len(stack))
+		for i, h := range stack {
+			path[i] = h.text
+		}
+
+		// Subsections join the chunk of the section they belong to
+		if current != nil && s.level > currentLevel && opts.fits(current.Content+"\n\n"+s.text) {
+			current.Content += "\n\n" + s.text
+			continue
+		}
+		if current != nil {
+			chunks = append(chunks, *current)
+			current = nil
+		}
+
+		if opts.fits(s.text) {
+			current = &MarkdownChunk{HeadingPath: path, Content: s.text}
+			currentLevel = s.level
+			if s.heading == "" {
+				// Text before the first heading has no subsections
+				currentLevel = 7
+			}
+			continue
+		}
+		for _, piece := range opts.packSection(s.text, s.level > 0) {
+			chunks = append(chunks, MarkdownChunk{HeadingPath: path, Content: piece})
+		}
+	}
+	if current != nil {
+		chunks = append(chunks, *current)
+	}
+	return chunks
+}
+
+// packSection splits a section too large for one chunk. The heading line,
+// if there is one, leads the first chunk: a chunk of just a heading is no
+// use in an index.
+func (o ChunkOptions) packSection(text string, hasHeading bool) []string {
+	heading, body, _ := strings.Cut(text, "\n")
+	blocks := markdownBlocks(body)
+	if !hasHeading || len(blocks) == 0 || !o.fits(heading) {
+		return o.pack(markdownBlocks(text), "\n\n", o.splitBlock)
+	}
+
+	lead := heading + "\n\n"
+	if o.fits(lead+blocks[0]) || isAtomicBlock(blocks[0]) {
+		blocks[0] = lead + blocks[0]
+		return o.pack(blocks, "\n\n", o.splitBlock)
+	}
+	// Split the first block with room left for the heading
+	first := o.less(lead).splitBlock(blocks[0])
+	first[0] = lead + first[0]
+	return append(first, o.pack(blocks[1:], "\n\n", o.splitBlock)...)
+}
+
+// less returns the limits left once prefix is taken from each chunk.
+func (o ChunkOptions) less(prefix string) ChunkOptions {
+	n := utf8.RuneCountInString(prefix)
+	if o.MaxChars == 0 && o.MaxTokens == 0 {
+		o.MaxChars = DefaultChunkChars
+	}
+	if o.MaxChars > 0 {
+		o.MaxChars = max(o.MaxChars-n, 1)
+	}
+	if o.MaxTokens > 0 {
+		o.MaxTokens = max(o.MaxTokens-(n+3)/4, 1)
+	}
+	return o
+}
+
+// pack joins pieces with sep into as few chunks as fit the limits. A piece
+// too large on its own is replaced by the chunks split returns for it.
+func (o ChunkOptions) pack(pieces []string, sep string, split func(string) []string) []string {
+	var chunks []string
+	current := ""
+	for _, piece := range pieces {
+		candidate := piece
+		if current != "" {
+			candidate = current + sep + piece
+		}
+		if o.fits(candidate) {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			chunks = append(chunks, current)
+			current = ""
+		}
+		if o.fits(piece) {
+			current = piece
+			continue
+		}
+		chunks = append(chunks, split(piece)...)
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// splitBlock splits a paragraph or list that is too large between lines.
+// Code blocks and tables are returned whole.
+func (o ChunkOptions) splitBlock(block string) []string {
+	if isAtomicBlock(block) {
+		return []string{block}
+	}
+	return o.pack(strings.Split(block, "\n"), "\n", o.splitLine)
+}
+
+// splitLine splits a line that is too large between words.
+func (o ChunkOptions) splitLine(line string) []string {
+	return o.pack(strings.Fields(line), " ", func(word string) []string {
+		return []string{word}
+	})
+}
+
+// markdownBlocks splits Markdown into blocks separated by blank lines,
+// keeping fenced code blocks in one piece.
+func markdownBlocks(markdown string) []string {
+	var blocks []string
+	var lines []string
+	flush := func() {
+		if len(lines) > 0 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+			lines = nil
+		}
+	}
+
+	fence := ""
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		} else if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+		} else if trimmed == "" {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return blocks
+}
+
+// isAtomicBlock reports whether a block contains a code block or a table,
+// which must not be split.
+func isAtomicBlock(block string) bool {
+	for _, line := range strings.Split(block, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") || strings.HasPrefix(trimmed, "|") {
+			return true
+		}
+	}
+	return false
+}
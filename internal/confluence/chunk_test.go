@@ -0,0 +1,154 @@
+package confluence
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkMarkdown(t *testing.T) {
+	doc := "Intro.\n\n# Guide\n\nShort.\n\n## Install\n\nRun it.\n\n# Reference\n\nAPI."
+
+	tests := []struct {
+		name  string
+		opts  ChunkOptions
+		paths []string
+		first string
+	}{
+		{
+			name:  "subsections join their parent while they fit",
+			opts:  ChunkOptions{},
+			paths: []string{"", "Guide", "Reference"},
+			first: "Intro.",
+		},
+		{
+			name:  "small budget keeps sections apart",
+			opts:  ChunkOptions{MaxChars: 20},
+			paths: []string{"", "Guide", "Guide > Install", "Reference"},
+			first: "Intro.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := ChunkMarkdown(doc, tt.opts)
+			var paths []string
+			for _, c := range chunks {
+				paths = append(paths, strings.Join(c.HeadingPath, " > "))
+			}
+			if strings.Join(paths, "|") != strings.Join(tt.paths, "|") {
+				t.Errorf("heading paths = %q, want %q", paths, tt.paths)
+			}
+			if len(chunks) > 0 && chunks[0].Content != tt.first {
+				t.Errorf("first chunk = %q, want %q", chunks[0].Content, tt.first)
+			}
+		})
+	}
+}
+
+func TestChunkMarkdown_SplitsLargeSections(t *testing.T) {
+	code := "```go\n" + strings.Repeat("fmt.Println(\"x\")\n", 10) + "```"
+	table := "| a | b |\n| --- | --- |\n" + strings.Repeat("| 1 | 2 |\n", 10)
+	para := strings.Repeat("word ", 40)
+	doc := "# Big\n\n" + para + "\n\n" + code + "\n\n" + strings.TrimSpace(table) + "\n\nTail."
+
+	opts := ChunkOptions{MaxChars: 60}
+	chunks := ChunkMarkdown(doc, opts)
+	if len(chunks) < 5 {
+		t.Fatalf("expected the section to be split, got %d chunks", len(chunks))
+	}
+
+	var sawCode, sawTable bool
+	for _, c := range chunks {
+		if strings.Join(c.HeadingPath, "/") != "Big" {
+			t.Errorf("chunk lost its heading path: %q", c.HeadingPath)
+		}
+		switch {
+		case c.Content == code:
+			sawCode = true
+		case c.Content == strings.TrimSpace(table):
+			sawTable = true
+		case strings.Contains(c.Content, "```") || strings.Contains(c.Content, "|"):
+			t.Errorf("code block or table was split or merged:\n%s", c.Content)
+		default:
+			if n := utf8.RuneCountInString(c.Content); n > opts.MaxChars {
+				t.Errorf("chunk of %d characters exceeds the limit:\n%s", n, c.Content)
+			}
+		}
+	}
+	if !sawCode || !sawTable {
+		t.Errorf("expected code block and table as whole chunks (code %v, table %v)", sawCode, sawTable)
+	}
+}
+
+func TestChunkMarkdown_NoHeadingOnlyChunks(t *testing.T) {
+	para := strings.Repeat("word ", 50)
+	doc := "# A\n\nIntro.\n\n## B\n\n" + para + "\n\n" + para + "\n\n## C\n" + strings.Repeat("long line of text\n", 20)
+
+	opts := ChunkOptions{MaxChars: 200}
+	chunks := ChunkMarkdown(doc, opts)
+	for _, c := range chunks {
+		if _, _, ok := parseATXHeading(c.Content); ok && !strings.Contains(c.Content, "\n") {
+			t.Errorf("chunk is just a heading: %q", c.Content)
+		}
+		if n := utf8.RuneCountInString(c.Content); n > opts.MaxChars {
+			t.Errorf("chunk of %d characters exceeds the limit:\n%s", n, c.Content)
+		}
+	}
+
+	var starts []string
+	for _, c := range chunks {
+		if line, _, _ := strings.Cut(c.Content, "\n"); strings.HasPrefix(line, "#") {
+			starts = append(starts, line)
+		}
+	}
+	if got := strings.Join(starts, ","); got != "# A,## B,## C" {
+		t.Errorf("chunks starting with a heading = %q", got)
+	}
+}
+
+func TestChunkMarkdown_TokenLimit(t *testing.T) {
+	doc := "# A\n\n" + strings.Repeat("abcd ", 100)
+	for _, c := range ChunkMarkdown(doc, ChunkOptions{MaxTokens: 25}) {
+		if n := utf8.RuneCountInString(c.Content); n > 100 {
+			t.Errorf("chunk of %d characters exceeds 25 tokens:\n%s", n, c.Content)
+		}
+	}
+}
+
+func TestChunkOptions_Validate(t *testing.T) {
+	if err := (ChunkOptions{MaxChars: -1}).Validate(); err == nil {
+		t.Error("expected error for negative character limit")
+	}
+	if err := (ChunkOptions{MaxTokens: -1}).Validate(); err == nil {
+		t.Error("expected error for negative token limit")
+	}
+	if err := (ChunkOptions{}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPage_Chunks(t *testing.T) {
+	page := &Page{ID: "7", Title: "Design", Version: 2, Body: `<h1>Goals</h1><p>Fast.</p><h1>Plan</h1><p>Ship.</p>`}
+	chunks, err := page.Chunks(ChunkOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := chunks.Write(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got:\n%s", buf.String())
+	}
+	var second Chunk
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if second.PageID != "7" || second.Title != "Design" || second.Version != 2 || second.ChunkIndex != 1 ||
+		len(second.HeadingPath) != 1 || second.HeadingPath[0] != "Plan" || !strings.Contains(second.Content, "Ship.") {
+		t.Errorf("unexpected chunk: %+v", second)
+	}
+}
@@ -1,19 +1,29 @@
 package confluence
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"sync"
 
 	"github.com/martin/atl-cli/internal/config"
 	"github.com/martin/atl-cli/internal/httpclient"
+	"github.com/martin/atl-cli/internal/jira"
 )
 
 // Client is a Confluence REST API client.
 type Client struct {
 	cfg        *config.Config
 	httpClient *httpclient.Client
+
+	// spaces remembers the spaces the client has looked up, by ID, so
+	// commands that touch many pages resolve each space only once.
+	spacesMu sync.Mutex
+	spaces   map[string]*Space
 }
 
 // NewClient creates a new Confluence client.
@@ -24,15 +34,68 @@ func NewClient(cfg *config.Config, debug bool) *Client {
 	}
 }
 
-// GetPage retrieves a Confluence page by its ID.
+// GetPage retrieves a Confluence page by its ID, resolving its space key.
 func (c *Client) GetPage(ctx context.Context, id string) (*Page, error) {
+	return c.GetPageVersion(ctx, id, 0)
+}
+
+// GetPageVersion retrieves a historical version of a page, resolving its
+// space key. Version 0 is the current version.
+func (c *Client) GetPageVersion(ctx context.Context, id string, version int) (*Page, error) {
+	return c.GetPageFormat(ctx, id, version, BodyFormatStorage)
+}
+
+// GetPageFormat retrieves a version of a page (0 for the current one) with
+// its body in the given format, resolving its space key.
+func (c *Client) GetPageFormat(ctx context.Context, id string, version int, format string) (*Page, error) {
+	if version < 0 {
+		return nil, fmt.Errorf("version must be a positive number")
+	}
+	if err := ValidateBodyFormat(format); err != nil {
+		return nil, err
+	}
+
+	page, err := c.fetchPageBody(ctx, id, version, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key is informational, so a failed lookup leaves it empty rather
+	// than failing the whole request.
+	if page.SpaceID != "" {
+		if space, err := c.GetSpaceByID(ctx, page.SpaceID); err == nil {
+			page.SpaceKey = space.Key
+		}
+	}
+
+	return page, nil
+}
+
+// fetchPage retrieves the current version of a page without resolving its
+// space key.
+func (c *Client) fetchPage(ctx context.Context, id string) (*Page, error) {
+	return c.fetchPageVersion(ctx, id, 0)
+}
+
+// fetchPageVersion retrieves a version of a page (0 for the current one)
+// without resolving its space key.
+func (c *Client) fetchPageVersion(ctx context.Context, id string, version int) (*Page, error) {
+	return c.fetchPageBody(ctx, id, version, BodyFormatStorage)
+}
+
+// fetchPageBody retrieves a version of a page with its body in the given
+// format, without resolving its space key.
+func (c *Client) fetchPageBody(ctx context.Context, id string, version int, format string) (*Page, error) {
 	// Validate page ID format
 	if err := ValidatePageID(id); err != nil {
 		return nil, err
 	}
 
 	// Build request URL - Confluence v2 API
-	url := fmt.Sprintf("%s/wiki/api/v2/pages/%s?body-format=storage", c.cfg.BaseURL(), id)
+	url := fmt.Sprintf("%s/wiki/api/v2/pages/%s?body-format=%s", c.cfg.BaseURL(), id, format)
+	if version > 0 {
+		url += fmt.Sprintf("&version=%d", version)
+	}
 
 	req, err := c.httpClient.NewRequest(ctx, "GET", url, nil)
 	if err != nil {
@@ -89,9 +152,201 @@ func (c *Client) CheckConnectivity(ctx context.Context) error {
 	return nil
 }
 
+// CreatePage creates a new page and returns its ID, version and web URL.
+func (c *Client) CreatePage(ctx context.Context, page *NewPage) (*PublishedPage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The v2 API addresses spaces by ID, not key
+	spaceID, err := c.lookupSpaceID(ctx, page.SpaceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	representation := page.BodyFormat
+	if representation == "" {
+		representation = BodyFormatStorage
+	}
+	if representation == BodyFormatADF {
+		var doc jira.ADFDoc
+		if err := json.Unmarshal([]byte(page.Body), &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse ADF body: %w", err)
+		}
+		if err := jira.ValidateADF(&doc); err != nil {
+			return nil, err
+		}
+	}
+	payload := &createPageRequest{
+		SpaceID:  spaceID,
+		Status:   "current",
+		Title:    page.Title,
+		ParentID: page.ParentID,
+		Body: pageBody{
+			Representation: representation,
+			Value:          page.Body,
+		},
+	}
+
+	url := fmt.Sprintf("%s/wiki/api/v2/pages", c.cfg.BaseURL())
+	body, err := c.doJSON(ctx, "POST", url, payload, http.StatusOK, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiPageResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &PublishedPage{
+		ID:      resp.ID,
+		Title:   resp.Title,
+		Version: resp.Version.Number,
+		URL:     c.webURL(resp.Links.Base, resp.Links.WebUI, resp.ID),
+	}, nil
+}
+
+// UpdatePage replaces the body (and optionally the title) of an existing page.
+// The update is submitted as the version after the one currently stored, so
+// an edit made by someone else in the meantime is rejected by the API with a
+// conflict instead of being overwritten.
+func (c *Client) UpdatePage(ctx context.Context, id string, update *PageUpdate) (*PublishedPage, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := c.fetchPage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.ExpectVersion > 0 && current.Version != update.ExpectVersion {
+		return nil, &httpclient.APIError{Response: httpclient.NewConflictError(fmt.Sprintf(
+			"page %s is at version %d, expected version %d", id, current.Version, update.ExpectVersion))}
+	}
+
+	title := update.Title
+	if title == "" {
+		title = current.Title
+	}
+
+	payload := &updatePageRequest{
+		ID:       id,
+		Status:   "current",
+		Title:    title,
+		ParentID: update.ParentID,
+		Body: pageBody{
+			Representation: "storage",
+			Value:          update.Body,
+		},
+		Version: pageVersion{
+			Number:    current.Version + 1,
+			Message:   update.Message,
+			MinorEdit: update.MinorEdit,
+		},
+	}
+
+	url := fmt.Sprintf("%s/wiki/api/v2/pages/%s", c.cfg.BaseURL(), id)
+	body, err := c.doJSON(ctx, "PUT", url, payload, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiPageResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &PublishedPage{
+		ID:      resp.ID,
+		Title:   resp.Title,
+		Version: resp.Version.Number,
+		URL:     c.webURL(resp.Links.Base, resp.Links.WebUI, resp.ID),
+	}, nil
+}
+
+// doJSON sends a request with an optional JSON-encoded payload and returns
+// the response body if the status code is one of want.
+func (c *Client) doJSON(ctx context.Context, method, url string, payload interface{}, want ...int) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := c.httpClient.NewRequest(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("request timed out")
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !slices.Contains(want, resp.StatusCode) {
+		return nil, c.handleError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// getPaged fetches a v2 collection starting at path (relative to the site
+// root), passing each response body to page and following the _links.next
+// cursor until the collection is exhausted.
+func (c *Client) getPaged(ctx context.Context, path string, page func(body []byte) error) error {
+	for next := path; next != ""; {
+		// Cursor links are resolved against the configured site so that
+		// requests never leave the expected host.
+		body, err := c.doJSON(ctx, "GET", c.cfg.BaseURL()+next, nil, http.StatusOK)
+		if err != nil {
+			return err
+		}
+		if err := page(body); err != nil {
+			return err
+		}
+
+		var links struct {
+			Links struct {
+				Next string `json:"next"`
+			} `json:"_links"`
+		}
+		if err := json.Unmarshal(body, &links); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		next = links.Links.Next
+	}
+	return nil
+}
+
+// webURL builds the browser URL for content from the API's _links fields,
+// falling back to the page ID when the API omits them.
+func (c *Client) webURL(base, webui, id string) string {
+	if base == "" {
+		base = c.cfg.BaseURL() + "/wiki"
+	}
+	if webui == "" {
+		return fmt.Sprintf("%s/pages/viewpage.action?pageId=%s", base, id)
+	}
+	return base + webui
+}
+
 func (c *Client) handleError(resp *http.Response) error {
-	errResp := httpclient.NewErrorResponse(resp)
-	return fmt.Errorf("%s: %s", errResp.Error, errResp.Message)
+	return &httpclient.APIError{Response: httpclient.NewErrorResponse(resp)}
 }
 
 // SetHTTPClient sets the underlying HTTP client (for testing).
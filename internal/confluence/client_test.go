@@ -13,6 +13,17 @@ import (
 
 func TestClient_GetPage_Success(t *testing.T) {
 	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The space key is resolved from the page's spaceId
+		if r.URL.Path == "/wiki/api/v2/spaces/65011" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":   "65011",
+				"key":  "DOCS",
+				"name": "Documentation",
+			})
+			return
+		}
+
 		// Verify request path
 		if !strings.Contains(r.URL.Path, "/wiki/api/v2/pages/123456789") {
 			t.Errorf("unexpected path: %s", r.URL.Path)
@@ -70,6 +81,12 @@ func TestClient_GetPage_Success(t *testing.T) {
 	if page.Body != "<p>Test content</p>" {
 		t.Errorf("expected body '<p>Test content</p>', got '%s'", page.Body)
 	}
+	if page.SpaceID != "65011" {
+		t.Errorf("expected spaceId 65011, got %s", page.SpaceID)
+	}
+	if page.SpaceKey != "DOCS" {
+		t.Errorf("expected spaceKey DOCS, got %s", page.SpaceKey)
+	}
 }
 
 func TestClient_GetPage_NotFound(t *testing.T) {
@@ -0,0 +1,269 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// Comment is a footer or inline page comment with its replies.
+type Comment struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	AuthorID string `json:"authorId"`
+	Author   string `json:"author"`
+	Created  string `json:"created"`
+	// ResolutionStatus is open, reopened, resolved or dangling for inline
+	// comments; footer comments have none.
+	ResolutionStatus string `json:"resolutionStatus,omitempty"`
+	// HighlightedText is the page text an inline comment is anchored to.
+	HighlightedText string     `json:"highlightedText,omitempty"`
+	Body            string     `json:"body"`
+	Replies         []*Comment `json:"replies"`
+}
+
+// Write writes the comment as JSON to the given writer.
+func (c *Comment) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// CommentList is a list of comment threads written as a JSON array.
+type CommentList []*Comment
+
+// Write writes the comments as a JSON array to the given writer.
+func (l CommentList) Write(w interface{ Write([]byte) (int, error) }) error {
+	if l == nil {
+		l = CommentList{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// apiComment is a footer or inline comment as returned by the v2 API.
+type apiComment struct {
+	ID               string `json:"id"`
+	ResolutionStatus string `json:"resolutionStatus"`
+	Version          struct {
+		AuthorID  string `json:"authorId"`
+		CreatedAt string `json:"createdAt"`
+	} `json:"version"`
+	Body struct {
+		Storage *struct {
+			Value string `json:"value"`
+		} `json:"storage"`
+	} `json:"body"`
+	Properties struct {
+		InlineOriginalSelection string `json:"inlineOriginalSelection"`
+	} `json:"properties"`
+}
+
+type apiCommentListResponse struct {
+	Results []apiComment `json:"results"`
+}
+
+// Comment types, which are also the v2 API path prefixes.
+const (
+	footerComment = "footer"
+	inlineComment = "inline"
+)
+
+// ListComments returns the footer and inline comments of a page as threads,
+// footer comments first, with bodies converted to Markdown and author
+// display names resolved where possible.
+func (c *Client) ListComments(ctx context.Context, pageID string) (CommentList, error) {
+	if err := ValidatePageID(pageID); err != nil {
+		return nil, err
+	}
+
+	comments := CommentList{}
+	for _, kind := range []string{footerComment, inlineComment} {
+		path := fmt.Sprintf("/wiki/api/v2/pages/%s/%s-comments?body-format=storage&limit=100", url.PathEscape(pageID), kind)
+		threads, err := c.listCommentThreads(ctx, path, kind)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, threads...)
+	}
+
+	var authorIDs []string
+	seen := make(map[string]bool)
+	walkComments(comments, func(cm *Comment) {
+		if cm.AuthorID != "" && !seen[cm.AuthorID] {
+			seen[cm.AuthorID] = true
+			authorIDs = append(authorIDs, cm.AuthorID)
+		}
+	})
+	if len(authorIDs) > 0 {
+		names := c.lookupUsers(ctx, authorIDs)
+		walkComments(comments, func(cm *Comment) {
+			cm.Author = names[cm.AuthorID]
+		})
+	}
+
+	return comments, nil
+}
+
+// listCommentThreads fetches the comments at path and, recursively, their
+// replies.
+func (c *Client) listCommentThreads(ctx context.Context, path, kind string) ([]*Comment, error) {
+	var comments []*Comment
+	err := c.getPaged(ctx, path, func(body []byte) error {
+		var resp apiCommentListResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, r := range resp.Results {
+			comment, err := newComment(r, kind)
+			if err != nil {
+				return err
+			}
+			comments = append(comments, comment)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, comment := range comments {
+		childPath := fmt.Sprintf("/wiki/api/v2/%s-comments/%s/children?body-format=storage&limit=100", kind, url.PathEscape(comment.ID))
+		replies, err := c.listCommentThreads(ctx, childPath, kind)
+		if err != nil {
+			return nil, err
+		}
+		comment.Replies = append(comment.Replies, replies...)
+	}
+	return comments, nil
+}
+
+// newComment converts an API comment, rendering its body as Markdown.
+func newComment(r apiComment, kind string) (*Comment, error) {
+	var storage string
+	if r.Body.Storage != nil {
+		storage = r.Body.Storage.Value
+	}
+	markdown, err := ToMarkdown(storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert comment %s to markdown: %w", r.ID, err)
+	}
+
+	comment := &Comment{
+		ID:       r.ID,
+		Type:     kind,
+		AuthorID: r.Version.AuthorID,
+		Created:  r.Version.CreatedAt,
+		Body:     markdown,
+		Replies:  []*Comment{},
+	}
+	if kind == inlineComment {
+		comment.ResolutionStatus = r.ResolutionStatus
+		comment.HighlightedText = r.Properties.InlineOriginalSelection
+	}
+	return comment, nil
+}
+
+// walkComments calls fn for every comment in the threads, replies included.
+func walkComments(comments []*Comment, fn func(*Comment)) {
+	for _, cm := range comments {
+		fn(cm)
+		walkComments(cm.Replies, fn)
+	}
+}
+
+// NewComment is a comment to post on a page, or a reply to another comment.
+type NewComment struct {
+	PageID string
+	// ReplyTo is the ID of the footer or inline comment being replied to.
+	ReplyTo string
+	// Body is the comment in storage format.
+	Body string
+}
+
+// Validate checks that the comment has a page and a body.
+func (n *NewComment) Validate() error {
+	if err := ValidatePageID(n.PageID); err != nil {
+		return err
+	}
+	if n.ReplyTo != "" {
+		if err := ValidatePageID(n.ReplyTo); err != nil {
+			return fmt.Errorf("invalid comment ID %q: must be numeric", n.ReplyTo)
+		}
+	}
+	if strings.TrimSpace(n.Body) == "" {
+		return fmt.Errorf("comment body is empty")
+	}
+	return nil
+}
+
+type createCommentRequest struct {
+	PageID          string   `json:"pageId,omitempty"`
+	ParentCommentID string   `json:"parentCommentId,omitempty"`
+	Body            pageBody `json:"body"`
+}
+
+// AddComment posts a footer comment on a page, or a reply to an existing
+// footer or inline comment.
+func (c *Client) AddComment(ctx context.Context, comment *NewComment) (*Comment, error) {
+	if err := comment.Validate(); err != nil {
+		return nil, err
+	}
+
+	kind := footerComment
+	payload := &createCommentRequest{
+		PageID: comment.PageID,
+		Body:   pageBody{Representation: "storage", Value: comment.Body},
+	}
+	if comment.ReplyTo != "" {
+		var err error
+		if kind, err = c.commentKind(ctx, comment.ReplyTo); err != nil {
+			return nil, err
+		}
+		payload.PageID = ""
+		payload.ParentCommentID = comment.ReplyTo
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/%s-comments?body-format=storage", c.cfg.BaseURL(), kind)
+	body, err := c.doJSON(ctx, "POST", reqURL, payload, http.StatusOK, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiComment
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return newComment(resp, kind)
+}
+
+// commentKind reports whether a comment is a footer or an inline comment.
+func (c *Client) commentKind(ctx context.Context, id string) (string, error) {
+	_, err := c.doJSON(ctx, "GET", fmt.Sprintf("%s/wiki/api/v2/footer-comments/%s", c.cfg.BaseURL(), url.PathEscape(id)), nil, http.StatusOK)
+	if err == nil {
+		return footerComment, nil
+	}
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Response.Error != httpclient.ErrTypeNotFound {
+		return "", err
+	}
+
+	_, err = c.doJSON(ctx, "GET", fmt.Sprintf("%s/wiki/api/v2/inline-comments/%s", c.cfg.BaseURL(), url.PathEscape(id)), nil, http.StatusOK)
+	if err != nil {
+		if errors.As(err, &apiErr) && apiErr.Response.Error == httpclient.ErrTypeNotFound {
+			return "", &httpclient.APIError{Response: &httpclient.ErrorResponse{
+				Error:   httpclient.ErrTypeNotFound,
+				Message: fmt.Sprintf("comment %s not found", id),
+			}}
+		}
+		return "", err
+	}
+	return inlineComment, nil
+}
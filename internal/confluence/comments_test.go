@@ -0,0 +1,162 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// commentJSON returns an API comment with a storage body.
+func commentJSON(id, author, storage string, extra map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{
+		"id":      id,
+		"version": map[string]string{"authorId": author, "createdAt": "2024-03-01T09:00:00Z"},
+		"body":    map[string]interface{}{"storage": map[string]string{"value": storage}},
+	}
+	for k, v := range extra {
+		c[k] = v
+	}
+	return c
+}
+
+func TestClient_ListComments(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var results []map[string]interface{}
+		switch r.URL.Path {
+		case "/wiki/api/v2/pages/123/footer-comments":
+			results = append(results, commentJSON("10", "u1", "<p>Looks <strong>good</strong></p>", nil))
+		case "/wiki/api/v2/footer-comments/10/children":
+			results = append(results, commentJSON("11", "u2", "<p>Thanks</p>", nil))
+		case "/wiki/api/v2/pages/123/inline-comments":
+			results = append(results, commentJSON("20", "u2", "<p>Typo here</p>", map[string]interface{}{
+				"resolutionStatus": "resolved",
+				"properties":       map[string]string{"inlineOriginalSelection": "teh service"},
+			}))
+		case "/wiki/api/v2/footer-comments/11/children", "/wiki/api/v2/inline-comments/20/children":
+		case "/wiki/rest/api/user/bulk":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"accountId": "u1", "displayName": "Ada"}, {"accountId": "u2", "displayName": "Grace"}},
+			})
+			return
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	comments, err := client.ListComments(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 threads, got %d", len(comments))
+	}
+
+	footer := comments[0]
+	if footer.Type != "footer" || footer.Author != "Ada" || footer.Body != "Looks **good**" || footer.ResolutionStatus != "" {
+		t.Errorf("unexpected footer comment: %+v", footer)
+	}
+	if len(footer.Replies) != 1 || footer.Replies[0].ID != "11" || footer.Replies[0].Author != "Grace" {
+		t.Errorf("expected reply 11 by Grace, got %+v", footer.Replies)
+	}
+
+	inline := comments[1]
+	if inline.Type != "inline" || inline.ResolutionStatus != "resolved" || inline.HighlightedText != "teh service" {
+		t.Errorf("unexpected inline comment: %+v", inline)
+	}
+}
+
+func TestClient_AddComment(t *testing.T) {
+	tests := []struct {
+		name      string
+		comment   NewComment
+		wantPath  string
+		wantField string
+	}{
+		{"footer comment", NewComment{PageID: "123", Body: "<p>Hi</p>"}, "/wiki/api/v2/footer-comments", `"pageId":"123"`},
+		{"reply to footer comment", NewComment{PageID: "123", ReplyTo: "10", Body: "<p>Hi</p>"}, "/wiki/api/v2/footer-comments", `"parentCommentId":"10"`},
+		{"reply to inline comment", NewComment{PageID: "123", ReplyTo: "20", Body: "<p>Hi</p>"}, "/wiki/api/v2/inline-comments", `"parentCommentId":"20"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted string
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/footer-comments/10",
+					r.Method == "GET" && r.URL.Path == "/wiki/api/v2/inline-comments/20":
+					json.NewEncoder(w).Encode(commentJSON("x", "u1", "", nil))
+				case r.Method == "GET":
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors": [{"title": "Not found"}]}`))
+				case r.Method == "POST" && r.URL.Path == tt.wantPath:
+					data, _ := io.ReadAll(r.Body)
+					posted = string(data)
+					json.NewEncoder(w).Encode(commentJSON("99", "u1", "<p>Hi</p>", nil))
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			defer server.Close()
+			client := newTestClient(server)
+
+			comment := tt.comment
+			created, err := client.AddComment(context.Background(), &comment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created.ID != "99" || created.Body != "Hi" {
+				t.Errorf("unexpected comment: %+v", created)
+			}
+			if !strings.Contains(posted, tt.wantField) {
+				t.Errorf("expected request to contain %s, got %s", tt.wantField, posted)
+			}
+		})
+	}
+}
+
+func TestClient_AddComment_UnknownParent(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	_, err := client.AddComment(context.Background(), &NewComment{PageID: "123", ReplyTo: "404", Body: "<p>Hi</p>"})
+	if err == nil || !strings.Contains(err.Error(), "comment 404 not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestNewComment_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment NewComment
+		wantErr bool
+	}{
+		{"valid", NewComment{PageID: "1", Body: "<p>x</p>"}, false},
+		{"valid reply", NewComment{PageID: "1", ReplyTo: "2", Body: "<p>x</p>"}, false},
+		{"empty body", NewComment{PageID: "1", Body: " "}, true},
+		{"bad page", NewComment{PageID: "abc", Body: "<p>x</p>"}, true},
+		{"bad reply id", NewComment{PageID: "1", ReplyTo: "x", Body: "<p>x</p>"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.comment.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
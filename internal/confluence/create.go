@@ -0,0 +1,68 @@
+package confluence
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// NewPage describes a page to create.
+type NewPage struct {
+	SpaceKey string
+	Title    string
+	ParentID string // optional; the page is created at the space root if empty
+	Body     string // storage format, unless BodyFormat says otherwise
+	// BodyFormat is BodyFormatStorage (the default if empty) or
+	// BodyFormatADF, with Body holding the document as JSON.
+	BodyFormat string
+}
+
+// Validate checks that the page has the fields required by the API.
+func (p *NewPage) Validate() error {
+	if err := ValidateSpaceKey(p.SpaceKey); err != nil {
+		return err
+	}
+	if p.Title == "" {
+		return fmt.Errorf("page title cannot be empty")
+	}
+	if p.ParentID != "" {
+		if err := ValidatePageID(p.ParentID); err != nil {
+			return fmt.Errorf("invalid parent: %w", err)
+		}
+	}
+	if p.BodyFormat != "" && p.BodyFormat != BodyFormatStorage && p.BodyFormat != BodyFormatADF {
+		return fmt.Errorf("pages can only be published in %s or %s format", BodyFormatStorage, BodyFormatADF)
+	}
+	return nil
+}
+
+// createPageRequest is the request body for POST /wiki/api/v2/pages.
+type createPageRequest struct {
+	SpaceID  string   `json:"spaceId"`
+	Status   string   `json:"status"`
+	Title    string   `json:"title"`
+	ParentID string   `json:"parentId,omitempty"`
+	Body     pageBody `json:"body"`
+}
+
+// pageBody is a page body in a given representation.
+type pageBody struct {
+	Representation string `json:"representation"`
+	Value          string `json:"value"`
+}
+
+// PublishedPage is the CLI output format for a created or updated page.
+type PublishedPage struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Version int    `json:"version"`
+	URL     string `json:"url"`
+	// Labels is set when labels were added to a new page.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Write writes the published page as JSON to the given writer.
+func (c *PublishedPage) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
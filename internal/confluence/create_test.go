@@ -0,0 +1,153 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+func TestNewPage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    NewPage
+		wantErr string
+	}{
+		{"valid", NewPage{SpaceKey: "ENG", Title: "Runbook"}, ""},
+		{"valid with parent", NewPage{SpaceKey: "ENG", Title: "Runbook", ParentID: "123"}, ""},
+		{"missing space", NewPage{Title: "Runbook"}, "space key cannot be empty"},
+		{"missing title", NewPage{SpaceKey: "ENG"}, "title cannot be empty"},
+		{"invalid parent", NewPage{SpaceKey: "ENG", Title: "Runbook", ParentID: "abc"}, "invalid parent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.page.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPublishedPage_Write(t *testing.T) {
+	created := &PublishedPage{ID: "123", Title: "Runbook", Version: 1, URL: "https://example.atlassian.net/wiki/x"}
+
+	var buf strings.Builder
+	if err := created.Write(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(buf.String()), &parsed); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	for _, field := range []string{"id", "title", "version", "url"} {
+		if _, ok := parsed[field]; !ok {
+			t.Errorf("expected field %q in output", field)
+		}
+	}
+}
+
+func TestClient_CreatePage_Success(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/spaces":
+			if r.URL.Query().Get("keys") != "ENG" {
+				t.Errorf("expected keys=ENG, got %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"id": "65011", "key": "ENG"}},
+			})
+
+		case r.Method == "POST" && r.URL.Path == "/wiki/api/v2/pages":
+			var req createPageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if req.SpaceID != "65011" {
+				t.Errorf("expected spaceId 65011, got %s", req.SpaceID)
+			}
+			if req.ParentID != "42" {
+				t.Errorf("expected parentId 42, got %s", req.ParentID)
+			}
+			if req.Body.Representation != "storage" || req.Body.Value != "<p>Hello</p>" {
+				t.Errorf("unexpected body: %+v", req.Body)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":      "98765",
+				"title":   req.Title,
+				"version": map[string]interface{}{"number": 1},
+				"_links": map[string]string{
+					"webui": "/spaces/ENG/pages/98765/Runbook",
+					"base":  "https://acme.atlassian.net/wiki",
+				},
+			})
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{
+		Site:  strings.TrimPrefix(server.URL, "https://"),
+		Email: "test@example.com",
+		Token: "test-token",
+	}, false)
+	client.SetHTTPClient(server.Client())
+
+	created, err := client.CreatePage(context.Background(), &NewPage{
+		SpaceKey: "ENG",
+		Title:    "Runbook",
+		ParentID: "42",
+		Body:     "<p>Hello</p>",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if created.ID != "98765" || created.Version != 1 {
+		t.Errorf("unexpected created page: %+v", created)
+	}
+	if created.URL != "https://acme.atlassian.net/wiki/spaces/ENG/pages/98765/Runbook" {
+		t.Errorf("unexpected URL: %s", created.URL)
+	}
+}
+
+func TestClient_CreatePage_SpaceNotFound(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected no page to be created, got %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{
+		Site:  strings.TrimPrefix(server.URL, "https://"),
+		Email: "test@example.com",
+		Token: "test-token",
+	}, false)
+	client.SetHTTPClient(server.Client())
+
+	_, err := client.CreatePage(context.Background(), &NewPage{SpaceKey: "NOPE", Title: "x"})
+	if err == nil || !strings.Contains(err.Error(), "not_found") {
+		t.Errorf("expected not_found error, got %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// PageDiff is the difference between two versions of a page, compared as
+// Markdown.
+type PageDiff struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	From     int             `json:"from"`
+	To       int             `json:"to"`
+	Sections []SectionChange `json:"sections"`
+
+	fromMarkdown string
+	toMarkdown   string
+}
+
+// SectionChange is a section of the page, keyed by its heading, that was
+// added, removed or modified between two versions. Content before the first
+// heading has an empty heading.
+type SectionChange struct {
+	Heading string `json:"heading"`
+	Level   int    `json:"level"`
+	Change  string `json:"change"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
+
+// Write writes the diff with its changed sections as JSON to the given writer.
+func (d *PageDiff) Write(w interface{ Write([]byte) (int, error) }) error {
+	if d.Sections == nil {
+		d.Sections = []SectionChange{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// Unified returns the diff in unified format, or "" if the versions convert
+// to the same Markdown.
+func (d *PageDiff) Unified() string {
+	return unifiedDiff(d.fromMarkdown, d.toMarkdown,
+		fmt.Sprintf("%s (version %d)", d.Title, d.From),
+		fmt.Sprintf("%s (version %d)", d.Title, d.To))
+}
+
+// DiffPage compares two versions of a page after converting both to
+// Markdown. Version 0 for to means the current version.
+func (c *Client) DiffPage(ctx context.Context, id string, from, to int) (*PageDiff, error) {
+	if from < 1 {
+		return nil, fmt.Errorf("from version must be a positive number")
+	}
+
+	toPage, err := c.fetchPageVersion(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	fromPage, err := c.fetchPageVersion(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	fromMarkdown, err := ToMarkdown(fromPage.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert version %d to markdown: %w", from, err)
+	}
+	toMarkdown, err := ToMarkdown(toPage.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert version %d to markdown: %w", toPage.Version, err)
+	}
+
+	return &PageDiff{
+		ID:           id,
+		Title:        toPage.Title,
+		From:         fromPage.Version,
+		To:           toPage.Version,
+		Sections:     diffSections(fromMarkdown, toMarkdown),
+		fromMarkdown: fromMarkdown,
+		toMarkdown:   toMarkdown,
+	}, nil
+}
+
+// markdownSection is a heading and the lines up to the next heading.
+type markdownSection struct {
+	heading string
+	level   int
+	text    string
+}
+
+// splitMarkdownSections splits Markdown at ATX headings outside code fences.
+// Content before the first heading becomes a section with an empty heading,
+// and is left out when blank.
+func splitMarkdownSections(markdown string) []markdownSection {
+	var sections []markdownSection
+	current := markdownSection{}
+	var lines []string
+	flush := func() {
+		current.text = strings.TrimSpace(strings.Join(lines, "\n"))
+		if current.heading != "" || current.text != "" {
+			sections = append(sections, current)
+		}
+	}
+
+	fence := ""
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		} else if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+		} else if level, heading, ok := parseATXHeading(line); ok {
+			flush()
+			current = markdownSection{heading: heading, level: level}
+			lines = nil
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return sections
+}
+
+// parseATXHeading parses a "# Heading" line. A closing sequence of #s is
+// only stripped when whitespace precedes it, so "# C#" keeps its #.
+func parseATXHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+	heading := strings.TrimSpace(line[level:])
+	if trimmed := strings.TrimRight(heading, "#"); trimmed == "" {
+		heading = ""
+	} else if trimmed != heading && strings.TrimRight(trimmed, " \t") != trimmed {
+		heading = strings.TrimSpace(trimmed)
+	}
+	return level, heading, true
+}
+
+// diffSections compares two Markdown documents section by section. Sections
+// are matched by heading; repeated headings are matched in order.
+func diffSections(from, to string) []SectionChange {
+	key := func(sections []markdownSection) []string {
+		keys := make([]string, len(sections))
+		count := make(map[string]int)
+		for i, s := range sections {
+			keys[i] = fmt.Sprintf("%s\x00%d", s.heading, count[s.heading])
+			count[s.heading]++
+		}
+		return keys
+	}
+
+	fromSections, toSections := splitMarkdownSections(from), splitMarkdownSections(to)
+	fromKeys, toKeys := key(fromSections), key(toSections)
+	fromByKey := make(map[string]markdownSection)
+	for i, s := range fromSections {
+		fromByKey[fromKeys[i]] = s
+	}
+
+	var changes []SectionChange
+	matched := make(map[string]bool)
+	for i, s := range toSections {
+		old, ok := fromByKey[toKeys[i]]
+		switch {
+		case !ok:
+			changes = append(changes, SectionChange{Heading: s.heading, Level: s.level, Change: "added", To: s.text})
+		case old.text != s.text:
+			changes = append(changes, SectionChange{Heading: s.heading, Level: s.level, Change: "modified", From: old.text, To: s.text})
+		}
+		matched[toKeys[i]] = true
+	}
+	for i, s := range fromSections {
+		if !matched[fromKeys[i]] {
+			changes = append(changes, SectionChange{Heading: s.heading, Level: s.level, Change: "removed", From: s.text})
+		}
+	}
+	return changes
+}
+
+// diffOp is one line of an edit script: ' ' keeps, '-' deletes and '+'
+// inserts a line.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines returns an edit script turning a into b, using Myers' algorithm.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+
+	// trace[d] holds v for diagonals -d..d before step d
+	var trace [][]int
+	var d int
+search:
+	for d = 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk back from the end, collecting the script in reverse
+	var ops []diffOp
+	x, y := n, m
+	for ; d > 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[y-1]})
+		} else {
+			ops = append(ops, diffOp{'-', a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{' ', a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// unifiedDiff returns a unified diff of two texts, or "" if they are equal.
+func unifiedDiff(from, to, fromName, toName string) string {
+	if from == to {
+		return ""
+	}
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	// Line numbers in a and b before each op
+	aLine, bLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if op.kind != '+' {
+			aLine[i+1]++
+		}
+		if op.kind != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("--- " + fromName + "\n")
+	b.WriteString("+++ " + toName + "\n")
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		start := max(0, i-diffContext)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		end = min(len(ops), end+diffContext)
+
+		b.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			hunkRange(aLine[start], aLine[end]-aLine[start]),
+			hunkRange(bLine[start], bLine[end]-bLine[start])))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line + "\n")
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats the line range of a hunk, where before is the number of
+// lines preceding it.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitLines splits text into lines without a trailing empty line.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
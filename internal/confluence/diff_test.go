@@ -0,0 +1,178 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name:     "identical",
+			from:     "a\nb",
+			to:       "a\nb",
+			expected: "",
+		},
+		{
+			name:     "changed line with context",
+			from:     "1\n2\n3\n4\n5\n6\n7\n8",
+			to:       "1\n2\n3\n4\nfive\n6\n7\n8",
+			expected: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "distant changes get separate hunks",
+			from: "a\n1\n2\n3\n4\n5\n6\n7\n8\nz",
+			to:   "A\n1\n2\n3\n4\n5\n6\n7\n8\nZ",
+			expected: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-z\n+Z\n",
+		},
+		{
+			name:     "insertion into empty",
+			from:     "",
+			to:       "new",
+			expected: "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n",
+		},
+		{
+			name:     "deletion at end",
+			from:     "keep\ndrop",
+			to:       "keep",
+			expected: "--- a\n+++ b\n@@ -1,2 +1 @@\n keep\n-drop\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff(tt.from, tt.to, "a", "b"); got != tt.expected {
+				t.Errorf("got:\n%s\nexpected:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseATXHeading(t *testing.T) {
+	tests := []struct {
+		line    string
+		level   int
+		heading string
+		ok      bool
+	}{
+		{line: "## Rollout plan", level: 2, heading: "Rollout plan", ok: true},
+		{line: "## Rollout plan ##", level: 2, heading: "Rollout plan", ok: true},
+		{line: "# C#", level: 1, heading: "C#", ok: true},
+		{line: "# C# ##", level: 1, heading: "C#", ok: true},
+		{line: "### F##", level: 3, heading: "F##", ok: true},
+		{line: "# ###", level: 1, heading: "", ok: true},
+		{line: "#hashtag"},
+		{line: "####### Too deep"},
+	}
+	for _, tt := range tests {
+		level, heading, ok := parseATXHeading(tt.line)
+		if level != tt.level || heading != tt.heading || ok != tt.ok {
+			t.Errorf("parseATXHeading(%q) = %d, %q, %v, want %d, %q, %v", tt.line, level, heading, ok, tt.level, tt.heading, tt.ok)
+		}
+	}
+}
+
+func TestDiffSections(t *testing.T) {
+	from := "Intro\n\n## Setup\n\nRun make\n\n## Usage\n\nCall it\n\n## Old\n\nGone"
+	to := "Intro\n\n## Setup\n\nRun make install\n\n```sh\n# not a heading\n```\n\n## Usage\n\nCall it\n\n## New\n\nAdded"
+
+	changes := diffSections(from, to)
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Change+":"+c.Heading)
+	}
+	expected := []string{"modified:Setup", "added:New", "removed:Old"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("got %v, expected %v", got, expected)
+	}
+	if !strings.Contains(changes[0].To, "# not a heading") || changes[0].Level != 2 {
+		t.Errorf("expected fenced comment to stay in the Setup section, got %+v", changes[0])
+	}
+}
+
+func TestClient_DiffPage(t *testing.T) {
+	bodies := map[string]string{
+		"2": "<h2>Setup</h2><p>Run make</p>",
+		"":  "<h2>Setup</h2><p>Run make install</p>",
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wiki/api/v2/pages/123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		version := r.URL.Query().Get("version")
+		number := 5
+		if version == "2" {
+			number = 2
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id": "123", "title": "Runbook", "version": map[string]int{"number": number},
+			"body": map[string]interface{}{"storage": map[string]string{"value": bodies[version]}},
+		})
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	diff, err := client.DiffPage(context.Background(), "123", 2, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.From != 2 || diff.To != 5 {
+		t.Errorf("expected versions 2 to 5, got %d to %d", diff.From, diff.To)
+	}
+	if len(diff.Sections) != 1 || diff.Sections[0].Heading != "Setup" || diff.Sections[0].Change != "modified" {
+		t.Errorf("unexpected sections: %+v", diff.Sections)
+	}
+	unified := diff.Unified()
+	if !strings.Contains(unified, "--- Runbook (version 2)") || !strings.Contains(unified, "-Run make\n+Run make install\n") {
+		t.Errorf("unexpected unified diff:\n%s", unified)
+	}
+}
+
+func TestClient_ListVersions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wiki/api/v2/pages/123/versions":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]interface{}{
+					{"number": 2, "authorId": "u1", "createdAt": "2024-02-01T10:00:00Z", "message": "Fix typo", "minorEdit": true},
+					{"number": 1, "authorId": "u2", "createdAt": "2024-01-01T10:00:00Z"},
+				},
+			})
+		case "/wiki/rest/api/user/bulk":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"accountId": "u1", "displayName": "Ada"}},
+			})
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	versions, err := client.ListVersions(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(versions))
+	}
+	if v := versions[0]; v.Number != 2 || v.Author != "Ada" || v.Message != "Fix typo" || !v.MinorEdit {
+		t.Errorf("unexpected version: %+v", v)
+	}
+	if v := versions[1]; v.Author != "" || v.AuthorID != "u2" {
+		t.Errorf("expected unresolved author to keep only its ID, got %+v", v)
+	}
+}
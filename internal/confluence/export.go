@@ -0,0 +1,691 @@
+package confluence
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportManifestFile is the file in the output directory that records what
+// a previous export wrote, so that re-runs can skip unchanged pages.
+const ExportManifestFile = ".atl-export.json"
+
+// exportAttachmentDir is the subdirectory of a page's directory that holds
+// its attachments, so they cannot collide with index.md or child pages.
+const exportAttachmentDir = "attachments"
+
+// ExportOptions selects what to export and where. Exactly one of SpaceKey
+// and PageID must be set.
+type ExportOptions struct {
+	SpaceKey string // export every page in the space
+	PageID   string // export a page and its descendants
+	OutDir   string
+	// Chunks, if set, writes each page as JSON Lines chunks (see Chunk) to
+	// chunks.jsonl instead of Markdown to index.md. Links then point at the
+	// site, and attachments are not downloaded.
+	Chunks *ChunkOptions
+}
+
+// ExportedPage describes one page of an export.
+type ExportedPage struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Version     int    `json:"version"`
+	Path        string `json:"path"`
+	Status      string `json:"status"` // "exported" or "unchanged"
+	Attachments int    `json:"attachments"`
+}
+
+// ExportResult summarises an export.
+type ExportResult struct {
+	Out       string          `json:"out"`
+	Exported  int             `json:"exported"`
+	Unchanged int             `json:"unchanged"`
+	Pages     []*ExportedPage `json:"pages"`
+}
+
+// Write writes the export summary as JSON to the given writer.
+func (r *ExportResult) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// exportManifest is the content of ExportManifestFile.
+type exportManifest struct {
+	Pages map[string]*manifestEntry `json:"pages"`
+}
+
+// manifestEntry records the exported version and location of a page, the
+// chunk options it was split with, if any, and the versions of its
+// downloaded attachments, keyed by their path relative to the page
+// directory.
+type manifestEntry struct {
+	Version     int            `json:"version"`
+	Path        string         `json:"path"`
+	Chunks      *ChunkOptions  `json:"chunks,omitempty"`
+	Attachments map[string]int `json:"attachments,omitempty"`
+}
+
+// frontMatter is the YAML header written at the top of each exported page.
+type frontMatter struct {
+	ID      string   `yaml:"id"`
+	Title   string   `yaml:"title"`
+	Version int      `yaml:"version"`
+	Updated string   `yaml:"updated"`
+	Parent  string   `yaml:"parent"`
+	Labels  []string `yaml:"labels"`
+}
+
+// exportNode is a page to export, its current version and the directory (relative to the output
+// directory, slash-separated) that holds its index.md and attachments.
+type exportNode struct {
+	id       string
+	title    string
+	parentID string
+	version  int
+	dir      string
+}
+
+// exportIndex is what an export knows about the pages it writes: their
+// directories by title and IDs by directory, for resolving links, and the
+// attachments listed so far, by page ID.
+type exportIndex struct {
+	spaceKey    string
+	paths       map[string]string
+	ids         map[string]string
+	attachments map[string]AttachmentList
+}
+
+// Export writes pages as Markdown files with YAML front matter into a
+// directory tree that mirrors the page hierarchy: each page becomes
+// <dir>/index.md, with its attachments in <dir>/attachments and its children
+// in subdirectories. Links between exported pages are rewritten to relative
+// paths. Pages whose version matches the previous export are not fetched or
+// rewritten, and their attachments are not checked.
+func (c *Client) Export(ctx context.Context, opts ExportOptions) (*ExportResult, error) {
+	if (opts.SpaceKey == "") == (opts.PageID == "") {
+		return nil, fmt.Errorf("exactly one of space key or page ID is required")
+	}
+	if opts.OutDir == "" {
+		return nil, fmt.Errorf("output directory cannot be empty")
+	}
+	if opts.Chunks != nil {
+		if err := opts.Chunks.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	spaceKey, roots, pages, err := c.exportPages(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	nodes := exportTree(roots, pages)
+
+	// Links are resolved by title, which is unique within a space
+	index := &exportIndex{
+		spaceKey:    spaceKey,
+		paths:       make(map[string]string, len(nodes)),
+		ids:         make(map[string]string, len(nodes)),
+		attachments: make(map[string]AttachmentList),
+	}
+	for _, node := range nodes {
+		index.paths[node.title] = node.dir
+		index.ids[node.dir] = node.id
+	}
+
+	manifest, err := readExportManifest(opts.OutDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExportResult{Out: opts.OutDir, Pages: []*ExportedPage{}}
+	for _, node := range nodes {
+		page, err := c.exportPage(ctx, opts, node, index, manifest)
+		if err != nil {
+			// Keep the progress made so far for the next run
+			writeExportManifest(opts.OutDir, manifest)
+			return nil, err
+		}
+		if page.Status == "exported" {
+			result.Exported++
+		} else {
+			result.Unchanged++
+		}
+		result.Pages = append(result.Pages, page)
+	}
+
+	if err := writeExportManifest(opts.OutDir, manifest); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// exportPages returns the key of the space being exported and the pages at
+// the top of the export, and lists every page in the space with its
+// version. One listing of the space gives the whole tree, so pages that
+// have not changed since the last export need no requests of their own.
+func (c *Client) exportPages(ctx context.Context, opts ExportOptions) (string, []exportNode, []apiPageSummary, error) {
+	var spaceKey, spaceID string
+	var roots []exportNode
+	if opts.PageID != "" {
+		if err := ValidatePageID(opts.PageID); err != nil {
+			return "", nil, nil, err
+		}
+		summary, err := c.pageSummary(ctx, opts.PageID)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		spaceID = summary.SpaceID
+		roots = []exportNode{{id: summary.ID, title: summary.Title, parentID: summary.ParentID, version: summary.Version.Number}}
+
+		// The key is only needed to recognise same-space links
+		if space, err := c.GetSpaceByID(ctx, summary.SpaceID); err == nil {
+			spaceKey = space.Key
+		}
+	} else {
+		space, err := c.GetSpace(ctx, opts.SpaceKey)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		spaceKey, spaceID = space.Key, space.ID
+	}
+
+	var pages []apiPageSummary
+	pagesPath := fmt.Sprintf("/wiki/api/v2/spaces/%s/pages?limit=250", url.PathEscape(spaceID))
+	err := c.getPaged(ctx, pagesPath, func(body []byte) error {
+		var resp struct {
+			Results []apiPageSummary `json:"results"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		pages = append(pages, resp.Results...)
+		return nil
+	})
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if opts.PageID == "" {
+		// Pages whose parent is not a page in the space are at the top
+		listed := make(map[string]bool, len(pages))
+		for _, p := range pages {
+			listed[p.ID] = true
+		}
+		for _, p := range sortedPages(pages) {
+			if !listed[p.ParentID] {
+				roots = append(roots, exportNode{id: p.ID, title: p.Title, parentID: p.ParentID, version: p.Version.Number})
+			}
+		}
+	}
+	return spaceKey, roots, pages, nil
+}
+
+// sortedPages returns pages in position order, keeping the listing order
+// for ties.
+func sortedPages(pages []apiPageSummary) []apiPageSummary {
+	sorted := slices.Clone(pages)
+	slices.SortStableFunc(sorted, func(a, b apiPageSummary) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+	return sorted
+}
+
+// exportTree walks the descendants of roots depth-first through the pages
+// of the space and assigns each page a directory. Siblings whose titles map
+// to the same directory name, or to a name the export writes itself, are
+// told apart by their page ID.
+func exportTree(roots []exportNode, pages []apiPageSummary) []exportNode {
+	children := make(map[string][]exportNode)
+	for _, p := range sortedPages(pages) {
+		children[p.ParentID] = append(children[p.ParentID], exportNode{id: p.ID, title: p.Title, parentID: p.ParentID, version: p.Version.Number})
+	}
+
+	var nodes []exportNode
+	var walk func(level []exportNode, parentDir string)
+	walk = func(level []exportNode, parentDir string) {
+		used := map[string]bool{"index.md": true, "chunks.jsonl": true, exportAttachmentDir: true}
+		for _, node := range level {
+			name := exportSlug(node.title, node.id)
+			if used[strings.ToLower(name)] {
+				name += "-" + node.id
+			}
+			used[strings.ToLower(name)] = true
+			node.dir = path.Join(parentDir, name)
+			nodes = append(nodes, node)
+			walk(children[node.id], node.dir)
+		}
+	}
+	walk(roots, "")
+	return nodes
+}
+
+// exportPage writes one page and its attachments, updating manifest. A page
+// whose version matches the manifest is left alone, attachments included.
+func (c *Client) exportPage(ctx context.Context, opts ExportOptions, node exportNode, index *exportIndex, manifest *exportManifest) (*ExportedPage, error) {
+	outDir := opts.OutDir
+	file := path.Join(node.dir, "index.md")
+	if opts.Chunks != nil {
+		file = path.Join(node.dir, "chunks.jsonl")
+	}
+	result := &ExportedPage{
+		ID:      node.id,
+		Title:   node.title,
+		Version: node.version,
+		Path:    file,
+		Status:  "unchanged",
+	}
+
+	entry := manifest.Pages[node.id]
+	if entry == nil {
+		entry = &manifestEntry{}
+		manifest.Pages[node.id] = entry
+	}
+
+	// Chunks written with other limits are stale even if the page is not
+	chunksChanged := (entry.Chunks == nil) != (opts.Chunks == nil) ||
+		entry.Chunks != nil && *entry.Chunks != *opts.Chunks
+	_, statErr := os.Stat(filepath.Join(outDir, filepath.FromSlash(file)))
+	if entry.Version == node.version && entry.Path == file && !chunksChanged && statErr == nil {
+		return result, nil
+	}
+
+	page, err := c.fetchPage(ctx, node.id)
+	if err != nil {
+		return nil, err
+	}
+	var content string
+	if opts.Chunks != nil {
+		content, err = c.exportChunks(page, *opts.Chunks)
+	} else {
+		content, err = c.exportMarkdown(ctx, page, node, index)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(outDir, filepath.FromSlash(file)), strings.NewReader(content)); err != nil {
+		return nil, err
+	}
+
+	// Remove the copy left behind when a page moves or is renamed
+	if entry.Path != "" && entry.Path != file {
+		os.Remove(filepath.Join(outDir, filepath.FromSlash(entry.Path)))
+	}
+
+	if opts.Chunks == nil {
+		downloaded, err := c.exportPageAttachments(ctx, outDir, node, index, entry)
+		if err != nil {
+			return nil, err
+		}
+		result.Attachments = downloaded
+	}
+
+	// Recorded last, so that a page whose attachments failed is retried
+	entry.Version = page.Version
+	entry.Path = file
+	entry.Chunks = opts.Chunks
+	result.Version = page.Version
+	result.Status = "exported"
+	return result, nil
+}
+
+// exportPageAttachments downloads the attachments of a page that are new or
+// have changed since the last export, and returns how many it downloaded.
+// Attachments are only checked when the page itself is exported: one added
+// without editing the page is picked up with the page's next version.
+func (c *Client) exportPageAttachments(ctx context.Context, outDir string, node exportNode, index *exportIndex, entry *manifestEntry) (int, error) {
+	attachments, err := c.exportAttachments(ctx, index, node.id)
+	if err != nil {
+		return 0, err
+	}
+	if entry.Attachments == nil {
+		entry.Attachments = make(map[string]int)
+	}
+	// Earlier exports wrote attachments next to index.md
+	for name := range entry.Attachments {
+		if path.Dir(name) != exportAttachmentDir {
+			if name != "index.md" && name != "chunks.jsonl" {
+				os.Remove(filepath.Join(outDir, filepath.FromSlash(node.dir), name))
+			}
+			delete(entry.Attachments, name)
+		}
+	}
+
+	downloaded := 0
+	names := attachmentNames(attachments)
+	for _, att := range attachments {
+		name := path.Join(exportAttachmentDir, names[att.Filename])
+		dest := filepath.Join(outDir, filepath.FromSlash(node.dir), filepath.FromSlash(name))
+		if _, err := os.Stat(dest); err == nil && entry.Attachments[name] == att.Version {
+			continue
+		}
+
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(c.DownloadAttachment(ctx, att, pw))
+		}()
+		err := writeFileAtomic(dest, pr)
+		pr.Close()
+		if err != nil {
+			return downloaded, err
+		}
+		entry.Attachments[name] = att.Version
+		downloaded++
+	}
+	return downloaded, nil
+}
+
+// exportMarkdown returns the content of a page's index.md: YAML front matter
+// and the body as Markdown, with links to exported pages made relative.
+func (c *Client) exportMarkdown(ctx context.Context, page *Page, node exportNode, index *exportIndex) (string, error) {
+	labels, err := c.GetLabels(ctx, node.id)
+	if err != nil {
+		return "", err
+	}
+
+	// Attachments of other exported pages are looked up by the directory
+	// their page is exported to. A failed lookup leaves the link to fall
+	// back to the plain file name.
+	attachmentName := func(dir, filename string) string {
+		id, ok := index.ids[dir]
+		if !ok {
+			return ""
+		}
+		attachments, err := c.exportAttachments(ctx, index, id)
+		if err != nil {
+			return ""
+		}
+		return attachmentNames(attachments)[filename]
+	}
+	storage := rewriteExportLinks(page.Body, node.dir, index.spaceKey, index.paths, attachmentName)
+	markdown, err := ToMarkdown(storage)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert page %s: %w", node.id, err)
+	}
+
+	header, err := yaml.Marshal(&frontMatter{
+		ID:      page.ID,
+		Title:   page.Title,
+		Version: page.Version,
+		Updated: page.Updated,
+		Parent:  node.parentID,
+		Labels:  labels,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to write front matter: %w", err)
+	}
+	return "---\n" + string(header) + "---\n\n" + markdown + "\n", nil
+}
+
+// exportAttachments lists a page's attachments once per export.
+func (c *Client) exportAttachments(ctx context.Context, index *exportIndex, id string) (AttachmentList, error) {
+	if attachments, ok := index.attachments[id]; ok {
+		return attachments, nil
+	}
+	attachments, err := c.ListAttachments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	index.attachments[id] = attachments
+	return attachments, nil
+}
+
+// attachmentNames returns the file names attachments are exported under in
+// their page's attachments directory, keyed by attachment filename. Names
+// are slugs of the filename; attachments whose slugs collide (ignoring
+// case) get their ID appended, so none overwrites another.
+func attachmentNames(attachments AttachmentList) map[string]string {
+	count := make(map[string]int, len(attachments))
+	for _, att := range attachments {
+		count[strings.ToLower(exportSlug(att.Filename, att.ID))]++
+	}
+	names := make(map[string]string, len(attachments))
+	for _, att := range attachments {
+		name := exportSlug(att.Filename, att.ID)
+		if count[strings.ToLower(name)] > 1 {
+			ext := path.Ext(name)
+			name = strings.TrimSuffix(name, ext) + "-" + att.ID + ext
+		}
+		names[att.Filename] = name
+	}
+	return names
+}
+
+// exportChunks returns the content of a page's chunks.jsonl.
+func (c *Client) exportChunks(page *Page, opts ChunkOptions) (string, error) {
+	page.refs = &References{BaseURL: c.cfg.BaseURL(), PageID: page.ID}
+	chunks, err := page.Chunks(opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert page %s: %w", page.ID, err)
+	}
+	var b strings.Builder
+	if err := chunks.Write(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+var slugSeparatorRe = regexp.MustCompile(`-{2,}`)
+
+// rewriteExportLinks replaces Confluence links and images that point at
+// exported pages or their attachments with plain HTML links relative to
+// pageDir, so that they survive conversion to Markdown. Links the export
+// cannot resolve are left for ToMarkdown to handle. attachmentName returns
+// the exported file name of an attachment of the page in a directory, or ""
+// to use the slug of its filename. Storage that cannot be parsed is
+// returned as it is.
+func rewriteExportLinks(storage, pageDir, spaceKey string, paths map[string]string, attachmentName func(dir, filename string) string) string {
+	root, err := parseStorage(storage)
+	if err != nil {
+		return storage
+	}
+
+	// target returns the directory of the page a ri:page element refers to
+	target := func(page *storageNode) (string, bool) {
+		if key := page.attr("ri:space-key"); key != "" && key != spaceKey {
+			return "", false
+		}
+		dir, ok := paths[page.attr("ri:content-title")]
+		return dir, ok
+	}
+
+	// attachmentPath returns the path of an attachment, which may belong to
+	// another page
+	attachmentPath := func(att *storageNode) (string, bool) {
+		dir := pageDir
+		if page := att.child("ri:page"); page != nil {
+			var ok bool
+			if dir, ok = target(page); !ok {
+				return "", false
+			}
+		}
+		filename := att.attr("ri:filename")
+		name := attachmentName(dir, filename)
+		if name == "" {
+			name = exportSlug(filename, "")
+		}
+		return relativeLink(pageDir, path.Join(dir, exportAttachmentDir, name)), name != ""
+	}
+
+	// link returns the HTML link that replaces an ac:link, or nil to keep it
+	link := func(n *storageNode) *storageNode {
+		anchor := n.attr("ac:anchor")
+		var href, fallback string
+		if att := n.child("ri:attachment"); att != nil {
+			p, ok := attachmentPath(att)
+			if !ok {
+				return nil
+			}
+			href, fallback = p, att.attr("ri:filename")
+		} else if page := n.child("ri:page"); page != nil {
+			dir, ok := target(page)
+			if !ok {
+				return nil
+			}
+			href = relativeLink(pageDir, path.Join(dir, "index.md"))
+			fallback = page.attr("ri:content-title")
+		} else if anchor == "" {
+			return nil
+		}
+		if anchor != "" {
+			href += "#" + url.PathEscape(anchor)
+		}
+
+		a := &storageNode{name: "a", attrs: []storageAttr{{name: "href", value: href}}}
+		if body := n.child("ac:plain-text-link-body"); body != nil && body.textContent() != "" {
+			a.children = []*storageNode{{text: body.textContent()}}
+		} else if body := n.child("ac:link-body"); body != nil && len(body.children) > 0 {
+			a.children = body.children
+		} else if fallback != "" {
+			a.children = []*storageNode{{text: fallback}}
+		} else {
+			a.children = []*storageNode{{text: anchor}}
+		}
+		return a
+	}
+
+	// image returns the img element that replaces an ac:image, or nil to
+	// keep it
+	image := func(n *storageNode) *storageNode {
+		var src, alt string
+		if att := n.child("ri:attachment"); att != nil {
+			p, ok := attachmentPath(att)
+			if !ok {
+				return nil
+			}
+			src, alt = p, att.attr("ri:filename")
+		} else if u := n.child("ri:url"); u != nil {
+			src = u.attr("ri:value")
+		} else {
+			return nil
+		}
+		return &storageNode{name: "img", attrs: []storageAttr{{name: "src", value: src}, {name: "alt", value: alt}}}
+	}
+
+	var walk func(n *storageNode)
+	walk = func(n *storageNode) {
+		for i, child := range n.children {
+			var replacement *storageNode
+			switch child.name {
+			case "ac:link":
+				replacement = link(child)
+			case "ac:image":
+				replacement = image(child)
+			}
+			if replacement != nil {
+				n.children[i] = replacement
+			}
+			walk(n.children[i])
+		}
+	}
+	walk(root)
+
+	var b strings.Builder
+	root.writeStorage(&b)
+	return b.String()
+}
+
+// relativeLink returns a URL path to target (relative to the output
+// directory) from a page in fromDir.
+func relativeLink(fromDir, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash("/"+fromDir), filepath.FromSlash("/"+target))
+	if err != nil {
+		rel = target
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// exportSlug turns a title into a file or directory name that is safe on
+// common filesystems, falling back to fallback if nothing usable remains.
+func exportSlug(title, fallback string) string {
+	var b strings.Builder
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	slug := slugSeparatorRe.ReplaceAllString(b.String(), "-")
+	slug = strings.Trim(slug, "-.")
+	if slug == "" {
+		return fallback
+	}
+	return slug
+}
+
+// readExportManifest loads the manifest from a previous export, if any.
+func readExportManifest(outDir string) (*exportManifest, error) {
+	manifest := &exportManifest{Pages: make(map[string]*manifestEntry)}
+	data, err := os.ReadFile(filepath.Join(outDir, ExportManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse export manifest: %w", err)
+	}
+	if manifest.Pages == nil {
+		manifest.Pages = make(map[string]*manifestEntry)
+	}
+	return manifest, nil
+}
+
+// writeExportManifest saves the manifest for the next export.
+func writeExportManifest(outDir string, manifest *exportManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export manifest: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(outDir, ExportManifestFile), strings.NewReader(string(data)+"\n"))
+}
+
+// writeFileAtomic writes r to name via a temporary file, so an interrupted
+// export never leaves a truncated file in place.
+func writeFileAtomic(name string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".atl-export-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,440 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportSlug(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Service A", "Service-A"},
+		{"API: v2 / Auth", "API-v2-Auth"},
+		{"diagram.png", "diagram.png"},
+		{"Über uns", "Über-uns"},
+		{"../etc", "etc"},
+		{"???", "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := exportSlug(tt.title, "fallback"); got != tt.want {
+				t.Errorf("exportSlug(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteExportLinks(t *testing.T) {
+	paths := map[string]string{
+		"Root":      "Root",
+		"Service A": "Root/Service-A",
+		"Runbook":   "Root/Service-A/Runbook",
+		"R&D > Ops": "Root/R-D-Ops",
+	}
+
+	attachmentName := func(dir, filename string) string {
+		if dir == "Root/Service-A" && filename == "a b.png" {
+			return "a-b-att7.png"
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name    string
+		storage string
+		want    string
+	}{
+		{
+			name:    "child page with link text",
+			storage: `<ac:link><ri:page ri:content-title="Runbook"/><ac:plain-text-link-body><![CDATA[the runbook]]></ac:plain-text-link-body></ac:link>`,
+			want:    `<a href="Runbook/index.md">the runbook</a>`,
+		},
+		{
+			name:    "parent page falls back to title",
+			storage: `<ac:link><ri:page ri:content-title="Root" ri:space-key="ENG"/></ac:link>`,
+			want:    `<a href="../index.md">Root</a>`,
+		},
+		{
+			name:    "anchor on another page",
+			storage: `<ac:link ac:anchor="alerts"><ri:page ri:content-title="Runbook"/><ac:link-body><em>Alerts</em></ac:link-body></ac:link>`,
+			want:    `<a href="Runbook/index.md#alerts"><em>Alerts</em></a>`,
+		},
+		{
+			name:    "anchor on same page",
+			storage: `<ac:link ac:anchor="setup"><ac:plain-text-link-body><![CDATA[Setup]]></ac:plain-text-link-body></ac:link>`,
+			want:    `<a href="#setup">Setup</a>`,
+		},
+		{
+			name:    "attachment link",
+			storage: `<ac:link><ri:attachment ri:filename="Design Doc.pdf"/></ac:link>`,
+			want:    `<a href="attachments/Design-Doc.pdf">Design Doc.pdf</a>`,
+		},
+		{
+			name:    "attachment image",
+			storage: `<ac:image ac:width="400"><ri:attachment ri:filename="arch.png"/></ac:image>`,
+			want:    `<img src="attachments/arch.png" alt="arch.png"/>`,
+		},
+		{
+			name:    "image attached to another page",
+			storage: `<ac:image><ri:attachment ri:filename="logo.png"><ri:page ri:content-title="Root"/></ri:attachment></ac:image>`,
+			want:    `<img src="../attachments/logo.png" alt="logo.png"/>`,
+		},
+		{
+			name:    "attachment exported under a de-duplicated name",
+			storage: `<ac:image><ri:attachment ri:filename="a b.png"/></ac:image>`,
+			want:    `<img src="attachments/a-b-att7.png" alt="a b.png"/>`,
+		},
+		{
+			name:    "attributes in another order with an end tag",
+			storage: `<ac:link><ri:page ri:space-key="ENG" ri:content-title="Runbook"></ri:page><ac:plain-text-link-body><![CDATA[R & D]]></ac:plain-text-link-body></ac:link>`,
+			want:    `<a href="Runbook/index.md">R &amp; D</a>`,
+		},
+		{
+			name:    "title with markup characters",
+			storage: `<ac:link><ri:page ri:content-title="R&amp;D &gt; Ops"/></ac:link>`,
+			want:    `<a href="../R-D-Ops/index.md">R&amp;D &gt; Ops</a>`,
+		},
+		{
+			name:    "nested in a table and a macro body",
+			storage: `<table><tbody><tr><td><ac:structured-macro ac:name="info"><ac:rich-text-body><p><ac:link><ri:page ri:content-title="Runbook"/></ac:link></p></ac:rich-text-body></ac:structured-macro></td></tr></tbody></table>`,
+			want:    `<table><tbody><tr><td><ac:structured-macro ac:name="info"><ac:rich-text-body><p><a href="Runbook/index.md">Runbook</a></p></ac:rich-text-body></ac:structured-macro></td></tr></tbody></table>`,
+		},
+		{
+			name:    "image in a link body",
+			storage: `<ac:link><ri:page ri:content-title="Root"/><ac:link-body><ac:image><ri:attachment ri:filename="arch.png"/></ac:image></ac:link-body></ac:link>`,
+			want:    `<a href="../index.md"><img src="attachments/arch.png" alt="arch.png"/></a>`,
+		},
+		{
+			name:    "page in another space is kept",
+			storage: `<ac:link><ri:page ri:content-title="Runbook" ri:space-key="OPS"/></ac:link>`,
+			want:    `<ac:link><ri:page ri:content-title="Runbook" ri:space-key="OPS"/></ac:link>`,
+		},
+		{
+			name:    "page outside the export is kept",
+			storage: `<ac:link><ri:page ri:content-title="Elsewhere"/></ac:link>`,
+			want:    `<ac:link><ri:page ri:content-title="Elsewhere"/></ac:link>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rewriteExportLinks(tt.storage, "Root/Service-A", "ENG", paths, attachmentName)
+			if got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportTree(t *testing.T) {
+	pages := []apiPageSummary{
+		{ID: "3", Title: "Second", ParentID: "1", Position: 20},
+		{ID: "2", Title: "First", ParentID: "1", Position: 10},
+		{ID: "4", Title: "first", ParentID: "1", Position: 30},
+		{ID: "5", Title: "Nested", ParentID: "3"},
+		{ID: "6", Title: "Elsewhere", ParentID: "7"},
+	}
+	nodes := exportTree([]exportNode{{id: "1", title: "Home"}}, pages)
+
+	var dirs []string
+	for _, n := range nodes {
+		dirs = append(dirs, n.dir)
+	}
+	want := "Home,Home/First,Home/Second,Home/Second/Nested,Home/first-4"
+	if got := strings.Join(dirs, ","); got != want {
+		t.Errorf("dirs = %s, want %s", got, want)
+	}
+}
+
+// newExportTestServer serves a space ENG with a root page and one child
+// holding an attachment. version controls the child's version number, and
+// pageRequests counts the requests about individual pages.
+func newExportTestServer(t *testing.T, version *int, pageRequests *int) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		page := func(id, title, parent string, v int) map[string]interface{} {
+			return map[string]interface{}{
+				"id": id, "title": title, "status": "current", "parentId": parent, "spaceId": "9",
+				"version": map[string]interface{}{"number": v, "createdAt": "2026-01-20T15:45:00.000Z"},
+			}
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/wiki/api/v2/pages/") || strings.HasPrefix(r.URL.Path, "/wiki/download/") {
+			*pageRequests++
+		}
+
+		switch r.URL.Path {
+		case "/wiki/api/v2/spaces":
+			enc.Encode(map[string]interface{}{"results": []map[string]string{{"id": "9", "key": "ENG"}}})
+		case "/wiki/api/v2/spaces/9/pages":
+			enc.Encode(map[string]interface{}{"results": []interface{}{page("2", "Runbook", "1", *version), page("1", "Home", "", 1)}})
+		case "/wiki/api/v2/pages/1", "/wiki/api/v2/pages/2":
+			id := strings.TrimPrefix(r.URL.Path, "/wiki/api/v2/pages/")
+			p := page("1", "Home", "", 1)
+			body := `<p>See <ac:link><ri:page ri:content-title="Runbook"/></ac:link>.</p>`
+			if id == "2" {
+				p = page("2", "Runbook", "1", *version)
+				body = `<h1>Steps</h1><ac:image><ri:attachment ri:filename="flow.png"/></ac:image>`
+			}
+			if r.URL.Query().Get("body-format") == "storage" {
+				p["body"] = map[string]interface{}{"storage": map[string]string{"value": body}}
+			}
+			enc.Encode(p)
+		case "/wiki/api/v2/pages/1/labels":
+			enc.Encode(map[string]interface{}{"results": []interface{}{}})
+		case "/wiki/api/v2/pages/2/labels":
+			enc.Encode(map[string]interface{}{"results": []map[string]string{{"name": "ops"}}})
+		case "/wiki/api/v2/pages/1/attachments":
+			enc.Encode(map[string]interface{}{"results": []interface{}{}})
+		case "/wiki/api/v2/pages/2/attachments":
+			enc.Encode(map[string]interface{}{"results": []map[string]interface{}{{
+				"id": "att1", "title": "flow.png", "downloadLink": "/download/attachments/2/flow.png",
+				"version": map[string]int{"number": 1},
+			}}})
+		case "/wiki/download/attachments/2/flow.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("PNGDATA"))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_Export(t *testing.T) {
+	version, pageRequests := 3, 0
+	server := newExportTestServer(t, &version, &pageRequests)
+	defer server.Close()
+
+	out := t.TempDir()
+	client := newTestClient(server)
+
+	result, err := client.Export(context.Background(), ExportOptions{SpaceKey: "ENG", OutDir: out})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Exported != 2 || result.Unchanged != 0 {
+		t.Errorf("expected 2 exported, got %+v", result)
+	}
+
+	home, err := os.ReadFile(filepath.Join(out, "Home", "index.md"))
+	if err != nil {
+		t.Fatalf("expected Home/index.md: %v", err)
+	}
+	if !strings.HasPrefix(string(home), "---\nid: \"1\"\ntitle: Home\nversion: 1\n") {
+		t.Errorf("unexpected front matter:\n%s", home)
+	}
+	if !strings.Contains(string(home), "[Runbook](Runbook/index.md)") {
+		t.Errorf("expected relative link to child, got:\n%s", home)
+	}
+
+	runbook, err := os.ReadFile(filepath.Join(out, "Home", "Runbook", "index.md"))
+	if err != nil {
+		t.Fatalf("expected Home/Runbook/index.md: %v", err)
+	}
+	for _, want := range []string{`parent: "1"`, "labels:\n    - ops", "# Steps", "![flow.png](attachments/flow.png)"} {
+		if !strings.Contains(string(runbook), want) {
+			t.Errorf("expected %q in Runbook export:\n%s", want, runbook)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "Home", "Runbook", "attachments", "flow.png"))
+	if err != nil || string(data) != "PNGDATA" {
+		t.Errorf("expected attachment to be downloaded, got %q, %v", data, err)
+	}
+
+	// A re-run without changes only lists the space
+	pageRequests = 0
+	result, err = client.Export(context.Background(), ExportOptions{SpaceKey: "ENG", OutDir: out})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Exported != 0 || result.Unchanged != 2 || pageRequests != 0 {
+		t.Errorf("expected incremental re-run, got %+v with %d page requests", result, pageRequests)
+	}
+
+	// A new version is exported again
+	version = 4
+	result, err = client.Export(context.Background(), ExportOptions{SpaceKey: "ENG", OutDir: out})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Exported != 1 || result.Pages[1].Status != "exported" || result.Pages[1].Version != 4 {
+		t.Errorf("expected Runbook to be re-exported, got %+v", result.Pages[1])
+	}
+}
+
+func TestAttachmentNames(t *testing.T) {
+	names := attachmentNames(AttachmentList{
+		{ID: "att1", Filename: "a b.png"},
+		{ID: "att2", Filename: "a-b.png"},
+		{ID: "att3", Filename: "A-B.PNG"},
+		{ID: "att4", Filename: "index.md"},
+		{ID: "att5", Filename: "???"},
+	})
+	want := map[string]string{
+		"a b.png":  "a-b-att1.png",
+		"a-b.png":  "a-b-att2.png",
+		"A-B.PNG":  "A-B-att3.PNG",
+		"index.md": "index.md",
+		"???":      "att5",
+	}
+	for filename, name := range want {
+		if names[filename] != name {
+			t.Errorf("%s: got %q, want %q", filename, names[filename], name)
+		}
+	}
+}
+
+func TestClient_Export_AttachmentCollisions(t *testing.T) {
+	attachments := []map[string]interface{}{}
+	for i, name := range []string{"index.md", "a b.png", "a-b.png"} {
+		attachments = append(attachments, map[string]interface{}{
+			"id": fmt.Sprintf("att%d", i+1), "title": name, "downloadLink": "/download/attachments/1/" + url.PathEscape(name),
+			"version": map[string]int{"number": 1},
+		})
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		switch r.URL.Path {
+		case "/wiki/api/v2/pages/1":
+			p := map[string]interface{}{"id": "1", "title": "Home", "spaceId": "9", "version": map[string]int{"number": 1}}
+			if r.URL.Query().Get("body-format") == "storage" {
+				p["body"] = map[string]interface{}{"storage": map[string]string{"value": `<ac:image><ri:attachment ri:filename="a b.png"/></ac:image>`}}
+			}
+			enc.Encode(p)
+		case "/wiki/api/v2/pages/2":
+			enc.Encode(map[string]interface{}{"id": "2", "title": "attachments", "spaceId": "9", "version": map[string]int{"number": 1},
+				"body": map[string]interface{}{"storage": map[string]string{"value": `<p>child</p>`}}})
+		case "/wiki/api/v2/spaces/9":
+			w.Write([]byte(`{"id": "9", "key": "ENG"}`))
+		case "/wiki/api/v2/spaces/9/pages":
+			w.Write([]byte(`{"results": [{"id": "1", "title": "Home", "version": {"number": 1}}, {"id": "2", "title": "attachments", "parentId": "1", "version": {"number": 1}}]}`))
+		case "/wiki/api/v2/pages/1/labels", "/wiki/api/v2/pages/2/labels", "/wiki/api/v2/pages/2/attachments":
+			w.Write([]byte(`{"results": []}`))
+		case "/wiki/api/v2/pages/1/attachments":
+			enc.Encode(map[string]interface{}{"results": attachments})
+		default:
+			if name, ok := strings.CutPrefix(r.URL.Path, "/wiki/download/attachments/1/"); ok {
+				w.Write([]byte("data:" + name))
+				return
+			}
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// A previous export wrote attachments next to index.md
+	out := t.TempDir()
+	os.MkdirAll(filepath.Join(out, "Home"), 0o755)
+	os.WriteFile(filepath.Join(out, "Home", "old.png"), []byte("old"), 0o644)
+	os.WriteFile(filepath.Join(out, ExportManifestFile), []byte(`{"pages": {"1": {"version": 1, "path": "Home/index.md", "attachments": {"old.png": 1}}}}`), 0o644)
+
+	if _, err := newTestClient(server).Export(context.Background(), ExportOptions{PageID: "1", OutDir: out}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	home, err := os.ReadFile(filepath.Join(out, "Home", "index.md"))
+	if err != nil || !strings.HasPrefix(string(home), "---\nid: \"1\"") {
+		t.Fatalf("page was overwritten: %q, %v", home, err)
+	}
+	if !strings.Contains(string(home), "(attachments/a-b-att2.png)") {
+		t.Errorf("expected link to the de-duplicated name:\n%s", home)
+	}
+	for name, want := range map[string]string{"index.md": "data:index.md", "a-b-att2.png": "data:a b.png", "a-b-att3.png": "data:a-b.png"} {
+		data, err := os.ReadFile(filepath.Join(out, "Home", "attachments", name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s: got %q, %v", name, data, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "Home", "attachments-2", "index.md")); err != nil {
+		t.Errorf("expected the child page to be moved aside: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "Home", "old.png")); err == nil {
+		t.Error("expected the attachment from the old layout to be removed")
+	}
+}
+
+func TestClient_Export_RequiresOneSource(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := newTestClient(server).Export(context.Background(), ExportOptions{SpaceKey: "ENG", PageID: "1", OutDir: t.TempDir()})
+	if err == nil {
+		t.Error("expected error when both space and page are given")
+	}
+}
+
+func TestClient_Export_Chunks(t *testing.T) {
+	version, pageRequests := 3, 0
+	server := newExportTestServer(t, &version, &pageRequests)
+	defer server.Close()
+
+	out := t.TempDir()
+	client := newTestClient(server)
+
+	result, err := client.Export(context.Background(), ExportOptions{SpaceKey: "ENG", OutDir: out, Chunks: &ChunkOptions{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Exported != 2 || result.Pages[1].Path != "Home/Runbook/chunks.jsonl" || result.Pages[1].Attachments != 0 {
+		t.Errorf("unexpected result: %+v", result.Pages[1])
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "Home", "Runbook", "chunks.jsonl"))
+	if err != nil {
+		t.Fatalf("expected Home/Runbook/chunks.jsonl: %v", err)
+	}
+	var chunk Chunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		t.Fatalf("invalid chunk: %v\n%s", err, data)
+	}
+	if chunk.PageID != "2" || chunk.Version != 3 || len(chunk.HeadingPath) != 1 || chunk.HeadingPath[0] != "Steps" {
+		t.Errorf("unexpected chunk: %+v", chunk)
+	}
+	if !strings.Contains(chunk.Content, "/wiki/download/attachments/2/flow.png") {
+		t.Errorf("expected image to link to the site:\n%s", chunk.Content)
+	}
+	if _, err := os.Stat(filepath.Join(out, "Home", "Runbook", "attachments")); err == nil {
+		t.Error("attachments should not be downloaded")
+	}
+
+	// Same options and versions: nothing to do
+	result, err = client.Export(context.Background(), ExportOptions{SpaceKey: "ENG", OutDir: out, Chunks: &ChunkOptions{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Exported != 0 || result.Unchanged != 2 {
+		t.Errorf("expected no changes, got %+v", result)
+	}
+
+	// New chunk limits re-split every page
+	result, err = client.Export(context.Background(), ExportOptions{SpaceKey: "ENG", OutDir: out, Chunks: &ChunkOptions{MaxTokens: 50}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Exported != 2 {
+		t.Errorf("expected changed chunk options to re-export, got %+v", result)
+	}
+	manifest, err := readExportManifest(out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chunks := manifest.Pages["2"].Chunks; chunks == nil || chunks.MaxTokens != 50 {
+		t.Errorf("chunk options not recorded: %+v", chunks)
+	}
+}
@@ -1,6 +1,8 @@
 package confluence
 
 import (
+	"encoding/hex"
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -8,17 +10,26 @@ import (
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/table"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // ToMarkdown converts Confluence storage format (XHTML) to Markdown.
 // It handles Confluence-specific macros and converts standard HTML elements.
 func ToMarkdown(storageFormat string) (string, error) {
+	return ToMarkdownWithReferences(storageFormat, nil)
+}
+
+// ToMarkdownWithReferences converts storage format to Markdown like
+// ToMarkdown, using refs to resolve attachment URLs, user mentions, Jira
+// issue statuses and child pages. refs may be nil.
+func ToMarkdownWithReferences(storageFormat string, refs *References) (string, error) {
 	if strings.TrimSpace(storageFormat) == "" {
 		return "", nil
 	}
 
 	// Pre-process Confluence macros before HTML conversion
-	processed := preprocessConfluenceMacros(storageFormat)
+	processed := preprocessConfluenceMacros(storageFormat, refs)
 
 	// Create converter with plugins for full markdown support
 	conv := converter.NewConverter(
@@ -41,111 +52,21 @@ func ToMarkdown(storageFormat string) (string, error) {
 	return result, nil
 }
 
-// preprocessConfluenceMacros converts Confluence-specific macros to HTML
-// that can be processed by the markdown converter.
-func preprocessConfluenceMacros(html string) string {
-	// Handle TOC macro - use special marker that won't be escaped
-	html = regexp.MustCompile(`<ac:structured-macro[^>]*ac:name="toc"[^>]*>.*?</ac:structured-macro>`).
-		ReplaceAllString(html, "<p>CFPLACEHOLDER:TOC:</p>")
-
-	// Handle drawio macro - extract diagram name if present
-	html = regexp.MustCompile(`<ac:structured-macro[^>]*ac:name="drawio"[^>]*>(?:.*?<ac:parameter[^>]*ac:name="diagramName"[^>]*>([^<]*)</ac:parameter>)?.*?</ac:structured-macro>`).
-		ReplaceAllStringFunc(html, func(match string) string {
-			nameMatch := regexp.MustCompile(`<ac:parameter[^>]*ac:name="diagramName"[^>]*>([^<]*)</ac:parameter>`).FindStringSubmatch(match)
-			if len(nameMatch) > 1 && nameMatch[1] != "" {
-				return "<p>CFPLACEHOLDER:DIAGRAM:" + nameMatch[1] + ":</p>"
-			}
-			return "<p>CFPLACEHOLDER:DIAGRAM::</p>"
-		})
-
-	// Handle code macro - convert to proper code block
-	html = regexp.MustCompile(`<ac:structured-macro[^>]*ac:name="code"[^>]*>(.*?)</ac:structured-macro>`).
-		ReplaceAllStringFunc(html, func(match string) string {
-			// Extract language parameter
-			lang := ""
-			langMatch := regexp.MustCompile(`<ac:parameter[^>]*ac:name="language"[^>]*>([^<]*)</ac:parameter>`).FindStringSubmatch(match)
-			if len(langMatch) > 1 {
-				lang = langMatch[1]
-			}
-
-			// Extract code body
-			code := ""
-			codeMatch := regexp.MustCompile(`<ac:plain-text-body><!\[CDATA\[(.*?)\]\]></ac:plain-text-body>`).FindStringSubmatch(match)
-			if len(codeMatch) > 1 {
-				code = codeMatch[1]
-			}
-
-			return "<pre><code class=\"language-" + lang + "\">" + code + "</code></pre>"
-		})
-
-	// Handle info/warning/note/tip macros - convert to blockquotes
-	for _, macroType := range []string{"info", "warning", "note", "tip"} {
-		pattern := regexp.MustCompile(`<ac:structured-macro[^>]*ac:name="` + macroType + `"[^>]*>.*?<ac:rich-text-body>(.*?)</ac:rich-text-body>.*?</ac:structured-macro>`)
-		label := strings.ToUpper(macroType[:1]) + macroType[1:]
-		html = pattern.ReplaceAllStringFunc(html, func(match string) string {
-			bodyMatch := regexp.MustCompile(`<ac:rich-text-body>(.*?)</ac:rich-text-body>`).FindStringSubmatch(match)
-			if len(bodyMatch) > 1 {
-				// Extract text from the body, removing HTML tags
-				body := regexp.MustCompile(`<[^>]+>`).ReplaceAllString(bodyMatch[1], "")
-				body = strings.TrimSpace(body)
-				return "<blockquote><strong>" + label + ":</strong> " + body + "</blockquote>"
-			}
-			return ""
-		})
+// preprocessConfluenceMacros converts Confluence-specific elements to HTML
+// that can be processed by the markdown converter. The storage format is
+// parsed into a tree, and each macro is converted by its MacroHandlers entry
+// after its rich-text body, so nested macros keep their formatting.
+func preprocessConfluenceMacros(storage string, refs *References) string {
+	root, err := parseStorage(storage)
+	if err != nil {
+		// Leave unparseable content to the HTML converter as it is
+		return storage
 	}
 
-	// Handle expand macro - convert to collapsible section format
-	html = regexp.MustCompile(`<ac:structured-macro[^>]*ac:name="expand"[^>]*>(.*?)</ac:structured-macro>`).
-		ReplaceAllStringFunc(html, func(match string) string {
-			// Extract title parameter
-			title := "Expand"
-			titleMatch := regexp.MustCompile(`<ac:parameter[^>]*ac:name="title"[^>]*>([^<]*)</ac:parameter>`).FindStringSubmatch(match)
-			if len(titleMatch) > 1 {
-				title = titleMatch[1]
-			}
-
-			// Extract body content
-			body := ""
-			bodyMatch := regexp.MustCompile(`<ac:rich-text-body>(.*?)</ac:rich-text-body>`).FindStringSubmatch(match)
-			if len(bodyMatch) > 1 {
-				// Extract text from the body, removing HTML tags
-				body = regexp.MustCompile(`<[^>]+>`).ReplaceAllString(bodyMatch[1], "")
-				body = strings.TrimSpace(body)
-			}
-
-			// Use a raw marker that will be converted in postprocessing
-			return "CFPLACEHOLDER:EXPANDSTART:" + title + ":CFPLACEHOLDER:EXPANDBODY:" + body + ":CFPLACEHOLDER:EXPANDEND:"
-		})
-
-	// Handle ac:link elements
-	html = regexp.MustCompile(`<ac:link>(.*?)</ac:link>`).
-		ReplaceAllStringFunc(html, func(match string) string {
-			// Try to extract link text from plain-text-link-body
-			textMatch := regexp.MustCompile(`<ac:plain-text-link-body><!\[CDATA\[(.*?)\]\]></ac:plain-text-link-body>`).FindStringSubmatch(match)
-			if len(textMatch) > 1 {
-				return textMatch[1]
-			}
-
-			// Fall back to page title
-			titleMatch := regexp.MustCompile(`ri:content-title="([^"]*)"`).FindStringSubmatch(match)
-			if len(titleMatch) > 1 {
-				return titleMatch[1]
-			}
-
-			return "CFPLACEHOLDER:LINK::"
-		})
-
-	// Handle unknown macros - replace with placeholder
-	html = regexp.MustCompile(`<ac:structured-macro[^>]*ac:name="([^"]*)"[^>]*>.*?</ac:structured-macro>`).
-		ReplaceAllStringFunc(html, func(match string) string {
-			nameMatch := regexp.MustCompile(`ac:name="([^"]*)"`).FindStringSubmatch(match)
-			if len(nameMatch) > 1 {
-				return "<p>CFPLACEHOLDER:MACRO:" + nameMatch[1] + ":</p>"
-			}
-			return ""
-		})
-
-	return html
+	var b strings.Builder
+	r := &storageRenderer{refs: refs}
+	r.renderChildren(&b, root)
+	return b.String()
 }
 
 // postprocessMarkdown cleans up the converted markdown.
@@ -154,6 +75,16 @@ func postprocessMarkdown(markdown string) string {
 	markdown = strings.ReplaceAll(markdown, "CFPLACEHOLDER:TOC:", "[Table of Contents]")
 	markdown = strings.ReplaceAll(markdown, "CFPLACEHOLDER:DIAGRAM::", "[Diagram]")
 	markdown = strings.ReplaceAll(markdown, "CFPLACEHOLDER:LINK::", "[Link]")
+	markdown = strings.ReplaceAll(markdown, "CFPLACEHOLDER:TASKDONE:", "[x]")
+	markdown = strings.ReplaceAll(markdown, "CFPLACEHOLDER:TASKOPEN:", "[ ]")
+
+	// Literal text and anchors are hex-encoded so they are not escaped
+	markdown = literalTextRe.ReplaceAllStringFunc(markdown, func(match string) string {
+		return decodePlaceholder(literalTextRe, match)
+	})
+	markdown = anchorRe.ReplaceAllStringFunc(markdown, func(match string) string {
+		return `<a id="` + html.EscapeString(decodePlaceholder(anchorRe, match)) + `"></a>`
+	})
 
 	// Handle diagram placeholders with names
 	markdown = regexp.MustCompile(`CFPLACEHOLDER:DIAGRAM:([^:]+):`).
@@ -164,8 +95,11 @@ func postprocessMarkdown(markdown string) string {
 		ReplaceAllString(markdown, "[Confluence Macro: $1]")
 
 	// Handle expand/details placeholders
-	markdown = regexp.MustCompile(`CFPLACEHOLDER:EXPANDSTART:([^:]*):CFPLACEHOLDER:EXPANDBODY:([^:]*):CFPLACEHOLDER:EXPANDEND:`).
-		ReplaceAllString(markdown, "<details>\n<summary>$1</summary>\n\n$2\n</details>")
+	markdown = expandStartRe.ReplaceAllStringFunc(markdown, func(match string) string {
+		title := expandTitle(match)
+		return "<details>\n<summary>" + html.EscapeString(title) + "</summary>"
+	})
+	markdown = expandEndRe.ReplaceAllString(markdown, "\n</details>")
 
 	// Normalize multiple newlines to max 2
 	markdown = regexp.MustCompile(`\n{3,}`).ReplaceAllString(markdown, "\n\n")
@@ -175,3 +109,170 @@ func postprocessMarkdown(markdown string) string {
 
 	return markdown
 }
+
+// ToPlainText converts Confluence storage format (XHTML) to plain text.
+// Macros are rendered the same way as in ToMarkdown, but all other
+// formatting is discarded.
+func ToPlainText(storageFormat string) (string, error) {
+	if strings.TrimSpace(storageFormat) == "" {
+		return "", nil
+	}
+
+	processed := preprocessConfluenceMacros(storageFormat, nil)
+	nodes, err := html.ParseFragment(strings.NewReader(processed), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		writePlainText(&b, node, false)
+	}
+
+	// Expand sections read as a title followed by their body in plain text
+	text := expandStartRe.ReplaceAllStringFunc(b.String(), expandTitle)
+	text = expandEndRe.ReplaceAllString(text, "")
+	text = anchorRe.ReplaceAllString(text, "")
+	text = postprocessMarkdown(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	text = strings.Join(lines, "\n")
+	text = regexp.MustCompile(`\n{3,}`).ReplaceAllString(text, "\n\n")
+
+	return strings.TrimSpace(text), nil
+}
+
+var (
+	expandStartRe = regexp.MustCompile(`CFPLACEHOLDER:EXPANDSTART:([0-9a-f]*):`)
+	expandEndRe   = regexp.MustCompile(`\n*CFPLACEHOLDER:EXPANDEND:`)
+	literalTextRe = regexp.MustCompile(`CFPLACEHOLDER:TEXT:([0-9a-f]*):`)
+	anchorRe      = regexp.MustCompile(`CFPLACEHOLDER:ANCHOR:([0-9a-f]*):`)
+)
+
+// expandTitle decodes the title of an expand placeholder.
+func expandTitle(placeholder string) string {
+	return decodePlaceholder(expandStartRe, placeholder)
+}
+
+// decodePlaceholder decodes the hex-encoded text of a placeholder matched
+// by re.
+func decodePlaceholder(re *regexp.Regexp, placeholder string) string {
+	m := re.FindStringSubmatch(placeholder)
+	text, err := hex.DecodeString(m[1])
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+// plainTextBlocks are elements that start on a new line in plain text
+// output. Elements mapped to true are also followed by a blank line.
+var plainTextBlocks = map[string]bool{
+	"p": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "table": true, "pre": true, "blockquote": true,
+	"li": false, "tr": false,
+}
+
+// writePlainText writes the text content of node, separating block elements
+// with newlines. Whitespace is preserved inside pre elements.
+func writePlainText(b *strings.Builder, node *html.Node, pre bool) {
+	paragraph, isBlock := false, false
+
+	switch node.Type {
+	case html.TextNode:
+		text := node.Data
+		if !pre {
+			text = collapseWhitespace(text)
+			// Drop indentation left over from the source markup
+			if b.Len() == 0 || strings.HasSuffix(b.String(), "\n") {
+				text = strings.TrimLeft(text, " ")
+			}
+		}
+		b.WriteString(text)
+		return
+	case html.ElementNode:
+		paragraph, isBlock = plainTextBlocks[node.Data]
+		switch {
+		case node.Data == "br":
+			b.WriteString("\n")
+			return
+		case node.Data == "td" || node.Data == "th":
+			if node.PrevSibling != nil {
+				b.WriteString("\t")
+			}
+		case isBlock:
+			ensureNewline(b)
+			if node.Data == "li" {
+				b.WriteString("- ")
+			}
+		}
+		pre = pre || node.Data == "pre"
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writePlainText(b, child, pre)
+	}
+
+	if isBlock {
+		ensureNewline(b)
+		if paragraph {
+			b.WriteString("\n")
+		}
+	}
+}
+
+// ensureNewline ends the current line unless the output is already at the
+// start of a line.
+func ensureNewline(b *strings.Builder) {
+	if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+}
+
+var whitespaceRe = regexp.MustCompile(`\s+`)
+
+func collapseWhitespace(text string) string {
+	return whitespaceRe.ReplaceAllString(text, " ")
+}
+
+// Macros whose handlers only leave a placeholder behind.
+var placeholderMacros = map[string]bool{
+	"toc": true, "drawio": true, "children": true,
+}
+
+var (
+	macroNameRe = regexp.MustCompile(`<ac:structured-macro[^>]*ac:name="([^"]*)"`)
+	userRefRe   = regexp.MustCompile(`<ri:user[\s/>]`)
+)
+
+// StorageWarnings reports Confluence storage content that ToMarkdown and
+// ToPlainText cannot represent faithfully.
+func StorageWarnings(storageFormat string) []string {
+	var warnings []string
+	seen := make(map[string]bool)
+	add := func(msg string) {
+		if !seen[msg] {
+			seen[msg] = true
+			warnings = append(warnings, msg)
+		}
+	}
+
+	for _, m := range macroNameRe.FindAllStringSubmatch(storageFormat, -1) {
+		name := m[1]
+		if _, ok := MacroHandlers[name]; !ok || placeholderMacros[name] {
+			add(fmt.Sprintf("%s macro was replaced with a placeholder", name))
+		}
+	}
+	if userRefRe.MatchString(storageFormat) {
+		add("user mentions were kept as account IDs")
+	}
+
+	return warnings
+}
@@ -329,3 +329,280 @@ func TestToMarkdown_ComplexDocument(t *testing.T) {
 		t.Error("expected table header")
 	}
 }
+
+func TestToPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "paragraphs and formatting",
+			input:    "<h1>Title</h1><p>Some <strong>bold</strong>\n  text</p>",
+			expected: "Title\n\nSome bold text",
+		},
+		{
+			name:     "list",
+			input:    "<ul><li>one</li><li>two</li></ul>",
+			expected: "- one\n- two",
+		},
+		{
+			name:     "table",
+			input:    "<table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>",
+			expected: "A\tB\n1\t2",
+		},
+		{
+			name:     "code macro keeps spacing",
+			input:    `<ac:structured-macro ac:name="code"><ac:plain-text-body><![CDATA[x  =  1]]></ac:plain-text-body></ac:structured-macro>`,
+			expected: "x  =  1",
+		},
+		{
+			name:     "expand macro",
+			input:    `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">More</ac:parameter><ac:rich-text-body><p>Hidden</p></ac:rich-text-body></ac:structured-macro>`,
+			expected: "More\n\nHidden",
+		},
+		{
+			name:     "unknown macro",
+			input:    `<ac:structured-macro ac:name="custom"></ac:structured-macro>`,
+			expected: "[Confluence Macro: custom]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ToPlainText(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestStorageWarnings(t *testing.T) {
+	input := `<ac:structured-macro ac:name="code"></ac:structured-macro>` +
+		`<ac:structured-macro ac:name="info"></ac:structured-macro>` +
+		`<ac:structured-macro ac:name="roadmap"></ac:structured-macro>` +
+		`<ac:structured-macro ac:name="roadmap"></ac:structured-macro>` +
+		`<ac:image><ri:attachment ri:filename="a.png"/></ac:image>` +
+		`<ac:link><ri:user ri:account-id="557058:abc"/></ac:link>`
+
+	warnings := StorageWarnings(input)
+	expected := []string{
+		"roadmap macro was replaced with a placeholder",
+		"user mentions were kept as account IDs",
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, warnings)
+	}
+	for i := range expected {
+		if warnings[i] != expected[i] {
+			t.Errorf("warning %d: expected %q, got %q", i, expected[i], warnings[i])
+		}
+	}
+
+	if warnings := StorageWarnings("<p>Plain</p>"); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestToMarkdown_NestedMacros(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "info inside expand keeps formatting",
+			input: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">Details</ac:parameter>` +
+				`<ac:rich-text-body><ac:structured-macro ac:name="info"><ac:rich-text-body>` +
+				`<p>Use <strong>bold</strong> and <code>code</code></p>` +
+				`</ac:rich-text-body></ac:structured-macro></ac:rich-text-body></ac:structured-macro>`,
+			expected: "<details>\n<summary>Details</summary>\n\n> **Info:** Use **bold** and `code`\n</details>",
+		},
+		{
+			name: "multi-line body with list",
+			input: "<ac:structured-macro ac:name=\"note\">\n  <ac:rich-text-body>\n    <p>First</p>\n" +
+				"    <ul><li>one</li><li>two</li></ul>\n  </ac:rich-text-body>\n</ac:structured-macro>",
+			expected: "> **Note:** First\n> \n> - one\n> - two",
+		},
+		{
+			name: "attributes in any order",
+			input: `<ac:structured-macro ac:macro-id="abc" ac:schema-version="1" ac:name="code">` +
+				`<ac:parameter ac:name="language">go</ac:parameter>` +
+				`<ac:plain-text-body><![CDATA[x := 1]]></ac:plain-text-body></ac:structured-macro>`,
+			expected: "```go\nx := 1\n```",
+		},
+		{
+			name: "CDATA containing brackets and markup",
+			input: `<ac:structured-macro ac:name="code"><ac:plain-text-body>` +
+				`<![CDATA[a[b[0]]]]><![CDATA[> <div>]]></ac:plain-text-body></ac:structured-macro>`,
+			expected: "```\na[b[0]]> <div>\n```",
+		},
+		{
+			name: "unknown macro keeps its body",
+			input: `<ac:structured-macro ac:name="section"><ac:rich-text-body>` +
+				`<p>Inside <em>section</em></p></ac:rich-text-body></ac:structured-macro>`,
+			expected: "[Confluence Macro: section]\n\nInside *section*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ToMarkdown(tt.input)
+			if err != nil {
+				t.Fatalf("ToMarkdown() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("ToMarkdown() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMacroHandlers_Custom(t *testing.T) {
+	MacroHandlers["team-status"] = func(m *Macro) string {
+		return "<p><strong>Status: " + m.Params["state"] + "</strong></p>" + m.RichBody
+	}
+	defer delete(MacroHandlers, "team-status")
+
+	input := `<ac:structured-macro ac:name="team-status"><ac:parameter ac:name="state">green</ac:parameter>` +
+		`<ac:rich-text-body><p>All good</p></ac:rich-text-body></ac:structured-macro>`
+
+	result, err := ToMarkdown(input)
+	if err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	if expected := "**Status: green**\n\nAll good"; result != expected {
+		t.Errorf("ToMarkdown() = %q, expected %q", result, expected)
+	}
+	if warnings := StorageWarnings(input); len(warnings) != 0 {
+		t.Errorf("expected no warnings for a registered macro, got %v", warnings)
+	}
+}
+
+func TestToMarkdownWithReferences(t *testing.T) {
+	refs := &References{
+		BaseURL:      "https://example.atlassian.net",
+		PageID:       "123",
+		Users:        map[string]string{"557058:abc": "Ada Lovelace"},
+		JiraStatuses: map[string]string{"ENG-7": "In Progress"},
+		Children:     []*PageNode{{ID: "201", Title: "Child A"}, {ID: "202", Title: "Child B"}},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		refs     *References
+		expected string
+	}{
+		{
+			name:     "attachment image",
+			input:    `<p><ac:image ac:alt="Architecture"><ri:attachment ri:filename="arch diagram.png"/></ac:image></p>`,
+			refs:     refs,
+			expected: "![Architecture](https://example.atlassian.net/wiki/download/attachments/123/arch%20diagram.png)",
+		},
+		{
+			name:     "attachment image without references",
+			input:    `<p><ac:image><ri:attachment ri:filename="a.png"/></ac:image></p>`,
+			expected: "![a.png](a.png)",
+		},
+		{
+			name:     "external image",
+			input:    `<p><ac:image><ri:url ri:value="https://example.com/logo.png"/></ac:image></p>`,
+			expected: "![](https://example.com/logo.png)",
+		},
+		{
+			name:     "attachment link",
+			input:    `<p>See <ac:link><ri:attachment ri:filename="spec.pdf"/></ac:link></p>`,
+			refs:     refs,
+			expected: "See [spec.pdf](https://example.atlassian.net/wiki/download/attachments/123/spec.pdf)",
+		},
+		{
+			name:     "user mention",
+			input:    `<p>Ask <ac:link><ri:user ri:account-id="557058:abc"/></ac:link></p>`,
+			refs:     refs,
+			expected: "Ask @Ada Lovelace",
+		},
+		{
+			name:     "unresolved user mention",
+			input:    `<p>Ask <ac:link><ri:user ri:account-id="557058:xyz"/></ac:link></p>`,
+			refs:     refs,
+			expected: "Ask @557058:xyz",
+		},
+		{
+			name: "task list",
+			input: `<ac:task-list>` +
+				`<ac:task><ac:task-id>1</ac:task-id><ac:task-status>complete</ac:task-status><ac:task-body>Write docs</ac:task-body></ac:task>` +
+				`<ac:task><ac:task-id>2</ac:task-id><ac:task-status>incomplete</ac:task-status><ac:task-body>Ship <strong>it</strong></ac:task-body></ac:task>` +
+				`</ac:task-list>`,
+			expected: "- [x] Write docs\n- [ ] Ship **it**",
+		},
+		{
+			name:     "status macro",
+			input:    `<p><ac:structured-macro ac:name="status"><ac:parameter ac:name="colour">Green</ac:parameter><ac:parameter ac:name="title">Done</ac:parameter></ac:structured-macro></p>`,
+			expected: "[STATUS: green Done]",
+		},
+		{
+			name:     "jira macro with status",
+			input:    `<p><ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">ENG-7</ac:parameter></ac:structured-macro></p>`,
+			refs:     refs,
+			expected: "[ENG-7](https://example.atlassian.net/browse/ENG-7) (In Progress)",
+		},
+		{
+			name:     "jira macro without references",
+			input:    `<p><ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">ENG-7</ac:parameter></ac:structured-macro></p>`,
+			expected: "ENG-7",
+		},
+		{
+			name:     "anchor macro",
+			input:    `<p><ac:structured-macro ac:name="anchor"><ac:parameter ac:name="">setup</ac:parameter></ac:structured-macro>Setup</p>`,
+			expected: `<a id="setup"></a>Setup`,
+		},
+		{
+			name:     "panel macro",
+			input:    `<ac:structured-macro ac:name="panel"><ac:parameter ac:name="title">Owners</ac:parameter><ac:rich-text-body><p>Team <em>Core</em></p></ac:rich-text-body></ac:structured-macro>`,
+			expected: "> **Owners**\n> \n> Team *Core*",
+		},
+		{
+			name:     "excerpt macro",
+			input:    `<ac:structured-macro ac:name="excerpt"><ac:rich-text-body><p>Summary</p></ac:rich-text-body></ac:structured-macro>`,
+			expected: "Summary",
+		},
+		{
+			name:     "hidden excerpt",
+			input:    `<ac:structured-macro ac:name="excerpt"><ac:parameter ac:name="hidden">true</ac:parameter><ac:rich-text-body><p>Summary</p></ac:rich-text-body></ac:structured-macro><p>Body</p>`,
+			expected: "Body",
+		},
+		{
+			name:     "children macro",
+			input:    `<ac:structured-macro ac:name="children"/>`,
+			refs:     refs,
+			expected: "- [Child A](https://example.atlassian.net/wiki/pages/viewpage.action?pageId=201)\n- [Child B](https://example.atlassian.net/wiki/pages/viewpage.action?pageId=202)",
+		},
+		{
+			name:     "children macro without references",
+			input:    `<ac:structured-macro ac:name="children"/>`,
+			expected: "[Child pages]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ToMarkdownWithReferences(tt.input, tt.refs)
+			if err != nil {
+				t.Fatalf("ToMarkdownWithReferences() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("got %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// maxIncludeDepth bounds how many levels of included pages are inlined: a
+// page included by an included page is at depth 2.
+const maxIncludeDepth = 5
+
+// IncludedPage is a page inlined by an include or excerpt-include macro.
+type IncludedPage struct {
+	ID       string
+	Title    string
+	SpaceKey string
+	Body     string // storage format
+}
+
+// The include handlers render storage themselves, which refers back to
+// MacroHandlers, so they are registered at init time.
+func init() {
+	MacroHandlers["include"] = includeMacro
+	MacroHandlers["excerpt-include"] = includeMacro
+}
+
+// includeKey identifies an included page by space and title, which is how
+// include macros refer to pages.
+func includeKey(spaceKey, title string) string {
+	return spaceKey + "\x00" + title
+}
+
+// includeTarget returns the space key and title of the page an include
+// macro refers to. Pages without a space key are in defaultSpace.
+func includeTarget(macro *storageNode, defaultSpace string) (string, string) {
+	page := findElement(macro, "ri:page")
+	if page == nil {
+		return "", ""
+	}
+	space := page.attr("ri:space-key")
+	if space == "" {
+		space = defaultSpace
+	}
+	return space, page.attr("ri:content-title")
+}
+
+// findElement returns the first descendant of n with the given name.
+func findElement(n *storageNode, name string) *storageNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+		if found := findElement(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// isIncludeMacro reports whether n is an include or excerpt-include macro.
+func isIncludeMacro(n *storageNode) bool {
+	if n.name != "ac:structured-macro" && n.name != "ac:macro" {
+		return false
+	}
+	name := n.attr("ac:name")
+	return name == "include" || name == "excerpt-include"
+}
+
+// collectIncludes returns the include macros in a parsed document.
+func collectIncludes(n *storageNode) []*storageNode {
+	var macros []*storageNode
+	for _, c := range n.children {
+		if isIncludeMacro(c) {
+			macros = append(macros, c)
+			continue
+		}
+		macros = append(macros, collectIncludes(c)...)
+	}
+	return macros
+}
+
+// findExcerpt returns the excerpt macro with the given name, or the first
+// excerpt macro if name is empty.
+func findExcerpt(n *storageNode, name string) *storageNode {
+	for _, c := range n.children {
+		if (c.name == "ac:structured-macro" || c.name == "ac:macro") && c.attr("ac:name") == "excerpt" {
+			if name == "" || excerptName(c) == name {
+				return c
+			}
+		}
+		if found := findExcerpt(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// excerptName returns the name parameter of an excerpt macro.
+func excerptName(excerpt *storageNode) string {
+	for _, p := range excerpt.children {
+		if p.name == "ac:parameter" && p.attr("ac:name") == "name" {
+			return strings.TrimSpace(p.textContent())
+		}
+	}
+	return ""
+}
+
+// includeMacro inlines the page an include macro refers to, or just its
+// excerpt for excerpt-include. Pages that were not resolved keep the
+// placeholder; cycles and includes nested too deeply are marked as such.
+func includeMacro(m *Macro) string {
+	if m.Refs == nil || m.Refs.Includes == nil {
+		return placeholderMacro(m)
+	}
+	space, title := includeTarget(m.node, m.Refs.SpaceKey)
+	key := includeKey(space, title)
+	if slices.Contains(m.Refs.includeStack, key) {
+		return "<p>" + literalText("[Include cycle: "+title+"]") + "</p>"
+	}
+	if len(m.Refs.includeStack) > maxIncludeDepth {
+		return "<p>" + literalText("[Include depth limit reached: "+title+"]") + "</p>"
+	}
+	included := m.Refs.Includes[key]
+	if included == nil {
+		return placeholderMacro(m)
+	}
+	root, err := parseStorage(included.Body)
+	if err != nil {
+		return placeholderMacro(m)
+	}
+
+	// The included content resolves attachments against its own page, and
+	// its children macro would otherwise list the including page's children
+	refs := *m.Refs
+	refs.PageID = included.ID
+	refs.SpaceKey = included.SpaceKey
+	refs.Children = nil
+	refs.includeStack = append(slices.Clone(m.Refs.includeStack), key)
+	r := &storageRenderer{refs: &refs}
+
+	var b strings.Builder
+	if m.Name == "excerpt-include" {
+		excerpt := findExcerpt(root, m.Params["name"])
+		if excerpt == nil {
+			return ""
+		}
+		if body := excerpt.child("ac:rich-text-body"); body != nil {
+			r.renderChildren(&b, body)
+		}
+		return b.String()
+	}
+	r.renderChildren(&b, root)
+	return b.String()
+}
+
+// ResolveIncludes fetches the pages that the page's include and
+// excerpt-include macros refer to, and the pages those include in turn, up
+// to a bounded depth, so that WriteMarkdown and WriteBodyOnly inline them.
+// Each page is fetched once, however often it is included. Resolution is
+// best-effort: pages that cannot be fetched keep the macro placeholder.
+func (c *Client) ResolveIncludes(ctx context.Context, page *Page) {
+	if page.refs == nil {
+		page.refs = &References{BaseURL: c.cfg.BaseURL(), PageID: page.ID}
+	}
+	refs := page.refs
+	refs.SpaceKey = page.SpaceKey
+	refs.Includes = make(map[string]*IncludedPage)
+	pageKey := includeKey(page.SpaceKey, page.Title)
+	refs.includeStack = []string{pageKey}
+
+	type pending struct {
+		body  string
+		space string
+		depth int
+	}
+	queue := []pending{{body: page.Body, space: page.SpaceKey}}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if p.depth >= maxIncludeDepth {
+			continue
+		}
+		root, err := parseStorage(p.body)
+		if err != nil {
+			continue
+		}
+
+		for _, macro := range collectIncludes(root) {
+			space, title := includeTarget(macro, p.space)
+			key := includeKey(space, title)
+			if _, seen := refs.Includes[key]; seen || key == pageKey || space == "" || title == "" {
+				continue
+			}
+			included, err := c.findPageByTitle(ctx, space, title)
+			// A failed lookup is remembered so the page is not fetched again
+			refs.Includes[key] = included
+			if err == nil {
+				queue = append(queue, pending{body: included.Body, space: space, depth: p.depth + 1})
+			}
+		}
+	}
+}
+
+// findPageByTitle fetches the current version of the page with the given
+// title in a space.
+func (c *Client) findPageByTitle(ctx context.Context, spaceKey, title string) (*IncludedPage, error) {
+	spaceID, err := c.lookupSpaceID(ctx, spaceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("space-id", spaceID)
+	query.Set("title", title)
+	query.Set("body-format", "storage")
+	body, err := c.doJSON(ctx, "GET", c.cfg.BaseURL()+"/wiki/api/v2/pages?"+query.Encode(), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return nil, fmt.Errorf("page %q not found in space %s", title, spaceKey)
+	}
+	page, err := ParseAPIResponse(resp.Results[0])
+	if err != nil {
+		return nil, err
+	}
+	return &IncludedPage{ID: page.ID, Title: page.Title, SpaceKey: spaceKey, Body: page.Body}, nil
+}
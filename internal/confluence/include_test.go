@@ -0,0 +1,200 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// includeStorage returns an include or excerpt-include macro for a page.
+func includeStorage(macro, title, space string) string {
+	spaceAttr := ""
+	if space != "" {
+		spaceAttr = ` ri:space-key="` + space + `"`
+	}
+	return `<ac:structured-macro ac:name="` + macro + `"><ac:parameter ac:name=""><ac:link>` +
+		`<ri:page ri:content-title="` + title + `"` + spaceAttr + `/></ac:link></ac:parameter></ac:structured-macro>`
+}
+
+func TestToMarkdownWithReferences_Includes(t *testing.T) {
+	refs := &References{
+		BaseURL:      "https://acme.atlassian.net",
+		PageID:       "1",
+		SpaceKey:     "ENG",
+		includeStack: []string{includeKey("ENG", "Runbook")},
+		Includes: map[string]*IncludedPage{
+			includeKey("ENG", "Contacts"): {ID: "2", SpaceKey: "ENG", Body: `<p>Call the on-call engineer.</p>`},
+			includeKey("OPS", "Escalation"): {ID: "3", SpaceKey: "OPS", Body: `<p>Intro</p>` +
+				`<ac:structured-macro ac:name="excerpt"><ac:parameter ac:name="hidden">true</ac:parameter>` +
+				`<ac:rich-text-body><p>Escalate after 30 minutes.</p></ac:rich-text-body></ac:structured-macro>` +
+				`<ac:structured-macro ac:name="excerpt"><ac:parameter ac:name="name">sla</ac:parameter>` +
+				`<ac:rich-text-body><p>Respond within 4 hours.</p></ac:rich-text-body></ac:structured-macro>`},
+			includeKey("ENG", "Loop"):    {ID: "4", SpaceKey: "ENG", Body: `<p>Loop start</p>` + includeStorage("include", "Runbook", "")},
+			includeKey("ENG", "Images"):  {ID: "5", SpaceKey: "ENG", Body: `<ac:image><ri:attachment ri:filename="chart.png"/></ac:image>`},
+			includeKey("ENG", "Missing"): nil,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		storage string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "include in same space",
+			storage: includeStorage("include", "Contacts", ""),
+			want:    []string{"Call the on-call engineer."},
+			notWant: []string{"CFPLACEHOLDER", "Confluence Macro"},
+		},
+		{
+			name:    "excerpt-include of a hidden excerpt",
+			storage: includeStorage("excerpt-include", "Escalation", "OPS"),
+			want:    []string{"Escalate after 30 minutes."},
+			notWant: []string{"Intro", "Respond within"},
+		},
+		{
+			name: "named excerpt",
+			storage: `<ac:structured-macro ac:name="excerpt-include"><ac:parameter ac:name="name">sla</ac:parameter>` +
+				`<ac:parameter ac:name=""><ac:link><ri:page ri:content-title="Escalation" ri:space-key="OPS"/></ac:link></ac:parameter></ac:structured-macro>`,
+			want:    []string{"Respond within 4 hours."},
+			notWant: []string{"Escalate after"},
+		},
+		{
+			name:    "cycle back to the page",
+			storage: includeStorage("include", "Loop", ""),
+			want:    []string{"Loop start", "[Include cycle: Runbook]"},
+		},
+		{
+			name:    "attachments resolve against the included page",
+			storage: includeStorage("include", "Images", ""),
+			want:    []string{"https://acme.atlassian.net/wiki/download/attachments/5/chart.png"},
+		},
+		{
+			name:    "unresolved page keeps the placeholder",
+			storage: includeStorage("include", "Missing", ""),
+			want:    []string{"[Confluence Macro: include]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMarkdownWithReferences(tt.storage, refs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in output:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("unexpected %q in output:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestToMarkdownWithReferences_IncludeDepthLimit(t *testing.T) {
+	// A chain of pages, each including the next, deeper than the limit
+	refs := &References{SpaceKey: "ENG", Includes: make(map[string]*IncludedPage)}
+	for i := 1; i <= maxIncludeDepth+2; i++ {
+		refs.Includes[includeKey("ENG", fmt.Sprint("Level ", i))] = &IncludedPage{
+			ID:       fmt.Sprint(i),
+			SpaceKey: "ENG",
+			Body:     fmt.Sprintf("<p>Content %d</p>", i) + includeStorage("include", fmt.Sprint("Level ", i+1), ""),
+		}
+	}
+	refs.includeStack = []string{includeKey("ENG", "Top")}
+
+	got, err := ToMarkdownWithReferences(includeStorage("include", "Level 1", ""), refs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, fmt.Sprintf("Content %d", maxIncludeDepth)) {
+		t.Errorf("expected content down to depth %d:\n%s", maxIncludeDepth, got)
+	}
+	if strings.Contains(got, fmt.Sprintf("Content %d", maxIncludeDepth+1)) {
+		t.Errorf("content beyond depth %d was inlined:\n%s", maxIncludeDepth, got)
+	}
+	if !strings.Contains(got, fmt.Sprintf("[Include depth limit reached: Level %d]", maxIncludeDepth+1)) {
+		t.Errorf("expected depth limit marker:\n%s", got)
+	}
+}
+
+func TestToMarkdown_IncludeWithoutReferences(t *testing.T) {
+	got, err := ToMarkdown(includeStorage("include", "Contacts", "ENG"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, "[Confluence Macro: include]") {
+		t.Errorf("expected placeholder, got:\n%s", got)
+	}
+}
+
+func TestClient_ResolveIncludes(t *testing.T) {
+	pages := map[string]string{
+		"Contacts":   `<p>Call the on-call engineer.</p>` + includeStorage("include", "Phone list", ""),
+		"Phone list": `<p>555-0100</p>` + includeStorage("include", "Runbook", ""),
+	}
+	fetched := make(map[string]int)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wiki/api/v2/spaces":
+			w.Write([]byte(`{"results": [{"id": "65011", "key": "ENG"}]}`))
+		case "/wiki/api/v2/pages":
+			title := r.URL.Query().Get("title")
+			fetched[title]++
+			if r.URL.Query().Get("space-id") != "65011" || r.URL.Query().Get("body-format") != "storage" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			results := []map[string]interface{}{}
+			if body, ok := pages[title]; ok {
+				results = append(results, map[string]interface{}{
+					"id":    "9" + fmt.Sprint(len(title)),
+					"title": title,
+					"body":  map[string]interface{}{"storage": map[string]string{"value": body}},
+				})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	page := &Page{
+		ID:       "1",
+		Title:    "Runbook",
+		SpaceKey: "ENG",
+		Body: `<h1>Runbook</h1>` + includeStorage("include", "Contacts", "") +
+			includeStorage("include", "Contacts", "ENG") + includeStorage("include", "Retired", ""),
+	}
+	client.ResolveIncludes(context.Background(), page)
+
+	if fetched["Contacts"] != 1 || fetched["Phone list"] != 1 || fetched["Retired"] != 1 {
+		t.Errorf("each page should be fetched once: %v", fetched)
+	}
+	if fetched["Runbook"] != 0 {
+		t.Errorf("the page itself should not be fetched: %v", fetched)
+	}
+
+	var buf strings.Builder
+	if err := page.WriteBodyOnly(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{"Call the on-call engineer.", "555-0100", "[Include cycle: Runbook]", "[Confluence Macro: include]"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// PageLabels is the set of labels on a page.
+type PageLabels struct {
+	ID     string   `json:"id"`
+	Labels []string `json:"labels"`
+}
+
+// Write writes the labels as JSON to the given writer.
+func (l *PageLabels) Write(w interface{ Write([]byte) (int, error) }) error {
+	if l.Labels == nil {
+		l.Labels = []string{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// apiLabelListResponse represents a page of v2 labels.
+type apiLabelListResponse struct {
+	Results []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Prefix string `json:"prefix"`
+	} `json:"results"`
+}
+
+// GetLabels returns the names of the labels on a page.
+func (c *Client) GetLabels(ctx context.Context, pageID string) ([]string, error) {
+	if err := ValidatePageID(pageID); err != nil {
+		return nil, err
+	}
+
+	labels := []string{}
+	path := fmt.Sprintf("/wiki/api/v2/pages/%s/labels?limit=250", pageID)
+	err := c.getPaged(ctx, path, func(body []byte) error {
+		var resp apiLabelListResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, r := range resp.Results {
+			labels = append(labels, r.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// apiLabel is a label in a v1 add-labels request.
+type apiLabel struct {
+	Prefix string `json:"prefix"`
+	Name   string `json:"name"`
+}
+
+// UpdateLabels adds and removes labels on a page and returns the labels it
+// has afterwards. Labels are lowercased as Confluence stores them; removing
+// a label the page does not have is not an error.
+func (c *Client) UpdateLabels(ctx context.Context, pageID string, add, remove []string) (*PageLabels, error) {
+	if err := ValidatePageID(pageID); err != nil {
+		return nil, err
+	}
+	for _, label := range append(append([]string{}, add...), remove...) {
+		if err := ValidateLabel(label); err != nil {
+			return nil, err
+		}
+	}
+
+	// The v2 API has no label writes, so these use the v1 content endpoints
+	base := fmt.Sprintf("%s/wiki/rest/api/content/%s/label", c.cfg.BaseURL(), url.PathEscape(pageID))
+	if len(add) > 0 {
+		payload := make([]apiLabel, len(add))
+		for i, label := range add {
+			payload[i] = apiLabel{Prefix: "global", Name: strings.ToLower(label)}
+		}
+		if _, err := c.doJSON(ctx, "POST", base, payload, http.StatusOK); err != nil {
+			return nil, err
+		}
+	}
+	for _, label := range remove {
+		_, err := c.doJSON(ctx, "DELETE", base+"?name="+url.QueryEscape(strings.ToLower(label)), nil, http.StatusOK, http.StatusNoContent)
+		var apiErr *httpclient.APIError
+		if err != nil && !(errors.As(err, &apiErr) && apiErr.Response.Error == httpclient.ErrTypeNotFound) {
+			return nil, err
+		}
+	}
+
+	labels, err := c.GetLabels(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	return &PageLabels{ID: pageID, Labels: labels}, nil
+}
@@ -0,0 +1,65 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_UpdateLabels(t *testing.T) {
+	var added []apiLabel
+	var removed []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/wiki/rest/api/content/123/label":
+			json.NewDecoder(r.Body).Decode(&added)
+			w.Write([]byte(`{"results": []}`))
+		case r.Method == "DELETE" && r.URL.Path == "/wiki/rest/api/content/123/label":
+			name := r.URL.Query().Get("name")
+			removed = append(removed, name)
+			if name == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/pages/123/labels":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"name": "runbook"}, {"name": "owner-team-x"}},
+			})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	labels, err := client.UpdateLabels(context.Background(), "123", []string{"Runbook", "owner-team-x"}, []string{"draft", "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 2 || added[0] != (apiLabel{Prefix: "global", Name: "runbook"}) {
+		t.Errorf("unexpected labels added: %+v", added)
+	}
+	if len(removed) != 2 || removed[0] != "draft" {
+		t.Errorf("unexpected labels removed: %v", removed)
+	}
+	if labels.ID != "123" || len(labels.Labels) != 2 || labels.Labels[0] != "runbook" {
+		t.Errorf("unexpected result: %+v", labels)
+	}
+}
+
+func TestClient_UpdateLabels_InvalidLabel(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	if _, err := client.UpdateLabels(context.Background(), "123", []string{"two words"}, nil); err == nil {
+		t.Error("expected error for a label with a space")
+	}
+}
@@ -0,0 +1,359 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PageChange is the CLI output for a page that was moved, archived, deleted
+// or restored.
+type PageChange struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Status is the page status after the change: current, archived or
+	// trashed.
+	Status   string `json:"status"`
+	ParentID string `json:"parentId,omitempty"`
+}
+
+// Write writes the change as JSON to the given writer.
+func (p *PageChange) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// Move positions accepted by MovePage.
+const (
+	MoveBefore = "before"
+	MoveAfter  = "after"
+	MoveAppend = "append"
+)
+
+// MovePage moves a page relative to a target page: with MoveAppend it
+// becomes the last child of the target, and with MoveBefore or MoveAfter a
+// sibling placed just before or after it. Descendants move with the page.
+func (c *Client) MovePage(ctx context.Context, id, targetID, position string) (*PageChange, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+	if err := ValidatePageID(targetID); err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+	if id == targetID {
+		return nil, fmt.Errorf("cannot move page %s relative to itself", id)
+	}
+	switch position {
+	case MoveBefore, MoveAfter, MoveAppend:
+	default:
+		return nil, fmt.Errorf("invalid position %q: must be before, after or append", position)
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/rest/api/content/%s/move/%s/%s", c.cfg.BaseURL(), url.PathEscape(id), position, url.PathEscape(targetID))
+	if _, err := c.doJSON(ctx, "PUT", reqURL, nil, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	summary, err := c.pageSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &PageChange{ID: summary.ID, Title: summary.Title, Status: summary.Status, ParentID: summary.ParentID}, nil
+}
+
+// ArchivePage archives a single page. Confluence archives in the background,
+// so the reported status is the one requested.
+func (c *Client) ArchivePage(ctx context.Context, id string) (*PageChange, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+	summary, err := c.pageSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ArchivePages(ctx, []string{id}); err != nil {
+		return nil, err
+	}
+	return &PageChange{ID: summary.ID, Title: summary.Title, Status: "archived", ParentID: summary.ParentID}, nil
+}
+
+// DeletePage moves a page to the space trash, from where RestorePage can
+// bring it back. Its children move up to the page's parent.
+func (c *Client) DeletePage(ctx context.Context, id string) (*PageChange, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+	summary, err := c.pageSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/pages/%s", c.cfg.BaseURL(), url.PathEscape(id))
+	if _, err := c.doJSON(ctx, "DELETE", reqURL, nil, http.StatusNoContent, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return &PageChange{ID: summary.ID, Title: summary.Title, Status: "trashed", ParentID: summary.ParentID}, nil
+}
+
+// restoreRequest is the request body that restores a trashed page through
+// PUT /wiki/rest/api/content/{id}: only the status may change, as a new
+// version.
+type restoreRequest struct {
+	ID      string      `json:"id"`
+	Type    string      `json:"type"`
+	Status  string      `json:"status"`
+	Title   string      `json:"title"`
+	Version pageVersion `json:"version"`
+}
+
+// RestorePage restores a page from the space trash.
+func (c *Client) RestorePage(ctx context.Context, id string) (*PageChange, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/pages/%s?status=trashed", c.cfg.BaseURL(), url.PathEscape(id))
+	body, err := c.doJSON(ctx, "GET", reqURL, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var trashed apiPageSummary
+	if err := json.Unmarshal(body, &trashed); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if trashed.Status != "trashed" {
+		return nil, fmt.Errorf("page %s is not in the trash (status %q)", id, trashed.Status)
+	}
+
+	payload := &restoreRequest{
+		ID:      id,
+		Type:    "page",
+		Status:  "current",
+		Title:   trashed.Title,
+		Version: pageVersion{Number: trashed.Version.Number + 1},
+	}
+	reqURL = fmt.Sprintf("%s/wiki/rest/api/content/%s?status=trashed", c.cfg.BaseURL(), url.PathEscape(id))
+	if _, err := c.doJSON(ctx, "PUT", reqURL, payload, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return &PageChange{ID: id, Title: trashed.Title, Status: "current", ParentID: trashed.ParentID}, nil
+}
+
+// CopyOptions configures CopyPage.
+type CopyOptions struct {
+	SourceID string
+	ParentID string // the page the copy is created under
+	Title    string // optional; the copied root keeps its title if empty
+	// IncludeChildren copies the descendants of the source too, keeping
+	// their titles and order.
+	IncludeChildren bool
+	// TitlePrefix, if set, is prepended to the title of every copied page
+	// except a root renamed with Title. Titles are unique within a space, so
+	// a subtree can only be copied within its own space with a prefix.
+	TitlePrefix string
+	// Progress, if set, is called after each page is copied with the number
+	// of pages copied so far and the total.
+	Progress func(done, total int, page *CopiedPage)
+}
+
+// CopiedPage maps a source page to its copy.
+type CopiedPage struct {
+	SourceID string `json:"sourceId"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	ParentID string `json:"parentId"`
+}
+
+// CopyResult is the CLI output for a page copy.
+type CopyResult struct {
+	SourceID string        `json:"sourceId"`
+	ID       string        `json:"id"`
+	Pages    []*CopiedPage `json:"pages"`
+	// Mapping maps each source page ID to the ID of its copy.
+	Mapping map[string]string `json:"mapping"`
+	// Incomplete is set when a copy failed part way, so that Pages and
+	// Mapping only list the copies that were made.
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// Write writes the copy result as JSON to the given writer.
+func (r *CopyResult) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// copyPageRequest is the request body for POST
+// /wiki/rest/api/content/{id}/copy.
+type copyPageRequest struct {
+	CopyAttachments bool `json:"copyAttachments"`
+	CopyLabels      bool `json:"copyLabels"`
+	CopyProperties  bool `json:"copyProperties"`
+	CopyPermissions bool `json:"copyPermissions"`
+	Destination     struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"destination"`
+	PageTitle string `json:"pageTitle,omitempty"`
+}
+
+// copyJob is a source page to copy, with its children.
+type copyJob struct {
+	id       string
+	title    string
+	children []*copyJob
+}
+
+// CopyPage copies a page, with its attachments, labels and properties, under
+// a new parent, and optionally its whole subtree. The source tree is listed
+// before anything is copied, so copying a page into its own subtree
+// terminates. Confluence rejects duplicate titles within a space, so copies
+// into the source's own space need a new title for the root and, with
+// children, a title prefix.
+//
+// If a page fails to copy, the copies already made are not undone: they are
+// returned in an incomplete result along with the error.
+func (c *Client) CopyPage(ctx context.Context, opts CopyOptions) (*CopyResult, error) {
+	if err := ValidatePageID(opts.SourceID); err != nil {
+		return nil, err
+	}
+	if err := ValidatePageID(opts.ParentID); err != nil {
+		return nil, fmt.Errorf("invalid parent: %w", err)
+	}
+
+	root := &copyJob{id: opts.SourceID}
+	if opts.TitlePrefix != "" && opts.Title == "" {
+		summary, err := c.pageSummary(ctx, opts.SourceID)
+		if err != nil {
+			return nil, err
+		}
+		root.title = summary.Title
+	}
+	total := 1
+	if opts.IncludeChildren {
+		n, err := c.collectCopyJobs(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		total += n
+	}
+
+	result := &CopyResult{SourceID: opts.SourceID, Pages: []*CopiedPage{}, Mapping: make(map[string]string)}
+	var copyTree func(job *copyJob, parentID, title string) error
+	copyTree = func(job *copyJob, parentID, title string) error {
+		copied, err := c.copyPage(ctx, job.id, parentID, title)
+		if err != nil {
+			return fmt.Errorf("failed to copy page %s: %w", job.id, err)
+		}
+		result.Pages = append(result.Pages, copied)
+		result.Mapping[copied.SourceID] = copied.ID
+		if opts.Progress != nil {
+			opts.Progress(len(result.Pages), total, copied)
+		}
+		for _, child := range job.children {
+			childTitle := ""
+			if opts.TitlePrefix != "" {
+				childTitle = opts.TitlePrefix + child.title
+			}
+			if err := copyTree(child, copied.ID, childTitle); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	title := opts.Title
+	if title == "" && opts.TitlePrefix != "" {
+		title = opts.TitlePrefix + root.title
+	}
+	err := copyTree(root, opts.ParentID, title)
+	result.ID = result.Mapping[opts.SourceID]
+	if err != nil {
+		result.Incomplete = true
+		return result, err
+	}
+	return result, nil
+}
+
+// collectCopyJobs lists the descendants of job into its children, returning
+// how many were found.
+func (c *Client) collectCopyJobs(ctx context.Context, job *copyJob) (int, error) {
+	children, err := c.listChildren(ctx, job.id)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, child := range children {
+		childJob := &copyJob{id: child.ID, title: child.Title}
+		n, err := c.collectCopyJobs(ctx, childJob)
+		if err != nil {
+			return 0, err
+		}
+		job.children = append(job.children, childJob)
+		count += n + 1
+	}
+	return count, nil
+}
+
+// copyPage copies a single page under parentID.
+func (c *Client) copyPage(ctx context.Context, id, parentID, title string) (*CopiedPage, error) {
+	payload := &copyPageRequest{
+		CopyAttachments: true,
+		CopyLabels:      true,
+		CopyProperties:  true,
+		CopyPermissions: true,
+		PageTitle:       title,
+	}
+	payload.Destination.Type = "parent_page"
+	payload.Destination.Value = parentID
+
+	reqURL := fmt.Sprintf("%s/wiki/rest/api/content/%s/copy", c.cfg.BaseURL(), url.PathEscape(id))
+	body, err := c.doJSON(ctx, "POST", reqURL, payload, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &CopiedPage{SourceID: id, ID: resp.ID, Title: resp.Title, ParentID: parentID}, nil
+}
+
+// archiveRequest is the request body for POST /wiki/rest/api/content/archive.
+type archiveRequest struct {
+	Pages []archivePage `json:"pages"`
+}
+
+type archivePage struct {
+	ID int64 `json:"id"`
+}
+
+// ArchivePages archives the given pages. Confluence archives pages in the
+// background, so the pages may take a moment to disappear from the space.
+func (c *Client) ArchivePages(ctx context.Context, ids []string) error {
+	payload := &archiveRequest{}
+	for _, id := range ids {
+		if err := ValidatePageID(id); err != nil {
+			return err
+		}
+		n, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid page ID %q: %w", id, err)
+		}
+		payload.Pages = append(payload.Pages, archivePage{ID: n})
+	}
+	if len(payload.Pages) == 0 {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/wiki/rest/api/content/archive", c.cfg.BaseURL())
+	_, err := c.doJSON(ctx, "POST", url, payload, http.StatusOK, http.StatusAccepted)
+	return err
+}
@@ -0,0 +1,335 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_MovePage(t *testing.T) {
+	var moved string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/wiki/rest/api/content/123/move/"):
+			moved = r.URL.Path
+			w.Write([]byte(`{"pageId": "123"}`))
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/pages/123":
+			w.Write([]byte(`{"id": "123", "title": "Runbook", "status": "current", "parentId": "456"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	change, err := client.MovePage(context.Background(), "123", "456", MoveAppend)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moved != "/wiki/rest/api/content/123/move/append/456" {
+		t.Errorf("unexpected move request: %s", moved)
+	}
+	if change.ParentID != "456" || change.Status != "current" || change.Title != "Runbook" {
+		t.Errorf("unexpected result: %+v", change)
+	}
+}
+
+func TestClient_MovePage_Invalid(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	tests := []struct {
+		name     string
+		id       string
+		target   string
+		position string
+	}{
+		{"invalid position", "123", "456", "inside"},
+		{"same page", "123", "123", MoveAppend},
+		{"invalid target", "123", "abc", MoveAppend},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.MovePage(context.Background(), tt.id, tt.target, tt.position); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestClient_DeletePage(t *testing.T) {
+	deleted := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/pages/123":
+			w.Write([]byte(`{"id": "123", "title": "Old notes", "status": "current", "parentId": "1"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/wiki/api/v2/pages/123":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	change, err := client.DeletePage(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Error("page was not deleted")
+	}
+	if change.Status != "trashed" || change.Title != "Old notes" {
+		t.Errorf("unexpected result: %+v", change)
+	}
+}
+
+func TestClient_RestorePage(t *testing.T) {
+	var restored restoreRequest
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/pages/123":
+			if r.URL.Query().Get("status") != "trashed" {
+				t.Errorf("expected status=trashed, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"id": "123", "title": "Old notes", "status": "trashed", "parentId": "1", "version": {"number": 4}}`))
+		case r.Method == "PUT" && r.URL.Path == "/wiki/rest/api/content/123":
+			json.NewDecoder(r.Body).Decode(&restored)
+			w.Write([]byte(`{"id": "123", "status": "current"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	change, err := client.RestorePage(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.Status != "current" || restored.Version.Number != 5 || restored.Title != "Old notes" {
+		t.Errorf("unexpected restore request: %+v", restored)
+	}
+	if change.Status != "current" {
+		t.Errorf("unexpected result: %+v", change)
+	}
+}
+
+func TestClient_RestorePage_NotTrashed(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != "GET" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"id": "123", "title": "Live", "status": "current", "version": {"number": 2}}`))
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	if _, err := client.RestorePage(context.Background(), "123"); err == nil {
+		t.Error("expected error for a page that is not in the trash")
+	}
+}
+
+func TestClient_CopyPage_IncludeChildren(t *testing.T) {
+	// Source tree: 10 -> (11 -> 13), 12. The copy goes under 11, inside the
+	// source tree, and must still copy only the original four pages.
+	children := map[string][]string{"10": {"11", "12"}, "11": {"13"}}
+	next := 100
+	var copies []copyPageRequest
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/children"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/wiki/api/v2/pages/"), "/children")
+			results := []map[string]string{}
+			for _, child := range children[id] {
+				results = append(results, map[string]string{"id": child, "title": "Page " + child})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/copy"):
+			var req copyPageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			copies = append(copies, req)
+			// Copies become children of their destination, as in Confluence
+			newID := fmt.Sprint(next)
+			next++
+			children[req.Destination.Value] = append(children[req.Destination.Value], newID)
+			json.NewEncoder(w).Encode(map[string]string{"id": newID, "title": req.PageTitle})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	var progress []string
+	result, err := client.CopyPage(context.Background(), CopyOptions{
+		SourceID:        "10",
+		ParentID:        "11",
+		Title:           "Handbook (copy)",
+		IncludeChildren: true,
+		Progress: func(done, total int, page *CopiedPage) {
+			progress = append(progress, fmt.Sprintf("%d/%d %s", done, total, page.SourceID))
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(copies) != 4 {
+		t.Fatalf("expected 4 copies, got %d", len(copies))
+	}
+	if copies[0].PageTitle != "Handbook (copy)" || copies[1].PageTitle != "" {
+		t.Errorf("title should only be set on the root copy: %+v", copies)
+	}
+	if !copies[0].CopyAttachments || copies[0].Destination.Type != "parent_page" {
+		t.Errorf("unexpected copy request: %+v", copies[0])
+	}
+	if result.ID != "100" || result.Mapping["11"] != "101" || result.Mapping["13"] != "102" || result.Mapping["12"] != "103" {
+		t.Errorf("unexpected mapping: %v", result.Mapping)
+	}
+	if result.Pages[2].ParentID != "101" || result.Pages[3].ParentID != "100" {
+		t.Errorf("children copied under the wrong parents: %+v", result.Pages)
+	}
+	want := "1/4 10,2/4 11,3/4 13,4/4 12"
+	if got := strings.Join(progress, ","); got != want {
+		t.Errorf("progress = %s, want %s", got, want)
+	}
+}
+
+func TestClient_CopyPage_TitlePrefix(t *testing.T) {
+	// Source tree: 10 -> 11 -> 12, copied within its own space
+	children := map[string][]string{"10": {"11"}, "11": {"12"}}
+	var titles []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/pages/10":
+			w.Write([]byte(`{"id": "10", "title": "Handbook"}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/children"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/wiki/api/v2/pages/"), "/children")
+			results := []map[string]string{}
+			for _, child := range children[id] {
+				results = append(results, map[string]string{"id": child, "title": "Page " + child})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/copy"):
+			var req copyPageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			titles = append(titles, req.PageTitle)
+			json.NewEncoder(w).Encode(map[string]string{"id": fmt.Sprint(100 + len(titles)), "title": req.PageTitle})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "prefixed root", want: "2025 Handbook,2025 Page 11,2025 Page 12"},
+		{name: "renamed root", title: "Handbook 2025", want: "Handbook 2025,2025 Page 11,2025 Page 12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			titles = nil
+			_, err := client.CopyPage(context.Background(), CopyOptions{
+				SourceID:        "10",
+				ParentID:        "20",
+				Title:           tt.title,
+				TitlePrefix:     "2025 ",
+				IncludeChildren: true,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(titles, ","); got != tt.want {
+				t.Errorf("titles = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_CopyPage_WithoutChildren(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != "POST" || r.URL.Path != "/wiki/rest/api/content/10/copy" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"id": "200", "title": "Handbook"}`))
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	result, err := client.CopyPage(context.Background(), CopyOptions{SourceID: "10", ParentID: "20"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != "200" || len(result.Pages) != 1 || result.Mapping["10"] != "200" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestClient_CopyPage_PartialFailure(t *testing.T) {
+	// Source tree: 10 -> 11, 12. Copying 12 fails after 10 and 11 are copied.
+	children := map[string][]string{"10": {"11", "12"}}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/children"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/wiki/api/v2/pages/"), "/children")
+			results := []map[string]string{}
+			for _, child := range children[id] {
+				results = append(results, map[string]string{"id": child, "title": "Page " + child})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case r.Method == "POST" && r.URL.Path == "/wiki/rest/api/content/12/copy":
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/copy"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/wiki/rest/api/content/"), "/copy")
+			fmt.Fprintf(w, `{"id": "%s0", "title": "Page %s"}`, id, id)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	result, err := newTestClient(server).CopyPage(context.Background(), CopyOptions{
+		SourceID:        "10",
+		ParentID:        "20",
+		Title:           "Handbook (copy)",
+		IncludeChildren: true,
+	})
+	if err == nil {
+		t.Fatal("expected the failed copy to be returned")
+	}
+	if result == nil || !result.Incomplete {
+		t.Fatalf("expected an incomplete result, got %+v", result)
+	}
+	if result.ID != "100" || len(result.Pages) != 2 || result.Mapping["11"] != "110" || result.Mapping["12"] != "" {
+		t.Errorf("unexpected partial result: %+v %v", result, result.Mapping)
+	}
+}
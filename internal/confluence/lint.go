@@ -0,0 +1,378 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// DefaultLintConcurrency is the number of requests LintLinks makes at once
+// when no concurrency is given.
+const DefaultLintConcurrency = 4
+
+// LinkLintOptions configures a link check of a space.
+type LinkLintOptions struct {
+	SpaceKey string
+	// Concurrency bounds the number of link lookups in flight.
+	Concurrency int
+}
+
+// LinkReport is the result of checking the links in a space.
+type LinkReport struct {
+	Space        string             `json:"space"`
+	PagesChecked int                `json:"pagesChecked"`
+	LinksChecked int                `json:"linksChecked"`
+	BrokenLinks  []*PageBrokenLinks `json:"brokenLinks"`
+	Orphans      []*OrphanPage      `json:"orphans"`
+}
+
+// PageBrokenLinks lists the broken links on one page.
+type PageBrokenLinks struct {
+	ID    string        `json:"id"`
+	Title string        `json:"title"`
+	Links []*BrokenLink `json:"links"`
+}
+
+// BrokenLink is a link whose target page does not exist. A link names its
+// target by space and title or by page ID, depending on how it was written.
+type BrokenLink struct {
+	Text     string `json:"text,omitempty"`
+	SpaceKey string `json:"spaceKey,omitempty"`
+	Title    string `json:"title,omitempty"`
+	PageID   string `json:"pageId,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// OrphanPage is a page no other page in the space links to.
+type OrphanPage struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	ParentID string `json:"parentId,omitempty"`
+}
+
+// Write writes the report as JSON to the given writer.
+func (r *LinkReport) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// pageLink is a link to a page found in a storage body.
+type pageLink struct {
+	text     string
+	spaceKey string
+	title    string
+	pageID   string
+}
+
+// key identifies the link's target for caching lookups.
+func (l pageLink) key() string {
+	if l.pageID != "" {
+		return "id:" + l.pageID
+	}
+	return includeKey(l.spaceKey, l.title)
+}
+
+// linkTarget is the result of resolving a link: the ID of the page it
+// points at, or why it is broken.
+type linkTarget struct {
+	id     string
+	reason string
+}
+
+// LintLinks checks every link to a page in the current pages of a space,
+// reporting the links whose target does not exist and the pages that no
+// other page links to. Links are read from <ac:link> and other <ri:page>
+// references and from <a href> links to pages on the configured site. The
+// space homepage is never reported as an orphan. Page bodies come with the
+// page list, so only links that leave the space cost a request each.
+func (c *Client) LintLinks(ctx context.Context, opts LinkLintOptions) (*LinkReport, error) {
+	if err := ValidateSpaceKey(opts.SpaceKey); err != nil {
+		return nil, err
+	}
+	if opts.Concurrency < 0 {
+		return nil, fmt.Errorf("concurrency must not be negative")
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = DefaultLintConcurrency
+	}
+
+	space, err := c.GetSpace(ctx, opts.SpaceKey)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := c.listSpacePages(ctx, space.ID)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]apiPageSummary, len(pages))
+	links := make([][]pageLink, len(pages))
+	for i, page := range pages {
+		summaries[i] = page.apiPageSummary
+		links[i] = c.storageLinks(page.Body.Storage.Value, space.Key)
+	}
+
+	// Links into the space are resolved from the page list; anything else
+	// is looked up once per target.
+	byID := make(map[string]bool, len(summaries))
+	byTitle := make(map[string]string, len(summaries))
+	for _, s := range summaries {
+		byID[s.ID] = true
+		byTitle[s.Title] = s.ID
+	}
+	targets := make(map[string]*linkTarget)
+	var lookups []pageLink
+	for _, pageLinks := range links {
+		for _, link := range pageLinks {
+			key := link.key()
+			if _, ok := targets[key]; ok {
+				continue
+			}
+			switch {
+			case link.pageID != "" && byID[link.pageID]:
+				targets[key] = &linkTarget{id: link.pageID}
+			case link.pageID == "" && link.spaceKey == space.Key:
+				if id, ok := byTitle[link.title]; ok {
+					targets[key] = &linkTarget{id: id}
+				} else {
+					targets[key] = &linkTarget{reason: "page not found"}
+				}
+			default:
+				targets[key] = &linkTarget{}
+				lookups = append(lookups, link)
+			}
+		}
+	}
+	err = forEachBounded(ctx, len(lookups), opts.Concurrency, func(ctx context.Context, i int) error {
+		target, err := c.resolveLink(ctx, lookups[i])
+		if err != nil {
+			return err
+		}
+		*targets[lookups[i].key()] = *target
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LinkReport{
+		Space:        space.Key,
+		PagesChecked: len(summaries),
+		BrokenLinks:  []*PageBrokenLinks{},
+		Orphans:      []*OrphanPage{},
+	}
+	inbound := make(map[string]bool)
+	for i, s := range summaries {
+		var broken []*BrokenLink
+		for _, link := range links[i] {
+			report.LinksChecked++
+			target := targets[link.key()]
+			if target.reason != "" {
+				broken = append(broken, &BrokenLink{
+					Text:     link.text,
+					SpaceKey: link.spaceKey,
+					Title:    link.title,
+					PageID:   link.pageID,
+					Reason:   target.reason,
+				})
+			} else if target.id != s.ID {
+				inbound[target.id] = true
+			}
+		}
+		if len(broken) > 0 {
+			report.BrokenLinks = append(report.BrokenLinks, &PageBrokenLinks{ID: s.ID, Title: s.Title, Links: broken})
+		}
+	}
+	for _, s := range summaries {
+		if !inbound[s.ID] && s.ID != space.HomepageID {
+			report.Orphans = append(report.Orphans, &OrphanPage{ID: s.ID, Title: s.Title, ParentID: s.ParentID})
+		}
+	}
+	return report, nil
+}
+
+// apiSpacePage is a page in a space's page list, with its storage body.
+type apiSpacePage struct {
+	apiPageSummary
+	Body struct {
+		Storage struct {
+			Value string `json:"value"`
+		} `json:"storage"`
+	} `json:"body"`
+}
+
+// listSpacePages returns the current and archived pages of a space with
+// their storage bodies.
+func (c *Client) listSpacePages(ctx context.Context, spaceID string) ([]apiSpacePage, error) {
+	var pages []apiSpacePage
+	path := fmt.Sprintf("/wiki/api/v2/spaces/%s/pages?body-format=storage&limit=250", url.PathEscape(spaceID))
+	err := c.getPaged(ctx, path, func(body []byte) error {
+		var resp struct {
+			Results []apiSpacePage `json:"results"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		pages = append(pages, resp.Results...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// storageLinks returns the links to pages in a storage body, in document
+// order. References without a space key are to spaceKey. Bodies that cannot
+// be parsed have no links.
+func (c *Client) storageLinks(storage, spaceKey string) []pageLink {
+	root, err := parseStorage(storage)
+	if err != nil {
+		return nil
+	}
+
+	var links []pageLink
+	var walk func(n *storageNode, text string)
+	walk = func(n *storageNode, text string) {
+		switch n.name {
+		case "ac:link":
+			text = strings.TrimSpace(n.textContent())
+		case "ri:page":
+			link := pageLink{text: text, pageID: n.attr("ri:content-id")}
+			if link.pageID == "" {
+				link.spaceKey = n.attr("ri:space-key")
+				if link.spaceKey == "" {
+					link.spaceKey = spaceKey
+				}
+				link.title = n.attr("ri:content-title")
+			}
+			if link.pageID != "" || link.title != "" {
+				links = append(links, link)
+			}
+			return
+		case "a":
+			if id, ok := c.hrefPageID(n.attr("href")); ok {
+				links = append(links, pageLink{text: strings.TrimSpace(n.textContent()), pageID: id})
+			}
+		}
+		for _, child := range n.children {
+			walk(child, text)
+		}
+	}
+	walk(root, "")
+	return links
+}
+
+// hrefPageID returns the ID of the page an href links to, if it is a page
+// link on the configured site. Site-relative links ("/wiki/...") count.
+func (c *Client) hrefPageID(href string) (string, bool) {
+	// A bare ID is a relative link, not a page reference
+	if !strings.Contains(href, "/") {
+		return "", false
+	}
+	id, err := ParsePageRef(href, c.cfg)
+	if err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+// resolveLink looks up a link to a page outside the space's page list. A
+// page or space that does not exist makes the link broken; other errors
+// are returned.
+func (c *Client) resolveLink(ctx context.Context, link pageLink) (*linkTarget, error) {
+	if link.pageID != "" {
+		summary, err := c.pageSummary(ctx, link.pageID)
+		if isNotFound(err) {
+			return &linkTarget{reason: "page not found"}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if summary.Status == "trashed" {
+			return &linkTarget{reason: "page is in the trash"}, nil
+		}
+		return &linkTarget{id: summary.ID}, nil
+	}
+
+	spaceID, err := c.lookupSpaceID(ctx, link.spaceKey)
+	if isNotFound(err) {
+		return &linkTarget{reason: "space not found"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("space-id", spaceID)
+	query.Set("title", link.title)
+	body, err := c.doJSON(ctx, "GET", c.cfg.BaseURL()+"/wiki/api/v2/pages?"+query.Encode(), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Results []apiPageSummary `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return &linkTarget{reason: "page not found"}, nil
+	}
+	return &linkTarget{id: resp.Results[0].ID}, nil
+}
+
+// isNotFound reports whether err is an API not-found error.
+func isNotFound(err error) bool {
+	var apiErr *httpclient.APIError
+	return errors.As(err, &apiErr) && apiErr.Response.Error == httpclient.ErrTypeNotFound
+}
+
+// forEachBounded calls fn for 0..n-1 with at most limit calls running at
+// once. It stops starting calls after the first error, which it returns
+// once the running calls have finished.
+func forEachBounded(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,161 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_LintLinks(t *testing.T) {
+	var site string
+	bodies := map[string]string{
+		"1": `<p><ac:link><ri:page ri:content-title="Runbook"/><ac:plain-text-link-body><![CDATA[the runbook]]></ac:plain-text-link-body></ac:link></p>` +
+			`<p><ac:link><ri:page ri:content-title="Deleted page"/></ac:link></p>` +
+			`<p><a href="/wiki/spaces/ENG/pages/3/Design">Design</a> <a href="https://example.com/wiki/x/AAA">elsewhere</a></p>`,
+		"2": `<p><ac:link><ri:page ri:space-key="OPS" ri:content-title="Oncall"/></ac:link></p>` +
+			`<p><ac:link><ri:page ri:space-key="GONE" ri:content-title="Anything"/></ac:link></p>` +
+			`<p><a href="https://SITE/wiki/pages/viewpage.action?pageId=77">old page</a></p>` +
+			`<p><ac:link><ri:page ri:content-title="Runbook"/></ac:link></p>`,
+		"3": `<p>No links</p>`,
+		"4": `<p><ac:link><ri:page ri:content-title="Home"/></ac:link><ac:link><ri:page ri:content-title="Old notes"/></ac:link></p>`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/wiki/api/v2/spaces":
+			switch r.URL.Query().Get("keys") {
+			case "ENG":
+				w.Write([]byte(`{"results": [{"id": "9", "key": "ENG", "homepageId": "1"}]}`))
+			case "OPS":
+				w.Write([]byte(`{"results": [{"id": "8", "key": "OPS"}]}`))
+			default:
+				w.Write([]byte(`{"results": []}`))
+			}
+		case r.URL.Path == "/wiki/api/v2/spaces/9/pages":
+			if got := r.URL.Query().Get("body-format"); got != "storage" {
+				t.Errorf("expected body-format=storage, got %q", got)
+			}
+			var results []map[string]interface{}
+			for _, p := range []struct{ id, title, parentID string }{
+				{"1", "Home", ""}, {"2", "Runbook", "1"}, {"3", "Design", "1"}, {"4", "Old notes", "3"},
+			} {
+				results = append(results, map[string]interface{}{
+					"id": p.id, "title": p.title, "parentId": p.parentID,
+					"body": map[string]interface{}{"storage": map[string]string{"value": strings.ReplaceAll(bodies[p.id], "SITE", site)}},
+				})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case r.URL.Path == "/wiki/api/v2/pages":
+			q := r.URL.Query()
+			if q.Get("space-id") == "8" && q.Get("title") == "Oncall" {
+				w.Write([]byte(`{"results": [{"id": "50", "title": "Oncall"}]}`))
+				return
+			}
+			w.Write([]byte(`{"results": []}`))
+		case r.URL.Path == "/wiki/api/v2/pages/77":
+			// Only links that leave the space are looked up
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	site = strings.TrimPrefix(server.URL, "https://")
+
+	report, err := newTestClient(server).LintLinks(context.Background(), LinkLintOptions{SpaceKey: "ENG"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.PagesChecked != 4 || report.LinksChecked != 9 {
+		t.Errorf("checked %d pages and %d links, want 4 and 9", report.PagesChecked, report.LinksChecked)
+	}
+
+	var broken []string
+	for _, page := range report.BrokenLinks {
+		for _, link := range page.Links {
+			broken = append(broken, fmt.Sprintf("%s:%s/%s%s=%s", page.ID, link.SpaceKey, link.Title, link.PageID, link.Reason))
+		}
+	}
+	want := "1:ENG/Deleted page=page not found,2:GONE/Anything=space not found,2:/77=page not found"
+	if got := strings.Join(broken, ","); got != want {
+		t.Errorf("broken links = %s, want %s", got, want)
+	}
+	if report.BrokenLinks[1].Links[0].Text != "" || report.BrokenLinks[1].Links[1].Text != "old page" {
+		t.Errorf("unexpected link text: %+v", report.BrokenLinks[1].Links)
+	}
+
+	// Page 4 only links to itself, and the homepage is never an orphan
+	if len(report.Orphans) != 1 || report.Orphans[0].ID != "4" || report.Orphans[0].ParentID != "3" {
+		t.Errorf("unexpected orphans: %+v", report.Orphans)
+	}
+}
+
+func TestClient_LintLinks_Concurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/wiki/api/v2/spaces":
+			w.Write([]byte(`{"results": [{"id": "9", "key": "ENG"}]}`))
+		case r.URL.Path == "/wiki/api/v2/spaces/9/pages":
+			// Each page links to a page outside the space
+			var results []string
+			for i := 1; i <= 10; i++ {
+				results = append(results, fmt.Sprintf(`{"id": "%d", "title": "Page %d", "body": {"storage": {"value": "<p><ac:link><ri:page ri:content-id=\"%d\"/></ac:link></p>"}}}`, i, i, 100+i))
+			}
+			w.Write([]byte(`{"results": [` + strings.Join(results, ",") + `]}`))
+		case strings.HasPrefix(r.URL.Path, "/wiki/api/v2/pages/"):
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			id := strings.TrimPrefix(r.URL.Path, "/wiki/api/v2/pages/")
+			if id == "107" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, `{"id": "%s", "title": "Page %s"}`, id, id)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).LintLinks(context.Background(), LinkLintOptions{SpaceKey: "ENG", Concurrency: 2})
+	if err == nil {
+		t.Error("expected the failed lookup to be returned")
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("%d requests in flight, want at most 2", got)
+	}
+}
+
+func TestForEachBounded(t *testing.T) {
+	var calls atomic.Int32
+	boom := errors.New("boom")
+	err := forEachBounded(context.Background(), 100, 1, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		if i == 3 {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("expected boom, got %v", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("%d calls, want 4 (no calls after the error)", got)
+	}
+}
@@ -0,0 +1,228 @@
+package confluence
+
+import (
+	"encoding/hex"
+	"html"
+	"strings"
+)
+
+// Macro is a Confluence macro (ac:structured-macro) passed to a MacroHandler.
+type Macro struct {
+	Name string
+	// Params holds the macro parameters by name. Parameters that refer to a
+	// resource, such as a page or user, hold its title, key or ID.
+	Params map[string]string
+	// Body is the plain-text body, such as the source of a code macro.
+	Body string
+	// RichBody is the rich-text body rendered as HTML. Macros nested in it
+	// have already been converted.
+	RichBody string
+	// Refs resolves attachments, users, Jira issues and child pages. It is
+	// nil when the caller has no references.
+	Refs *References
+
+	node *storageNode
+}
+
+// MacroHandler converts a macro to HTML for the Markdown converter.
+type MacroHandler func(m *Macro) string
+
+// MacroHandlers maps macro names to the handlers used by ToMarkdown and
+// ToPlainText. Macros without a handler are replaced with a placeholder,
+// followed by their rich-text body if they have one. Register a handler
+// here to support a custom macro.
+var MacroHandlers = map[string]MacroHandler{
+	"toc":      tocMacro,
+	"drawio":   drawioMacro,
+	"code":     codeMacroHandler,
+	"noformat": codeMacroHandler,
+	"info":     admonitionMacro("Info"),
+	"warning":  admonitionMacro("Warning"),
+	"note":     admonitionMacro("Note"),
+	"tip":      admonitionMacro("Tip"),
+	"expand":   expandMacro,
+	"status":   statusMacro,
+	"jira":     jiraMacro,
+	"anchor":   anchorMacro,
+	"panel":    panelMacro,
+	"excerpt":  excerptMacro,
+	"children": childrenMacro,
+}
+
+// newMacro collects the name, parameters and bodies of a macro element.
+func (r *storageRenderer) newMacro(n *storageNode) *Macro {
+	m := &Macro{Name: n.attr("ac:name"), Params: make(map[string]string), Refs: r.refs, node: n}
+	for _, c := range n.children {
+		switch c.name {
+		case "ac:parameter":
+			m.Params[c.attr("ac:name")] = parameterValue(c)
+		case "ac:plain-text-body":
+			m.Body = c.textContent()
+		case "ac:rich-text-body":
+			var b strings.Builder
+			r.renderChildren(&b, c)
+			m.RichBody = b.String()
+		}
+	}
+	return m
+}
+
+// parameterValue returns the text of a macro parameter, or the identifying
+// attribute of the resource it refers to.
+func parameterValue(n *storageNode) string {
+	if text := strings.TrimSpace(n.textContent()); text != "" {
+		return text
+	}
+	for _, attr := range []string{"ri:content-title", "ri:space-key", "ri:account-id", "ri:filename", "ri:value"} {
+		if ref := findAttr(n, attr); ref != "" {
+			return ref
+		}
+	}
+	return ""
+}
+
+// findAttr returns the first value of the named attribute on n or its
+// descendants.
+func findAttr(n *storageNode, name string) string {
+	if v := n.attr(name); v != "" {
+		return v
+	}
+	for _, c := range n.children {
+		if v := findAttr(c, name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// placeholderMacro renders a macro that has no handler.
+func placeholderMacro(m *Macro) string {
+	return "<p>CFPLACEHOLDER:MACRO:" + html.EscapeString(m.Name) + ":</p>" + m.RichBody
+}
+
+func tocMacro(m *Macro) string {
+	return "<p>CFPLACEHOLDER:TOC:</p>"
+}
+
+func drawioMacro(m *Macro) string {
+	return "<p>CFPLACEHOLDER:DIAGRAM:" + html.EscapeString(m.Params["diagramName"]) + ":</p>"
+}
+
+func codeMacroHandler(m *Macro) string {
+	return `<pre><code class="language-` + html.EscapeString(m.Params["language"]) + `">` +
+		html.EscapeString(m.Body) + "</code></pre>"
+}
+
+// admonitionMacro renders info/warning/note/tip macros as a blockquote whose
+// first paragraph starts with the label.
+func admonitionMacro(label string) MacroHandler {
+	return func(m *Macro) string {
+		prefix := "<strong>" + label + ":</strong> "
+		if title := m.Params["title"]; title != "" {
+			return "<blockquote><p>" + prefix + "<strong>" + html.EscapeString(title) + "</strong></p>" +
+				m.RichBody + "</blockquote>"
+		}
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(m.RichBody), "<p>"); ok {
+			return "<blockquote><p>" + prefix + rest + "</blockquote>"
+		}
+		return "<blockquote><p>" + strings.TrimSpace(prefix) + "</p>" + m.RichBody + "</blockquote>"
+	}
+}
+
+// expandMacro renders an expand macro as a <details> section. The title is
+// hex-encoded in the placeholder so the Markdown converter leaves it alone.
+func expandMacro(m *Macro) string {
+	title := m.Params["title"]
+	if title == "" {
+		title = "Expand"
+	}
+	return "<p>CFPLACEHOLDER:EXPANDSTART:" + hex.EncodeToString([]byte(title)) + ":</p>" +
+		m.RichBody + "<p>CFPLACEHOLDER:EXPANDEND:</p>"
+}
+
+// literalText returns a placeholder that becomes text in the Markdown output
+// without being escaped, for renderings such as "[STATUS: green Done]".
+func literalText(text string) string {
+	return "CFPLACEHOLDER:TEXT:" + hex.EncodeToString([]byte(text)) + ":"
+}
+
+// statusMacro renders a status lozenge as [STATUS: colour text].
+func statusMacro(m *Macro) string {
+	colour := strings.ToLower(m.Params["colour"])
+	if colour == "" {
+		colour = "grey"
+	}
+	return literalText("[STATUS: " + strings.TrimSpace(colour+" "+m.Params["title"]) + "]")
+}
+
+// jiraMacro renders a single-issue Jira macro as a link to the issue,
+// followed by its status when known. Issue lists (JQL) become a placeholder.
+func jiraMacro(m *Macro) string {
+	key := m.Params["key"]
+	if key == "" {
+		if jql := m.Params["jqlQuery"]; jql != "" {
+			return "<p>" + literalText("[Jira issues: "+jql+"]") + "</p>"
+		}
+		return placeholderMacro(m)
+	}
+
+	out := html.EscapeString(key)
+	if href := m.Refs.issueURL(key); href != "" {
+		out = `<a href="` + html.EscapeString(href) + `">` + out + "</a>"
+	}
+	if m.Refs != nil && m.Refs.JiraStatuses[key] != "" {
+		out += " (" + html.EscapeString(m.Refs.JiraStatuses[key]) + ")"
+	}
+	return out
+}
+
+// anchorMacro renders an anchor as an HTML anchor that links can target.
+func anchorMacro(m *Macro) string {
+	name := m.Params[""]
+	if name == "" {
+		return ""
+	}
+	return "CFPLACEHOLDER:ANCHOR:" + hex.EncodeToString([]byte(name)) + ":"
+}
+
+// panelMacro renders a panel as a blockquote, led by its title in bold.
+func panelMacro(m *Macro) string {
+	var b strings.Builder
+	b.WriteString("<blockquote>")
+	if title := m.Params["title"]; title != "" {
+		b.WriteString("<p><strong>" + html.EscapeString(title) + "</strong></p>")
+	}
+	b.WriteString(m.RichBody)
+	b.WriteString("</blockquote>")
+	return b.String()
+}
+
+// excerptMacro renders the excerpt body in place, unless it is hidden.
+func excerptMacro(m *Macro) string {
+	if m.Params["hidden"] == "true" {
+		return ""
+	}
+	return m.RichBody
+}
+
+// childrenMacro renders the child page list, or a placeholder when the
+// children were not looked up.
+func childrenMacro(m *Macro) string {
+	if m.Refs == nil || m.Refs.Children == nil {
+		return "<p>" + literalText("[Child pages]") + "</p>"
+	}
+
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, child := range m.Refs.Children {
+		b.WriteString("<li>")
+		if href := m.Refs.pageURL(child.ID); href != "" {
+			b.WriteString(`<a href="` + html.EscapeString(href) + `">` + html.EscapeString(child.Title) + "</a>")
+		} else {
+			b.WriteString(html.EscapeString(child.Title))
+		}
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
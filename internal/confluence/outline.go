@@ -0,0 +1,214 @@
+package confluence
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// Heading is a heading in a page outline, with the headings nested under it.
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	// Anchor is the fragment that links to the heading in the page URL.
+	Anchor   string     `json:"anchor"`
+	Children []*Heading `json:"children,omitempty"`
+}
+
+// PageOutline is the heading tree of a page.
+type PageOutline struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Version  int        `json:"version"`
+	Headings []*Heading `json:"headings"`
+}
+
+// Write writes the outline as JSON to the given writer.
+func (o *PageOutline) Write(w interface{ Write([]byte) (int, error) }) error {
+	if o.Headings == nil {
+		o.Headings = []*Heading{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(o)
+}
+
+// Outline returns the page's headings as a tree, from the converted Markdown.
+func (p *Page) Outline() (*PageOutline, error) {
+	markdown, err := p.Markdown()
+	if err != nil {
+		return nil, err
+	}
+	return &PageOutline{ID: p.ID, Title: p.Title, Version: p.Version, Headings: MarkdownOutline(markdown)}, nil
+}
+
+// MarkdownOutline returns the ATX headings of a Markdown document as a tree.
+// A heading nests under the closest preceding heading of a lower level.
+func MarkdownOutline(markdown string) []*Heading {
+	var roots []*Heading
+	var stack []*Heading
+	anchors := make(map[string]int)
+
+	for _, s := range splitMarkdownSections(markdown) {
+		if s.heading == "" {
+			continue
+		}
+		text := headingText(s.heading)
+		h := &Heading{Level: s.level, Text: text, Anchor: headingAnchor(text, anchors)}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, h)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, h)
+		}
+		stack = append(stack, h)
+	}
+	return roots
+}
+
+var (
+	markdownLinkRe   = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	markdownEscapeRe = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!<>|~])`)
+)
+
+// headingText returns the text of a Markdown heading without inline
+// formatting.
+func headingText(heading string) string {
+	text := markdownLinkRe.ReplaceAllString(heading, "$1")
+	for _, marker := range []string{"**", "__", "`", "~~"} {
+		text = strings.ReplaceAll(text, marker, "")
+	}
+	text = markdownEscapeRe.ReplaceAllString(text, "$1")
+	return strings.TrimSpace(text)
+}
+
+// headingAnchor returns the anchor Confluence gives a heading: its text with
+// spaces replaced by hyphens. Repeated headings get a numeric suffix, which
+// seen tracks.
+func headingAnchor(text string, seen map[string]int) string {
+	anchor := strings.Join(strings.Fields(text), "-")
+	n := seen[anchor]
+	seen[anchor]++
+	if n > 0 {
+		return fmt.Sprintf("%s.%d", anchor, n)
+	}
+	return anchor
+}
+
+// minHeadingScore is the lowest match score at which a heading counts as
+// matching a section query.
+const minHeadingScore = 0.5
+
+// ExtractSection returns the section of a Markdown document under the
+// heading that best matches query, from the heading down to the next
+// heading of the same or a higher level. Headings are matched case- and
+// punctuation-insensitively, preferring exact matches, then headings that
+// contain the query's words, then headings spelled similarly.
+func ExtractSection(markdown, query string) (string, error) {
+	if normalizeHeading(query) == "" {
+		return "", fmt.Errorf("section heading cannot be empty")
+	}
+
+	sections := splitMarkdownSections(markdown)
+	best, bestScore := -1, 0.0
+	for i, s := range sections {
+		if s.heading == "" {
+			continue
+		}
+		if score := headingScore(query, headingText(s.heading)); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 || bestScore < minHeadingScore {
+		return "", &httpclient.APIError{Response: &httpclient.ErrorResponse{
+			Error:   httpclient.ErrTypeNotFound,
+			Message: fmt.Sprintf("no heading matches %q", query),
+		}}
+	}
+
+	parts := []string{sections[best].text}
+	for _, s := range sections[best+1:] {
+		if s.level <= sections[best].level {
+			break
+		}
+		parts = append(parts, s.text)
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// headingWords lowercases text and splits it into words, dropping
+// punctuation.
+func headingWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalizeHeading lowercases text and reduces it to words separated by
+// single spaces.
+func normalizeHeading(text string) string {
+	return strings.Join(headingWords(text), " ")
+}
+
+// containsWords reports whether sub appears as a run of whole words in
+// words.
+func containsWords(words, sub []string) bool {
+	for i := 0; i+len(sub) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(sub)], sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// headingScore rates how well a heading matches a query, from 0 (no match)
+// to 1 (the same words). Containment is by whole words, so "plan" matches
+// "Rollout plan" but not "Planning".
+func headingScore(query, heading string) float64 {
+	q, h := headingWords(query), headingWords(heading)
+	switch {
+	case len(q) == 0 || len(h) == 0:
+		return 0
+	case slices.Equal(q, h):
+		return 1
+	case containsWords(h, q):
+		return 0.9
+	case containsWords(q, h):
+		return 0.8
+	}
+
+	// Tolerate typos by comparing the spelling of the whole heading
+	qr, hr := []rune(strings.Join(q, " ")), []rune(strings.Join(h, " "))
+	similarity := 1 - float64(levenshtein(qr, hr))/float64(max(len(qr), len(hr)))
+	return similarity * 0.75
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,197 @@
+package confluence
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+const outlineDoc = `Intro text.
+
+# Design
+
+Overview.
+
+## Goals
+
+- Fast
+
+## Rollout plan
+
+Ship it in stages.
+
+### Stage 1
+
+Internal users.
+
+` + "```sh\n# not a heading\n```" + `
+
+## Goals
+
+Repeated heading.
+
+# [Appendix](https://example.com) **A**
+
+The end.`
+
+func TestMarkdownOutline(t *testing.T) {
+	headings := MarkdownOutline(outlineDoc)
+
+	var lines []string
+	var walk func(hs []*Heading, indent string)
+	walk = func(hs []*Heading, indent string) {
+		for _, h := range hs {
+			lines = append(lines, indent+h.Text+" #"+h.Anchor)
+			walk(h.Children, indent+"  ")
+		}
+	}
+	walk(headings, "")
+
+	want := []string{
+		"Design #Design",
+		"  Goals #Goals",
+		"  Rollout plan #Rollout-plan",
+		"    Stage 1 #Stage-1",
+		"  Goals #Goals.1",
+		"Appendix A #Appendix-A",
+	}
+	if got := strings.Join(lines, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("outline:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestMarkdownOutline_SkippedLevels(t *testing.T) {
+	headings := MarkdownOutline("### Deep\n\n# Top\n\n### Under top")
+	if len(headings) != 2 || headings[0].Text != "Deep" || headings[1].Text != "Top" {
+		t.Fatalf("unexpected roots: %+v", headings)
+	}
+	if len(headings[1].Children) != 1 || headings[1].Children[0].Text != "Under top" {
+		t.Errorf("unexpected children: %+v", headings[1].Children)
+	}
+}
+
+func TestExtractSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "exact heading includes subsections",
+			query:   "Rollout plan",
+			want:    []string{"## Rollout plan", "Ship it in stages.", "### Stage 1", "# not a heading"},
+			notWant: []string{"Repeated heading", "Overview"},
+		},
+		{
+			name:    "case and punctuation insensitive",
+			query:   "rollout-PLAN",
+			want:    []string{"## Rollout plan"},
+			notWant: []string{"Repeated heading"},
+		},
+		{
+			name:  "typo",
+			query: "Rolout paln",
+			want:  []string{"## Rollout plan"},
+		},
+		{
+			name:  "partial heading",
+			query: "rollout",
+			want:  []string{"## Rollout plan"},
+		},
+		{
+			name:    "top level section runs to the next top level heading",
+			query:   "design",
+			want:    []string{"# Design", "## Goals", "Repeated heading"},
+			notWant: []string{"Intro text", "The end."},
+		},
+		{
+			name:    "first of repeated headings",
+			query:   "Goals",
+			want:    []string{"## Goals", "- Fast"},
+			notWant: []string{"Rollout", "Repeated heading"},
+		},
+		{
+			name:  "formatted heading",
+			query: "appendix a",
+			want:  []string{"The end."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractSection(outlineDoc, tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in section:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("unexpected %q in section:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadingScore(t *testing.T) {
+	tests := []struct {
+		query   string
+		heading string
+		want    float64
+	}{
+		{query: "rollout plan", heading: "Rollout plan", want: 1},
+		{query: "plan", heading: "Rollout plan", want: 0.9},
+		{query: "the rollout plan", heading: "Rollout plan", want: 0.8},
+		{query: "", heading: "Rollout plan", want: 0},
+	}
+	for _, tt := range tests {
+		if got := headingScore(tt.query, tt.heading); got != tt.want {
+			t.Errorf("headingScore(%q, %q) = %v, want %v", tt.query, tt.heading, got, tt.want)
+		}
+	}
+
+	// Containment is by whole words, not substrings
+	for _, tt := range []struct{ query, heading string }{
+		{"plan", "Planning"},
+		{"go", "Goals and non-goals"},
+		{"rollout planning", "Rollout plan"},
+	} {
+		if got := headingScore(tt.query, tt.heading); got >= 0.8 {
+			t.Errorf("headingScore(%q, %q) = %v, want a partial score", tt.query, tt.heading, got)
+		}
+	}
+}
+
+func TestExtractSection_NoMatch(t *testing.T) {
+	_, err := ExtractSection(outlineDoc, "Security review")
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Response.Error != httpclient.ErrTypeNotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if !strings.Contains(apiErr.Response.Message, "Security review") {
+		t.Errorf("message should name the heading: %s", apiErr.Response.Message)
+	}
+}
+
+func TestPage_Section(t *testing.T) {
+	page := &Page{ID: "1", Title: "Design", Version: 3, Body: `<h1>Design</h1><p>Overview</p><h2>Rollout plan</h2><p>In stages</p>`}
+	section, err := page.Section("rollout plan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if section.ID != "1" || section.Version != 3 {
+		t.Errorf("metadata not copied: %+v", section)
+	}
+	if !strings.HasPrefix(section.Body, "## Rollout plan") || !strings.Contains(section.Body, "In stages") || strings.Contains(section.Body, "Overview") {
+		t.Errorf("unexpected section body:\n%s", section.Body)
+	}
+	if page.Body == section.Body {
+		t.Error("original page should keep its storage body")
+	}
+}
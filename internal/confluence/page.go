@@ -10,26 +10,47 @@ import (
 type Page struct {
 	ID       string `json:"id"`
 	Title    string `json:"title"`
-	SpaceKey string `json:"spaceKey"` // Actually spaceId from API
+	SpaceKey string `json:"spaceKey"`
+	SpaceID  string `json:"spaceId"`
 	Version  int    `json:"version"`
+	Created  string `json:"created,omitempty"`
 	Updated  string `json:"updated"`
 	Body     string `json:"body"`
+	// BodyFormat is the format of Body when it is not BodyFormatStorage.
+	BodyFormat string `json:"bodyFormat,omitempty"`
+
+	// refs resolves references when converting the body to Markdown; see
+	// Client.ResolveReferences.
+	refs *References
+	// warnings lists what the last conversion to Markdown could not
+	// represent.
+	warnings []string
 }
 
 // apiPageResponse represents the Confluence API v2 response structure.
 type apiPageResponse struct {
-	ID      string `json:"id"`
-	Title   string `json:"title"`
-	SpaceID string `json:"spaceId"`
-	Version struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	SpaceID   string `json:"spaceId"`
+	CreatedAt string `json:"createdAt"`
+	Version   struct {
 		Number    int    `json:"number"`
 		CreatedAt string `json:"createdAt"`
 	} `json:"version"`
 	Body struct {
-		Storage *struct {
-			Value string `json:"value"`
-		} `json:"storage"`
+		Storage        *apiBodyValue `json:"storage"`
+		AtlasDocFormat *apiBodyValue `json:"atlas_doc_format"`
+		View           *apiBodyValue `json:"view"`
 	} `json:"body"`
+	Links struct {
+		WebUI string `json:"webui"`
+		Base  string `json:"base"`
+	} `json:"_links"`
+}
+
+// apiBodyValue is a page body in one format.
+type apiBodyValue struct {
+	Value string `json:"value"`
 }
 
 // ParseAPIResponse parses a Confluence API response into a Page.
@@ -40,18 +61,24 @@ func ParseAPIResponse(data []byte) (*Page, error) {
 	}
 
 	page := &Page{
-		ID:       resp.ID,
-		Title:    resp.Title,
-		SpaceKey: resp.SpaceID, // Note: v2 API returns spaceId, not space key
-		Version:  resp.Version.Number,
-		Updated:  resp.Version.CreatedAt,
+		ID:      resp.ID,
+		Title:   resp.Title,
+		SpaceID: resp.SpaceID, // v2 API returns spaceId; the key is resolved separately
+		Version: resp.Version.Number,
+		Created: resp.CreatedAt,
+		Updated: resp.Version.CreatedAt,
 	}
 
 	// Handle body content
-	if resp.Body.Storage != nil {
+	switch {
+	case resp.Body.Storage != nil:
 		page.Body = resp.Body.Storage.Value
-	} else {
-		page.Body = ""
+	case resp.Body.AtlasDocFormat != nil:
+		page.Body = resp.Body.AtlasDocFormat.Value
+		page.BodyFormat = BodyFormatADF
+	case resp.Body.View != nil:
+		page.Body = resp.Body.View.Value
+		page.BodyFormat = BodyFormatView
 	}
 
 	return page, nil
@@ -66,31 +93,70 @@ func (p *Page) Write(w interface{ Write([]byte) (int, error) }) error {
 
 // WriteMarkdown writes the page as JSON with the body converted to Markdown.
 func (p *Page) WriteMarkdown(w interface{ Write([]byte) (int, error) }) error {
-	markdown, err := ToMarkdown(p.Body)
+	markdown, err := p.Markdown()
+	if err != nil {
+		return err
+	}
+	return p.withBody(markdown).Write(w)
+}
+
+// Markdown returns the page body converted to Markdown. ADF bodies are
+// converted with the renderer used for Jira content, without the references
+// resolved for storage format; what they contain that Markdown cannot
+// represent is then listed by Warnings. View bodies are HTML, which converts
+// like storage format without macros.
+func (p *Page) Markdown() (string, error) {
+	if p.BodyFormat == BodyFormatADF {
+		markdown, warnings, err := ADFToMarkdown(p.Body)
+		p.warnings = warnings
+		return markdown, err
+	}
+	markdown, err := ToMarkdownWithReferences(p.Body, p.refs)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert body to markdown: %w", err)
+	}
+	return markdown, nil
+}
+
+// Warnings returns the content that the last call to Markdown could not
+// represent, such as macros in an ADF body.
+func (p *Page) Warnings() []string {
+	return p.warnings
+}
+
+// Section returns a copy of the page whose body is the Markdown of one
+// section, found by heading as for ExtractSection.
+func (p *Page) Section(heading string) (*Page, error) {
+	markdown, err := p.Markdown()
+	if err != nil {
+		return nil, err
+	}
+	section, err := ExtractSection(markdown, heading)
 	if err != nil {
-		return fmt.Errorf("failed to convert body to markdown: %w", err)
+		return nil, err
 	}
+	return p.withBody(section), nil
+}
 
-	// Create a copy with converted body
-	converted := &Page{
+// withBody returns a copy of the page with a different body.
+func (p *Page) withBody(body string) *Page {
+	return &Page{
 		ID:       p.ID,
 		Title:    p.Title,
 		SpaceKey: p.SpaceKey,
+		SpaceID:  p.SpaceID,
 		Version:  p.Version,
+		Created:  p.Created,
 		Updated:  p.Updated,
-		Body:     markdown,
+		Body:     body,
 	}
-
-	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(converted)
 }
 
 // WriteBodyOnly writes just the Markdown body without JSON wrapper.
 func (p *Page) WriteBodyOnly(w interface{ Write([]byte) (int, error) }) error {
-	markdown, err := ToMarkdown(p.Body)
+	markdown, err := p.Markdown()
 	if err != nil {
-		return fmt.Errorf("failed to convert body to markdown: %w", err)
+		return err
 	}
 
 	_, err = w.Write([]byte(markdown))
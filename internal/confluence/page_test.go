@@ -11,7 +11,8 @@ func TestPage_JSON(t *testing.T) {
 	page := &Page{
 		ID:       "123456789",
 		Title:    "Test Page",
-		SpaceKey: "65011",
+		SpaceKey: "DOCS",
+		SpaceID:  "65011",
 		Version:  5,
 		Updated:  "2026-01-20T15:45:00.000Z",
 		Body:     "<p>Page content</p>",
@@ -72,8 +73,11 @@ func TestParseAPIResponse(t *testing.T) {
 	if page.Title != "Test Page Title" {
 		t.Errorf("expected title 'Test Page Title', got '%s'", page.Title)
 	}
-	if page.SpaceKey != "65011" {
-		t.Errorf("expected spaceKey '65011', got '%s'", page.SpaceKey)
+	if page.SpaceID != "65011" {
+		t.Errorf("expected spaceId '65011', got '%s'", page.SpaceID)
+	}
+	if page.SpaceKey != "" {
+		t.Errorf("expected spaceKey to be resolved separately, got '%s'", page.SpaceKey)
 	}
 	if page.Version != 5 {
 		t.Errorf("expected version 5, got %d", page.Version)
@@ -112,7 +116,8 @@ func TestPage_WriteMarkdown(t *testing.T) {
 	page := &Page{
 		ID:       "123456789",
 		Title:    "Test Page",
-		SpaceKey: "65011",
+		SpaceKey: "DOCS",
+		SpaceID:  "65011",
 		Version:  5,
 		Updated:  "2026-01-20T15:45:00.000Z",
 		Body:     "<h1>Hello World</h1><p>This is a <strong>test</strong>.</p>",
@@ -135,6 +140,9 @@ func TestPage_WriteMarkdown(t *testing.T) {
 	if result.Title != page.Title {
 		t.Errorf("expected title %s, got %s", page.Title, result.Title)
 	}
+	if result.SpaceKey != page.SpaceKey || result.SpaceID != page.SpaceID {
+		t.Errorf("expected space %s/%s, got %s/%s", page.SpaceKey, page.SpaceID, result.SpaceKey, result.SpaceID)
+	}
 
 	// Check that body is converted to markdown
 	if !strings.Contains(result.Body, "# Hello World") {
@@ -149,7 +157,8 @@ func TestPage_WriteBodyOnly(t *testing.T) {
 	page := &Page{
 		ID:       "123456789",
 		Title:    "Test Page",
-		SpaceKey: "65011",
+		SpaceKey: "DOCS",
+		SpaceID:  "65011",
 		Version:  5,
 		Updated:  "2026-01-20T15:45:00.000Z",
 		Body:     "<h1>Hello World</h1><p>This is a <strong>test</strong>.</p>",
@@ -0,0 +1,90 @@
+package confluence
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+func TestParsePageRef(t *testing.T) {
+	cfg := &config.Config{Site: "acme.atlassian.net"}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{name: "page ID", ref: "12345", want: "12345"},
+		{name: "page URL", ref: "https://acme.atlassian.net/wiki/spaces/ENG/pages/12345/Runbook+Title", want: "12345"},
+		{name: "page URL without title", ref: "https://acme.atlassian.net/wiki/spaces/ENG/pages/12345", want: "12345"},
+		{name: "page URL with fragment", ref: "https://acme.atlassian.net/wiki/spaces/ENG/pages/12345/Runbook#Restart", want: "12345"},
+		{name: "edit URL", ref: "https://acme.atlassian.net/wiki/spaces/ENG/pages/edit-v2/12345", want: "12345"},
+		{name: "viewpage URL", ref: "https://acme.atlassian.net/wiki/pages/viewpage.action?pageId=12345", want: "12345"},
+		{name: "tiny link", ref: "https://acme.atlassian.net/wiki/x/RAAB", want: "65604"},
+		{name: "tiny link with trimmed zeros", ref: "acme.atlassian.net/wiki/x/OT", want: "12345"},
+		{name: "tiny link with substituted characters", ref: "https://acme.atlassian.net/wiki/x/_-8", want: "65531"},
+		{name: "site-relative page URL", ref: "/wiki/spaces/ENG/pages/12345/Runbook", want: "12345"},
+		{name: "site-relative tiny link", ref: "/wiki/x/RAAB", want: "65604"},
+		{name: "protocol-relative URL", ref: "//other.atlassian.net/wiki/x/RAAB", wantErr: "does not match the configured site"},
+		{name: "invalid ID", ref: "abc", wantErr: "invalid page ID"},
+		{name: "other site", ref: "https://other.atlassian.net/wiki/spaces/ENG/pages/12345", wantErr: "does not match the configured site"},
+		{name: "space URL", ref: "https://acme.atlassian.net/wiki/spaces/ENG/overview", wantErr: "unrecognized Confluence page URL"},
+		{name: "viewpage without ID", ref: "https://acme.atlassian.net/wiki/pages/viewpage.action", wantErr: "unrecognized Confluence page URL"},
+		{name: "invalid tiny link", ref: "https://acme.atlassian.net/wiki/x/!!", wantErr: "invalid tiny link"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePageRef(tt.ref, cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePageRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBlogPostRef(t *testing.T) {
+	cfg := &config.Config{Site: "acme.atlassian.net"}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{name: "blog post ID", ref: "98765", want: "98765"},
+		{name: "blog URL", ref: "https://acme.atlassian.net/wiki/spaces/ENG/blog/2024/01/15/98765/Release+notes", want: "98765"},
+		{name: "short blog URL", ref: "https://acme.atlassian.net/wiki/spaces/ENG/blog/98765", want: "98765"},
+		{name: "site-relative blog URL", ref: "/wiki/spaces/ENG/blog/2024/01/15/98765/Release+notes", want: "98765"},
+		{name: "tiny link", ref: "https://acme.atlassian.net/wiki/x/RAAB", want: "65604"},
+		{name: "page URL", ref: "https://acme.atlassian.net/wiki/spaces/ENG/pages/12345/Runbook", wantErr: "unrecognized Confluence blog post URL"},
+		{name: "other site", ref: "https://other.atlassian.net/wiki/spaces/ENG/blog/2024/01/15/98765", wantErr: "does not match the configured site"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBlogPostRef(tt.ref, cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBlogPostRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// ContentProperty is a JSON value stored on a page under a key.
+type ContentProperty struct {
+	ID      string          `json:"id"`
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value"`
+	Version int             `json:"version"`
+}
+
+// Write writes the property as JSON to the given writer.
+func (p *ContentProperty) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// apiContentProperty is a content property as returned by the v2 API.
+type apiContentProperty struct {
+	ID      string          `json:"id"`
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+}
+
+func (p *apiContentProperty) toProperty() *ContentProperty {
+	return &ContentProperty{ID: p.ID, Key: p.Key, Value: p.Value, Version: p.Version.Number}
+}
+
+type apiContentPropertyListResponse struct {
+	Results []apiContentProperty `json:"results"`
+}
+
+// propertyRequest is the body of a property create or update.
+type propertyRequest struct {
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value"`
+	Version *pageVersion    `json:"version,omitempty"`
+}
+
+// propertyNotFound returns the error for a missing property.
+func propertyNotFound(pageID, key string) error {
+	return &httpclient.APIError{Response: &httpclient.ErrorResponse{
+		Error:   httpclient.ErrTypeNotFound,
+		Message: fmt.Sprintf("page %s has no property %q", pageID, key),
+	}}
+}
+
+// propertyConflict returns the error for a property that changed since the
+// version the caller expected.
+func propertyConflict(key string, current, expected int) error {
+	return &httpclient.APIError{Response: httpclient.NewConflictError(fmt.Sprintf(
+		"property %q is at version %d, expected version %d", key, current, expected))}
+}
+
+// findProperty returns a page property by key, or nil if it does not exist.
+func (c *Client) findProperty(ctx context.Context, pageID, key string) (*ContentProperty, error) {
+	if err := ValidatePageID(pageID); err != nil {
+		return nil, err
+	}
+	if err := ValidatePropertyKey(key); err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/pages/%s/properties?key=%s", c.cfg.BaseURL(), url.PathEscape(pageID), url.QueryEscape(key))
+	body, err := c.doJSON(ctx, "GET", reqURL, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiContentPropertyListResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	for _, p := range resp.Results {
+		if p.Key == key {
+			return p.toProperty(), nil
+		}
+	}
+	return nil, nil
+}
+
+// GetProperty returns a page property by key.
+func (c *Client) GetProperty(ctx context.Context, pageID, key string) (*ContentProperty, error) {
+	prop, err := c.findProperty(ctx, pageID, key)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, propertyNotFound(pageID, key)
+	}
+	return prop, nil
+}
+
+// SetProperty creates or updates a page property. An update is submitted as
+// the version after the one read, so a concurrent write is rejected by the
+// API with a conflict instead of being overwritten. If expectVersion is
+// positive, the property must still be at that version; 0 skips the check.
+func (c *Client) SetProperty(ctx context.Context, pageID, key string, value json.RawMessage, expectVersion int) (*ContentProperty, error) {
+	if !json.Valid(value) {
+		return nil, fmt.Errorf("property value must be valid JSON")
+	}
+
+	current, err := c.findProperty(ctx, pageID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var method, reqURL string
+	payload := &propertyRequest{Key: key, Value: value}
+	switch {
+	case current == nil && expectVersion > 0:
+		return nil, propertyNotFound(pageID, key)
+	case current == nil:
+		method = "POST"
+		reqURL = fmt.Sprintf("%s/wiki/api/v2/pages/%s/properties", c.cfg.BaseURL(), url.PathEscape(pageID))
+	case expectVersion > 0 && current.Version != expectVersion:
+		return nil, propertyConflict(key, current.Version, expectVersion)
+	default:
+		method = "PUT"
+		reqURL = fmt.Sprintf("%s/wiki/api/v2/pages/%s/properties/%s", c.cfg.BaseURL(), url.PathEscape(pageID), url.PathEscape(current.ID))
+		payload.Version = &pageVersion{Number: current.Version + 1}
+	}
+
+	body, err := c.doJSON(ctx, method, reqURL, payload, http.StatusOK, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiContentProperty
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp.toProperty(), nil
+}
+
+// DeleteProperty deletes a page property and returns it as it was before
+// the deletion. If expectVersion is positive, the property must still be at
+// that version.
+func (c *Client) DeleteProperty(ctx context.Context, pageID, key string, expectVersion int) (*ContentProperty, error) {
+	current, err := c.findProperty(ctx, pageID, key)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, propertyNotFound(pageID, key)
+	}
+	if expectVersion > 0 && current.Version != expectVersion {
+		return nil, propertyConflict(key, current.Version, expectVersion)
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/pages/%s/properties/%s", c.cfg.BaseURL(), url.PathEscape(pageID), url.PathEscape(current.ID))
+	if _, err := c.doJSON(ctx, "DELETE", reqURL, nil, http.StatusNoContent, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
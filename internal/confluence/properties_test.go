@@ -0,0 +1,156 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// propertyTestServer serves a single page property and records writes.
+type propertyTestServer struct {
+	*httptest.Server
+	exists  bool
+	version int
+	writes  []string
+	sent    propertyRequest
+}
+
+func newPropertyTestServer(t *testing.T, exists bool, version int) *propertyTestServer {
+	s := &propertyTestServer{exists: exists, version: version}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		prop := map[string]interface{}{
+			"id": "p1", "key": "owner", "value": map[string]string{"team": "core"},
+			"version": map[string]int{"number": s.version},
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/pages/123/properties":
+			if r.URL.Query().Get("key") != "owner" {
+				t.Errorf("expected key query, got %s", r.URL.RawQuery)
+			}
+			results := []interface{}{}
+			if s.exists {
+				results = append(results, prop)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case r.Method == "POST" && r.URL.Path == "/wiki/api/v2/pages/123/properties",
+			r.Method == "PUT" && r.URL.Path == "/wiki/api/v2/pages/123/properties/p1":
+			s.writes = append(s.writes, r.Method)
+			json.NewDecoder(r.Body).Decode(&s.sent)
+			prop["value"] = s.sent.Value
+			if s.sent.Version != nil {
+				prop["version"] = map[string]int{"number": s.sent.Version.Number}
+			} else {
+				prop["version"] = map[string]int{"number": 1}
+			}
+			json.NewEncoder(w).Encode(prop)
+		case r.Method == "DELETE" && r.URL.Path == "/wiki/api/v2/pages/123/properties/p1":
+			s.writes = append(s.writes, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
+
+func TestClient_GetProperty(t *testing.T) {
+	server := newPropertyTestServer(t, true, 4)
+	defer server.Close()
+	client := newTestClient(server.Server)
+
+	prop, err := client.GetProperty(context.Background(), "123", "owner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prop.Key != "owner" || prop.Version != 4 || string(prop.Value) != `{"team":"core"}` {
+		t.Errorf("unexpected property: %+v (%s)", prop, prop.Value)
+	}
+
+	server.exists = false
+	_, err = client.GetProperty(context.Background(), "123", "owner")
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Response.Error != httpclient.ErrTypeNotFound {
+		t.Errorf("expected not_found error, got %v", err)
+	}
+}
+
+func TestClient_SetProperty(t *testing.T) {
+	tests := []struct {
+		name          string
+		exists        bool
+		expectVersion int
+		wantWrite     string
+		wantVersion   int
+		wantErr       string
+	}{
+		{name: "create", exists: false, wantWrite: "POST", wantVersion: 1},
+		{name: "update next version", exists: true, wantWrite: "PUT", wantVersion: 4},
+		{name: "update at expected version", exists: true, expectVersion: 3, wantWrite: "PUT", wantVersion: 4},
+		{name: "stale expected version", exists: true, expectVersion: 2, wantErr: httpclient.ErrTypeConflict},
+		{name: "expected version of missing property", exists: false, expectVersion: 1, wantErr: httpclient.ErrTypeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPropertyTestServer(t, tt.exists, 3)
+			defer server.Close()
+			client := newTestClient(server.Server)
+
+			prop, err := client.SetProperty(context.Background(), "123", "owner", json.RawMessage(`{"team":"payments"}`), tt.expectVersion)
+			if tt.wantErr != "" {
+				var apiErr *httpclient.APIError
+				if !errors.As(err, &apiErr) || apiErr.Response.Error != tt.wantErr {
+					t.Fatalf("expected %s error, got %v", tt.wantErr, err)
+				}
+				if len(server.writes) != 0 {
+					t.Errorf("expected no writes, got %v", server.writes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(server.writes) != 1 || server.writes[0] != tt.wantWrite {
+				t.Errorf("expected %s, got %v", tt.wantWrite, server.writes)
+			}
+			if prop.Version != tt.wantVersion || string(prop.Value) != `{"team":"payments"}` {
+				t.Errorf("unexpected property: %+v (%s)", prop, prop.Value)
+			}
+		})
+	}
+}
+
+func TestClient_SetProperty_InvalidJSON(t *testing.T) {
+	client := NewClient(&config.Config{Site: "test.atlassian.net"}, false)
+	if _, err := client.SetProperty(context.Background(), "123", "owner", json.RawMessage(`{team}`), 0); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestClient_DeleteProperty(t *testing.T) {
+	server := newPropertyTestServer(t, true, 3)
+	defer server.Close()
+	client := newTestClient(server.Server)
+
+	if _, err := client.DeleteProperty(context.Background(), "123", "owner", 2); err == nil {
+		t.Error("expected conflict for a stale version")
+	}
+	deleted, err := client.DeleteProperty(context.Background(), "123", "owner", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted.Key != "owner" || deleted.Version != 3 {
+		t.Errorf("unexpected deleted property: %+v", deleted)
+	}
+	if len(server.writes) != 1 || server.writes[0] != "DELETE" {
+		t.Errorf("expected one DELETE, got %v", server.writes)
+	}
+}
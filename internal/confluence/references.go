@@ -0,0 +1,219 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// References holds what ToMarkdownWithReferences needs to resolve the
+// attachments, users, Jira issues and child pages a page refers to. Every
+// field is optional; unresolved references are rendered as they appear in
+// the storage format.
+type References struct {
+	// BaseURL is the site URL, such as https://example.atlassian.net.
+	BaseURL string
+	// PageID is the page that attachments without a page reference belong to.
+	PageID string
+	// Users maps account IDs to display names.
+	Users map[string]string
+	// JiraStatuses maps Jira issue keys to their current status.
+	JiraStatuses map[string]string
+	// Children lists the child pages shown by the children macro.
+	Children []*PageNode
+	// SpaceKey is the space of the page, where include macros without a
+	// space key look for pages.
+	SpaceKey string
+	// Includes maps pages referred to by include and excerpt-include macros,
+	// keyed by includeKey, to their content; nil entries could not be
+	// fetched.
+	Includes map[string]*IncludedPage
+
+	// includeStack lists the pages being rendered, outermost first, to
+	// detect include cycles.
+	includeStack []string
+}
+
+// userName returns the display name of a ri:user, or its account ID.
+func (r *References) userName(user *storageNode) string {
+	id := user.attr("ri:account-id")
+	if id == "" {
+		id = user.attr("ri:userkey")
+	}
+	if r != nil {
+		if name, ok := r.Users[id]; ok && name != "" {
+			return name
+		}
+	}
+	return id
+}
+
+// attachmentURL returns the download URL of a ri:attachment. Without a base
+// URL and page ID, or for attachments on another page, the file name is
+// returned as a relative URL.
+func (r *References) attachmentURL(att *storageNode) string {
+	filename := url.PathEscape(att.attr("ri:filename"))
+	if r == nil || r.BaseURL == "" || r.PageID == "" || att.child("ri:page") != nil || att.child("ri:blog-post") != nil {
+		return filename
+	}
+	return r.BaseURL + "/wiki/download/attachments/" + url.PathEscape(r.PageID) + "/" + filename
+}
+
+// issueURL returns the browse URL of a Jira issue, or "" without a base URL.
+func (r *References) issueURL(key string) string {
+	if r == nil || r.BaseURL == "" {
+		return ""
+	}
+	return r.BaseURL + "/browse/" + url.PathEscape(key)
+}
+
+// pageURL returns the URL of a page, or "" without a base URL.
+func (r *References) pageURL(id string) string {
+	if r == nil || r.BaseURL == "" {
+		return ""
+	}
+	return r.BaseURL + "/wiki/pages/viewpage.action?pageId=" + url.QueryEscape(id)
+}
+
+// storageRefs lists what a storage document refers to.
+type storageRefs struct {
+	accountIDs []string
+	issueKeys  []string
+	children   bool
+}
+
+// collectStorageRefs walks a parsed document for user mentions, Jira issue
+// macros and children macros.
+func collectStorageRefs(n *storageNode, refs *storageRefs, seen map[string]bool) {
+	switch n.name {
+	case "ri:user":
+		if id := n.attr("ri:account-id"); id != "" && !seen["user:"+id] {
+			seen["user:"+id] = true
+			refs.accountIDs = append(refs.accountIDs, id)
+		}
+	case "ac:structured-macro":
+		switch n.attr("ac:name") {
+		case "jira":
+			for _, p := range n.children {
+				if p.name == "ac:parameter" && p.attr("ac:name") == "key" {
+					if key := strings.TrimSpace(p.textContent()); key != "" && !seen["issue:"+key] {
+						seen["issue:"+key] = true
+						refs.issueKeys = append(refs.issueKeys, key)
+					}
+				}
+			}
+		case "children":
+			refs.children = true
+		}
+	}
+	for _, c := range n.children {
+		collectStorageRefs(c, refs, seen)
+	}
+}
+
+// apiUserListResponse is the response of the bulk user lookup.
+type apiUserListResponse struct {
+	Results []struct {
+		AccountID   string `json:"accountId"`
+		DisplayName string `json:"displayName"`
+		PublicName  string `json:"publicName"`
+	} `json:"results"`
+}
+
+// apiIssueStatusResponse is a Jira issue fetched with only its status.
+type apiIssueStatusResponse struct {
+	Fields struct {
+		Status struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// userBatchSize is the number of account IDs looked up per request.
+const userBatchSize = 100
+
+// ResolveReferences looks up the users, child pages and, with jiraStatus,
+// the Jira issue statuses that the page refers to, so that WriteMarkdown and
+// WriteBodyOnly can render them. Resolution is best-effort: references that
+// cannot be looked up are rendered unresolved.
+func (c *Client) ResolveReferences(ctx context.Context, page *Page, jiraStatus bool) {
+	refs := &References{BaseURL: c.cfg.BaseURL(), PageID: page.ID, SpaceKey: page.SpaceKey}
+	if page.refs != nil {
+		// Keep pages fetched by ResolveIncludes
+		refs.Includes = page.refs.Includes
+		refs.includeStack = page.refs.includeStack
+	}
+	page.refs = refs
+
+	root, err := parseStorage(page.Body)
+	if err != nil {
+		return
+	}
+	var found storageRefs
+	seen := make(map[string]bool)
+	collectStorageRefs(root, &found, seen)
+	children := found.children
+
+	// Users and issues in included pages are rendered too
+	for _, included := range refs.Includes {
+		if included == nil {
+			continue
+		}
+		if root, err := parseStorage(included.Body); err == nil {
+			collectStorageRefs(root, &found, seen)
+		}
+	}
+
+	if len(found.accountIDs) > 0 {
+		refs.Users = c.lookupUsers(ctx, found.accountIDs)
+	}
+
+	if jiraStatus && len(found.issueKeys) > 0 {
+		refs.JiraStatuses = make(map[string]string)
+		for _, key := range found.issueKeys {
+			body, err := c.doJSON(ctx, "GET", c.cfg.BaseURL()+"/rest/api/3/issue/"+url.PathEscape(key)+"?fields=status", nil, http.StatusOK)
+			if err != nil {
+				// Issues may be missing or restricted; leave them without a status
+				continue
+			}
+			var resp apiIssueStatusResponse
+			if json.Unmarshal(body, &resp) == nil && resp.Fields.Status.Name != "" {
+				refs.JiraStatuses[key] = resp.Fields.Status.Name
+			}
+		}
+	}
+
+	if children {
+		if children, err := c.listChildren(ctx, page.ID); err == nil {
+			refs.Children = children
+		}
+	}
+}
+
+// lookupUsers returns the display names of the given account IDs. Accounts
+// that cannot be looked up are left out.
+func (c *Client) lookupUsers(ctx context.Context, accountIDs []string) map[string]string {
+	users := make(map[string]string)
+	for start := 0; start < len(accountIDs); start += userBatchSize {
+		end := min(start+userBatchSize, len(accountIDs))
+		query := url.Values{"accountId": accountIDs[start:end]}
+		body, err := c.doJSON(ctx, "GET", c.cfg.BaseURL()+"/wiki/rest/api/user/bulk?"+query.Encode(), nil, http.StatusOK)
+		if err != nil {
+			break
+		}
+		var resp apiUserListResponse
+		if json.Unmarshal(body, &resp) != nil {
+			break
+		}
+		for _, u := range resp.Results {
+			name := u.DisplayName
+			if name == "" {
+				name = u.PublicName
+			}
+			users[u.AccountID] = name
+		}
+	}
+	return users
+}
@@ -0,0 +1,61 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_ResolveReferences(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wiki/rest/api/user/bulk":
+			if got := r.URL.Query()["accountId"]; len(got) != 1 || got[0] != "557058:abc" {
+				t.Errorf("unexpected account IDs: %v", got)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"accountId": "557058:abc", "displayName": "Ada Lovelace"}},
+			})
+		case "/rest/api/3/issue/ENG-7":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"fields": map[string]interface{}{"status": map[string]string{"name": "Done"}},
+			})
+		case "/rest/api/3/issue/SEC-1":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorMessages": ["Issue does not exist"]}`))
+		case "/wiki/api/v2/pages/123/children":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"id": "201", "title": "Child A", "status": "current"}},
+			})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	page := &Page{ID: "123", Body: `<p><ac:link><ri:user ri:account-id="557058:abc"/></ac:link> owns ` +
+		`<ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">ENG-7</ac:parameter></ac:structured-macro> and ` +
+		`<ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">SEC-1</ac:parameter></ac:structured-macro></p>` +
+		`<ac:structured-macro ac:name="children"/>`}
+	client.ResolveReferences(context.Background(), page, true)
+
+	var buf strings.Builder
+	if err := page.WriteBodyOnly(&buf); err != nil {
+		t.Fatalf("WriteBodyOnly() error = %v", err)
+	}
+	body := buf.String()
+	for _, want := range []string{"@Ada Lovelace owns", "/browse/ENG-7) (Done)", "[Child A]("} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "SEC-1) (") {
+		t.Errorf("expected no status for an inaccessible issue, got:\n%s", body)
+	}
+}
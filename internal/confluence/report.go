@@ -0,0 +1,327 @@
+package confluence
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StaleSortKeys are the fields a stale content report can be sorted by.
+// A "-" prefix sorts in descending order.
+var StaleSortKeys = []string{"modified", "title", "author", "versions", "label"}
+
+// StaleOptions selects the pages in a stale content report.
+type StaleOptions struct {
+	SpaceKeys []string
+	// Before is the cutoff: pages last modified before it are stale.
+	Before time.Time
+	// Labels restricts the report to pages that have all of these labels. A
+	// label ending in "*", such as "owner-*", matches any label with that
+	// prefix.
+	Labels []string
+	// Sort is one of StaleSortKeys, optionally prefixed with "-". Sorting by
+	// label uses the first label matching the first prefix in Labels, or the
+	// first label if there is no prefix.
+	Sort string
+}
+
+// Validate checks the options before a search is made.
+func (o StaleOptions) Validate() error {
+	if len(o.SpaceKeys) == 0 {
+		return fmt.Errorf("at least one space is required")
+	}
+	for _, key := range o.SpaceKeys {
+		if err := ValidateSpaceKey(key); err != nil {
+			return err
+		}
+	}
+	for _, label := range o.Labels {
+		prefix, _ := strings.CutSuffix(label, "*")
+		if strings.Contains(prefix, "*") {
+			return fmt.Errorf("invalid label %q: * is only allowed at the end, to match a prefix", label)
+		}
+		if err := ValidateLabel(prefix); err != nil {
+			return err
+		}
+	}
+	if o.Before.IsZero() {
+		return fmt.Errorf("a cutoff date is required")
+	}
+	if o.Sort != "" && !slices.Contains(StaleSortKeys, strings.TrimPrefix(o.Sort, "-")) {
+		return fmt.Errorf("invalid sort %q (valid: %s, with an optional - prefix for descending)", o.Sort, strings.Join(StaleSortKeys, ", "))
+	}
+	return nil
+}
+
+// CQL returns the query that finds the stale pages.
+func (o StaleOptions) CQL() string {
+	spaces := make([]string, len(o.SpaceKeys))
+	for i, key := range o.SpaceKeys {
+		spaces[i] = QuoteCQL(key)
+	}
+
+	var b strings.Builder
+	b.WriteString("type = page")
+	if len(spaces) == 1 {
+		b.WriteString(" AND space = " + spaces[0])
+	} else {
+		b.WriteString(" AND space in (" + strings.Join(spaces, ", ") + ")")
+	}
+	b.WriteString(" AND lastmodified < " + QuoteCQL(o.Before.UTC().Format("2006-01-02")))
+	for _, label := range o.Labels {
+		// CQL has no prefix match for labels, so prefixes are filtered
+		// after the search
+		if !strings.HasSuffix(label, "*") {
+			b.WriteString(" AND label = " + QuoteCQL(label))
+		}
+	}
+	b.WriteString(" ORDER BY lastmodified ASC")
+	return b.String()
+}
+
+// labelPrefixes returns the labels that end in "*", without the "*".
+func (o StaleOptions) labelPrefixes() []string {
+	var prefixes []string
+	for _, label := range o.Labels {
+		if prefix, ok := strings.CutSuffix(label, "*"); ok {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// StalePage is a page in a stale content report.
+type StalePage struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Space        string   `json:"space"`
+	LastModified string   `json:"lastModified"`
+	LastAuthor   string   `json:"lastAuthor"`
+	Versions     int      `json:"versions"`
+	Labels       []string `json:"labels"`
+	URL          string   `json:"url"`
+}
+
+// StaleReport is the CLI output format for a stale content report.
+type StaleReport struct {
+	CQL    string       `json:"cql"`
+	Before string       `json:"before"`
+	Pages  []*StalePage `json:"pages"`
+}
+
+// Write writes the report as JSON to the given writer.
+func (r *StaleReport) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the pages as CSV with a header row. Labels are separated
+// by semicolons.
+func (r *StaleReport) WriteCSV(w interface{ Write([]byte) (int, error) }) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "title", "space", "lastModified", "lastAuthor", "versions", "labels", "url"})
+	for _, p := range r.Pages {
+		cw.Write([]string{p.ID, p.Title, p.Space, p.LastModified, p.LastAuthor, strconv.Itoa(p.Versions), strings.Join(p.Labels, ";"), p.URL})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// apiContentSearchResponse is a page of /wiki/rest/api/content/search
+// results with the version, labels and space expanded.
+type apiContentSearchResponse struct {
+	Results []struct {
+		ID      string `json:"id"`
+		Title   string `json:"title"`
+		Version struct {
+			Number int    `json:"number"`
+			When   string `json:"when"`
+			By     struct {
+				DisplayName string `json:"displayName"`
+			} `json:"by"`
+		} `json:"version"`
+		Space struct {
+			Key string `json:"key"`
+		} `json:"space"`
+		Metadata struct {
+			Labels struct {
+				Results []struct {
+					Name string `json:"name"`
+				} `json:"results"`
+			} `json:"labels"`
+		} `json:"metadata"`
+		Links struct {
+			WebUI string `json:"webui"`
+		} `json:"_links"`
+	} `json:"results"`
+	Links struct {
+		Base string `json:"base"`
+	} `json:"_links"`
+}
+
+// StaleReport finds the pages in the given spaces that have not been
+// modified since the cutoff. The search runs as a CQL query, so only the
+// stale pages are fetched rather than the whole space.
+func (c *Client) StaleReport(ctx context.Context, opts StaleOptions) (*StaleReport, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := &StaleReport{
+		CQL:    opts.CQL(),
+		Before: opts.Before.UTC().Format("2006-01-02"),
+		Pages:  []*StalePage{},
+	}
+
+	prefixes := opts.labelPrefixes()
+	query := url.Values{}
+	query.Set("cql", report.CQL)
+	query.Set("limit", fmt.Sprint(searchPageSize))
+	query.Set("expand", "version,space,metadata.labels")
+
+	err := c.getSearchPaged(ctx, "/rest/api/content/search?"+query.Encode(), func(body []byte) (bool, error) {
+		var resp apiContentSearchResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return false, fmt.Errorf("failed to parse search response: %w", err)
+		}
+
+		for _, r := range resp.Results {
+			page := &StalePage{
+				ID:           r.ID,
+				Title:        r.Title,
+				Space:        r.Space.Key,
+				LastModified: r.Version.When,
+				LastAuthor:   r.Version.By.DisplayName,
+				Versions:     r.Version.Number,
+				Labels:       []string{},
+				URL:          c.webURL(resp.Links.Base, r.Links.WebUI, r.ID),
+			}
+			for _, label := range r.Metadata.Labels.Results {
+				page.Labels = append(page.Labels, label.Name)
+			}
+			if !hasLabelPrefixes(page.Labels, prefixes) {
+				continue
+			}
+			report.Pages = append(report.Pages, page)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sortPrefix string
+	if len(prefixes) > 0 {
+		sortPrefix = prefixes[0]
+	}
+	sortStalePages(report.Pages, opts.Sort, sortPrefix)
+	return report, nil
+}
+
+// hasLabelPrefixes reports whether labels has a label starting with each of
+// the prefixes.
+func hasLabelPrefixes(labels, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if !slices.ContainsFunc(labels, func(label string) bool { return strings.HasPrefix(label, prefix) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortLabel returns the alphabetically first of a page's labels that starts
+// with prefix, or "" if there is none.
+func sortLabel(page *StalePage, prefix string) string {
+	first := ""
+	for _, label := range page.Labels {
+		if strings.HasPrefix(label, prefix) && (first == "" || label < first) {
+			first = label
+		}
+	}
+	return first
+}
+
+// sortStalePages sorts pages by a sort key, keeping the search order (oldest
+// first) for ties. Sorting by label groups the pages by their first label
+// starting with labelPrefix; pages without one come last.
+func sortStalePages(pages []*StalePage, sort, labelPrefix string) {
+	key, desc := strings.CutPrefix(sort, "-")
+	if key == "label" {
+		slices.SortStableFunc(pages, func(a, b *StalePage) int {
+			la, lb := sortLabel(a, labelPrefix), sortLabel(b, labelPrefix)
+			switch {
+			case la == lb:
+				return 0
+			case la == "":
+				return 1
+			case lb == "":
+				return -1
+			case desc:
+				return cmp.Compare(lb, la)
+			}
+			return cmp.Compare(la, lb)
+		})
+		return
+	}
+	compare := func(a, b *StalePage) int {
+		switch key {
+		case "title":
+			return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case "author":
+			return cmp.Compare(strings.ToLower(a.LastAuthor), strings.ToLower(b.LastAuthor))
+		case "versions":
+			return cmp.Compare(a.Versions, b.Versions)
+		default:
+			return cmp.Compare(a.LastModified, b.LastModified)
+		}
+	}
+	slices.SortStableFunc(pages, func(a, b *StalePage) int {
+		if desc {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+}
+
+// maxAge is the longest age ParseAge accepts. Confluence Cloud has not
+// existed for that long, and longer ages would overflow a time.Duration.
+const maxAge = 100 * 365 * 24 * time.Hour
+
+// ParseAge parses an age such as "365d", "12w" or "1y", or a Go duration
+// such as "72h". A year is 365 days. Ages over 100 years are rejected.
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+		"y": 365 * 24 * time.Hour,
+	}
+	if len(s) > 1 {
+		if unit, ok := units[s[len(s)-1:]]; ok {
+			n, err := strconv.Atoi(s[:len(s)-1])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid age %q (expected a positive number followed by d, w or y)", s)
+			}
+			// Checked before multiplying, which could overflow
+			if n > int(maxAge/unit) {
+				return 0, fmt.Errorf("age %q is too long (at most 100y)", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q (expected a positive number followed by d, w or y)", s)
+	}
+	if d > maxAge {
+		return 0, fmt.Errorf("age %q is too long (at most 100y)", s)
+	}
+	return d, nil
+}
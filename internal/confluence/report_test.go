@@ -0,0 +1,202 @@
+package confluence
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{age: "365d", want: 365 * 24 * time.Hour},
+		{age: "2w", want: 14 * 24 * time.Hour},
+		{age: "1y", want: 365 * 24 * time.Hour},
+		{age: "72h", want: 72 * time.Hour},
+		{age: "100y", want: 100 * 365 * 24 * time.Hour},
+		{age: "101y", wantErr: true},
+		{age: "300y", wantErr: true},
+		{age: "99999999999999d", wantErr: true},
+		{age: "900000h", wantErr: true},
+		{age: "0d", wantErr: true},
+		{age: "-5d", wantErr: true},
+		{age: "d", wantErr: true},
+		{age: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.age, func(t *testing.T) {
+			got, err := ParseAge(tt.age)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAge(%q) error = %v, wantErr %v", tt.age, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAge(%q) = %v, want %v", tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaleOptions_CQL(t *testing.T) {
+	before := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts StaleOptions
+		want string
+	}{
+		{
+			name: "one space",
+			opts: StaleOptions{SpaceKeys: []string{"ENG"}, Before: before},
+			want: `type = page AND space = "ENG" AND lastmodified < "2025-10-18" ORDER BY lastmodified ASC`,
+		},
+		{
+			name: "spaces and labels",
+			opts: StaleOptions{SpaceKeys: []string{"ENG", "OPS"}, Before: before, Labels: []string{"runbook", "owner-payments"}},
+			want: `type = page AND space in ("ENG", "OPS") AND lastmodified < "2025-10-18" AND label = "runbook" AND label = "owner-payments" ORDER BY lastmodified ASC`,
+		},
+		{
+			name: "label prefix",
+			opts: StaleOptions{SpaceKeys: []string{"ENG"}, Before: before, Labels: []string{"owner-*", "runbook"}},
+			want: `type = page AND space = "ENG" AND lastmodified < "2025-10-18" AND label = "runbook" ORDER BY lastmodified ASC`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.CQL(); got != tt.want {
+				t.Errorf("CQL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaleOptions_Validate(t *testing.T) {
+	before := time.Now()
+	tests := []struct {
+		name string
+		opts StaleOptions
+	}{
+		{"no space", StaleOptions{Before: before}},
+		{"invalid space", StaleOptions{SpaceKeys: []string{"not a key"}, Before: before}},
+		{"invalid label", StaleOptions{SpaceKeys: []string{"ENG"}, Before: before, Labels: []string{"two words"}}},
+		{"wildcard inside label", StaleOptions{SpaceKeys: []string{"ENG"}, Before: before, Labels: []string{"owner-*-team"}}},
+		{"wildcard only", StaleOptions{SpaceKeys: []string{"ENG"}, Before: before, Labels: []string{"*"}}},
+		{"no cutoff", StaleOptions{SpaceKeys: []string{"ENG"}}},
+		{"unknown sort", StaleOptions{SpaceKeys: []string{"ENG"}, Before: before, Sort: "size"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func newStaleTestServer(t *testing.T) *httptest.Server {
+	page := func(id, title, when, author string, versions int, labels ...string) string {
+		var names []string
+		for _, l := range labels {
+			names = append(names, fmt.Sprintf(`{"name": %q}`, l))
+		}
+		return fmt.Sprintf(`{"id": %q, "title": %q, "space": {"key": "ENG"},
+			"version": {"number": %d, "when": %q, "by": {"displayName": %q}},
+			"metadata": {"labels": {"results": [%s]}},
+			"_links": {"webui": "/spaces/ENG/pages/%s"}}`, id, title, versions, when, author, strings.Join(names, ","), id)
+	}
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wiki/rest/api/content/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if got := r.URL.Query().Get("expand"); got != "version,space,metadata.labels" {
+			t.Errorf("unexpected expand: %s", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprintf(w, `{"results": [%s, %s], "_links": {"base": "https://example.atlassian.net/wiki", "next": "/rest/api/content/search?cursor=2&expand=version,space,metadata.labels"}}`,
+				page("1", "Zeta runbook", "2023-01-05T10:00:00.000Z", "Ada", 12, "runbook", "owner-payments"),
+				page("2", "alpha notes", "2024-03-01T09:00:00.000Z", "Bob", 3))
+			return
+		}
+		fmt.Fprintf(w, `{"results": [%s], "_links": {"base": "https://example.atlassian.net/wiki"}}`,
+			page("3", "Design", "2024-06-30T08:00:00.000Z", "ada", 7, "owner-core"))
+	}))
+}
+
+func TestClient_StaleReport(t *testing.T) {
+	server := newStaleTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		sort   string
+		labels []string
+		want   string
+	}{
+		{sort: "", want: "1,2,3"},
+		{sort: "-modified", want: "3,2,1"},
+		{sort: "title", want: "2,3,1"},
+		{sort: "-versions", want: "1,3,2"},
+		{sort: "author", want: "1,3,2"},
+		{sort: "label", want: "3,1,2"},
+		{sort: "-label", want: "1,3,2"},
+		{sort: "", labels: []string{"owner-*"}, want: "1,3"},
+		{sort: "label", labels: []string{"owner-*"}, want: "3,1"},
+		{sort: "label", labels: []string{"run*"}, want: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort+strings.Join(tt.labels, ","), func(t *testing.T) {
+			report, err := newTestClient(server).StaleReport(context.Background(), StaleOptions{
+				SpaceKeys: []string{"ENG"},
+				Before:    time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC),
+				Labels:    tt.labels,
+				Sort:      tt.sort,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, p := range report.Pages {
+				ids = append(ids, p.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	report, err := newTestClient(server).StaleReport(context.Background(), StaleOptions{
+		SpaceKeys: []string{"ENG"},
+		Before:    time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := report.Pages[0]
+	if first.LastAuthor != "Ada" || first.Versions != 12 || first.Space != "ENG" ||
+		first.LastModified != "2023-01-05T10:00:00.000Z" || strings.Join(first.Labels, ",") != "runbook,owner-payments" ||
+		first.URL != "https://example.atlassian.net/wiki/spaces/ENG/pages/1" {
+		t.Errorf("unexpected page: %+v", first)
+	}
+	if report.Before != "2025-10-18" {
+		t.Errorf("unexpected cutoff: %s", report.Before)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[0] != "id,title,space,lastModified,lastAuthor,versions,labels,url" {
+		t.Fatalf("unexpected CSV:\n%s", buf.String())
+	}
+	if lines[1] != "1,Zeta runbook,ENG,2023-01-05T10:00:00.000Z,Ada,12,runbook;owner-payments,https://example.atlassian.net/wiki/spaces/ENG/pages/1" {
+		t.Errorf("unexpected CSV row: %s", lines[1])
+	}
+}
@@ -0,0 +1,178 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// searchPageSize is the number of results requested per search page.
+const searchPageSize = 50
+
+// SearchResult is a single Confluence search hit returned by atl-cli.
+type SearchResult struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Type         string `json:"type"`
+	Space        string `json:"space"`
+	LastModified string `json:"lastModified"`
+	URL          string `json:"url"`
+	Excerpt      string `json:"excerpt"`
+}
+
+// SearchResults is the CLI output format for a search.
+type SearchResults struct {
+	CQL     string         `json:"cql"`
+	Results []SearchResult `json:"results"`
+}
+
+// Write writes the search results as JSON to the given writer.
+func (s *SearchResults) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// apiSearchResponse represents a page of /wiki/rest/api/search results.
+type apiSearchResponse struct {
+	Results []struct {
+		Content *struct {
+			ID    string `json:"id"`
+			Type  string `json:"type"`
+			Title string `json:"title"`
+			Space *struct {
+				Key string `json:"key"`
+			} `json:"space"`
+		} `json:"content"`
+		Title                 string `json:"title"`
+		Excerpt               string `json:"excerpt"`
+		URL                   string `json:"url"`
+		EntityType            string `json:"entityType"`
+		LastModified          string `json:"lastModified"`
+		ResultGlobalContainer *struct {
+			DisplayURL string `json:"displayUrl"`
+		} `json:"resultGlobalContainer"`
+	} `json:"results"`
+}
+
+// Search runs a CQL query and returns up to limit results, following result
+// cursors until the results are exhausted. A limit of 0 returns all results.
+func (c *Client) Search(ctx context.Context, cql string, limit int) ([]SearchResult, error) {
+	if strings.TrimSpace(cql) == "" {
+		return nil, fmt.Errorf("CQL query cannot be empty")
+	}
+
+	base := c.cfg.BaseURL() + "/wiki"
+	query := url.Values{}
+	query.Set("cql", cql)
+	query.Set("limit", fmt.Sprint(searchPageSize))
+	query.Set("expand", "content.space")
+
+	results := []SearchResult{}
+	err := c.getSearchPaged(ctx, "/rest/api/search?"+query.Encode(), func(body []byte) (bool, error) {
+		var resp apiSearchResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return false, fmt.Errorf("failed to parse search response: %w", err)
+		}
+
+		for _, r := range resp.Results {
+			result := SearchResult{
+				Title:        highlightToText(r.Title),
+				Type:         r.EntityType,
+				LastModified: r.LastModified,
+				Excerpt:      highlightToText(r.Excerpt),
+			}
+			if r.URL != "" {
+				result.URL = base + r.URL
+			}
+			if r.Content != nil {
+				result.ID = r.Content.ID
+				result.Type = r.Content.Type
+				if r.Content.Title != "" {
+					result.Title = r.Content.Title
+				}
+				if r.Content.Space != nil {
+					result.Space = r.Content.Space.Key
+				}
+			}
+			if result.Space == "" && r.ResultGlobalContainer != nil {
+				result.Space = strings.TrimPrefix(r.ResultGlobalContainer.DisplayURL, "/spaces/")
+			}
+
+			results = append(results, result)
+			if limit > 0 && len(results) >= limit {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// getSearchPaged fetches v1 search results starting at path (relative to
+// the wiki base, like the API's cursor links), passing each response body to
+// page and following the _links.next cursor until page reports that it is
+// done or the results run out.
+func (c *Client) getSearchPaged(ctx context.Context, path string, page func(body []byte) (bool, error)) error {
+	base := c.cfg.BaseURL() + "/wiki"
+	for next := path; next != ""; {
+		// Cursor links are relative to the wiki base; resolving them against
+		// the configured site keeps requests on the expected host.
+		body, err := c.doJSON(ctx, "GET", base+next, nil, http.StatusOK)
+		if err != nil {
+			return err
+		}
+		done, err := page(body)
+		if err != nil || done {
+			return err
+		}
+
+		var resp struct {
+			Results []json.RawMessage `json:"results"`
+			Links   struct {
+				Next string `json:"next"`
+			} `json:"_links"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse search response: %w", err)
+		}
+		if len(resp.Results) == 0 {
+			break
+		}
+		next = resp.Links.Next
+	}
+	return nil
+}
+
+var (
+	highlightMarkerRe = regexp.MustCompile(`@@@(?:end)?hl@@@`)
+	htmlTagRe         = regexp.MustCompile(`<[^>]*>`)
+)
+
+// highlightToText converts highlighted search HTML to plain text.
+func highlightToText(s string) string {
+	s = highlightMarkerRe.ReplaceAllString(s, "")
+	s = htmlTagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// TextQuery builds a CQL full-text query for text, escaping it so that
+// quotes and backslashes cannot break out of the string literal.
+func TextQuery(text string) string {
+	return `text ~ ` + QuoteCQL(text)
+}
+
+// QuoteCQL returns s as a double-quoted CQL string literal.
+func QuoteCQL(s string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+	return `"` + escaped + `"`
+}
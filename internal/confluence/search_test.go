@@ -0,0 +1,125 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+func newSearchTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wiki/rest/api/search" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("cql") != "space = ENG" {
+			t.Errorf("unexpected cql: %q", r.URL.Query().Get("cql"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]interface{}{
+					{
+						"content": map[string]interface{}{
+							"id": "1", "type": "page", "title": "Disk runbook",
+							"space": map[string]string{"key": "ENG"},
+						},
+						"title":        "@@@hl@@@Disk@@@endhl@@@ runbook",
+						"excerpt":      "When the @@@hl@@@disk@@@endhl@@@ is full &amp; <b>alerts</b>\n fire",
+						"url":          "/spaces/ENG/pages/1/Disk+runbook",
+						"entityType":   "content",
+						"lastModified": "2026-01-02T03:04:05.000Z",
+					},
+				},
+				"_links": map[string]string{
+					"next": "/rest/api/search?cql=space+%3D+ENG&cursor=abc",
+				},
+			})
+		case "abc":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]interface{}{
+					{
+						"title":                 "Engineering",
+						"entityType":            "space",
+						"url":                   "/spaces/ENG",
+						"resultGlobalContainer": map[string]string{"displayUrl": "/spaces/ENG"},
+					},
+				},
+				"_links": map[string]string{},
+			})
+		default:
+			t.Errorf("unexpected cursor: %s", r.URL.Query().Get("cursor"))
+		}
+	}))
+}
+
+func TestClient_Search_FollowsCursors(t *testing.T) {
+	server := newSearchTestServer(t)
+	defer server.Close()
+
+	results, err := newTestClient(server).Search(context.Background(), "space = ENG", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	first := results[0]
+	if first.ID != "1" || first.Type != "page" || first.Space != "ENG" || first.Title != "Disk runbook" {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if first.Excerpt != "When the disk is full & alerts fire" {
+		t.Errorf("unexpected excerpt: %q", first.Excerpt)
+	}
+	if first.URL != server.URL+"/wiki/spaces/ENG/pages/1/Disk+runbook" {
+		t.Errorf("unexpected URL: %s", first.URL)
+	}
+
+	if results[1].Type != "space" || results[1].Space != "ENG" {
+		t.Errorf("unexpected second result: %+v", results[1])
+	}
+}
+
+func TestClient_Search_Limit(t *testing.T) {
+	server := newSearchTestServer(t)
+	defer server.Close()
+
+	results, err := newTestClient(server).Search(context.Background(), "space = ENG", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(results))
+	}
+}
+
+func TestClient_Search_EmptyQuery(t *testing.T) {
+	client := NewClient(&config.Config{Site: "test.atlassian.net"}, false)
+	if _, err := client.Search(context.Background(), "  ", 0); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestTextQuery(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"disk full", `text ~ "disk full"`},
+		{`say "hi"`, `text ~ "say \"hi\""`},
+		{`back\slash`, `text ~ "back\\slash"`},
+		{`\" OR space = X`, `text ~ "\\\" OR space = X"`},
+	}
+
+	for _, tt := range tests {
+		if got := TextQuery(tt.input); got != tt.expected {
+			t.Errorf("TextQuery(%q) = %s, expected %s", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// Space represents a Confluence space returned by atl-cli.
+type Space struct {
+	ID          string `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	HomepageID  string `json:"homepageId"`
+	Description string `json:"description"`
+}
+
+// Write writes the space as JSON to the given writer.
+func (s *Space) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// SpaceList is a list of spaces written as a JSON array.
+type SpaceList []*Space
+
+// Write writes the spaces as a JSON array to the given writer.
+func (l SpaceList) Write(w interface{ Write([]byte) (int, error) }) error {
+	if l == nil {
+		l = SpaceList{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// apiSpace represents a space in the Confluence API v2.
+type apiSpace struct {
+	ID          string `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	HomepageID  string `json:"homepageId"`
+	Description *struct {
+		Plain *struct {
+			Value string `json:"value"`
+		} `json:"plain"`
+	} `json:"description"`
+}
+
+func (s *apiSpace) toSpace() *Space {
+	space := &Space{
+		ID:         s.ID,
+		Key:        s.Key,
+		Name:       s.Name,
+		Type:       s.Type,
+		HomepageID: s.HomepageID,
+	}
+	if s.Description != nil && s.Description.Plain != nil {
+		space.Description = s.Description.Plain.Value
+	}
+	return space
+}
+
+// apiSpaceListResponse represents a page of v2 spaces.
+type apiSpaceListResponse struct {
+	Results []apiSpace `json:"results"`
+}
+
+// cachedSpace returns a cached space matching the ID or key.
+func (c *Client) cachedSpace(id, key string) *Space {
+	c.spacesMu.Lock()
+	defer c.spacesMu.Unlock()
+
+	if id != "" {
+		return c.spaces[id]
+	}
+	for _, space := range c.spaces {
+		if space.Key == key {
+			return space
+		}
+	}
+	return nil
+}
+
+// cacheSpace stores a space for later lookups by ID or key.
+func (c *Client) cacheSpace(space *Space) {
+	c.spacesMu.Lock()
+	defer c.spacesMu.Unlock()
+
+	if c.spaces == nil {
+		c.spaces = make(map[string]*Space)
+	}
+	c.spaces[space.ID] = space
+}
+
+// GetSpace retrieves a space by its key.
+func (c *Client) GetSpace(ctx context.Context, key string) (*Space, error) {
+	if err := ValidateSpaceKey(key); err != nil {
+		return nil, err
+	}
+	if space := c.cachedSpace("", key); space != nil {
+		return space, nil
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/spaces?keys=%s&description-format=plain",
+		c.cfg.BaseURL(), url.QueryEscape(key))
+	body, err := c.doJSON(ctx, "GET", reqURL, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiSpaceListResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	for _, s := range resp.Results {
+		if s.Key == key {
+			space := s.toSpace()
+			c.cacheSpace(space)
+			return space, nil
+		}
+	}
+	return nil, &httpclient.APIError{Response: &httpclient.ErrorResponse{
+		Error:   httpclient.ErrTypeNotFound,
+		Message: fmt.Sprintf("space %q not found", key),
+	}}
+}
+
+// GetSpaceByID retrieves a space by its numeric ID.
+func (c *Client) GetSpaceByID(ctx context.Context, id string) (*Space, error) {
+	if space := c.cachedSpace(id, ""); space != nil {
+		return space, nil
+	}
+
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/spaces/%s?description-format=plain",
+		c.cfg.BaseURL(), url.PathEscape(id))
+	body, err := c.doJSON(ctx, "GET", reqURL, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiSpace
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	space := resp.toSpace()
+	c.cacheSpace(space)
+	return space, nil
+}
+
+// ListSpaces retrieves all spaces visible to the user, optionally filtered
+// by type ("global" or "personal").
+func (c *Client) ListSpaces(ctx context.Context, spaceType string) (SpaceList, error) {
+	query := url.Values{}
+	query.Set("limit", "250")
+	query.Set("description-format", "plain")
+	if spaceType != "" {
+		query.Set("type", spaceType)
+	}
+
+	spaces := SpaceList{}
+	err := c.getPaged(ctx, "/wiki/api/v2/spaces?"+query.Encode(), func(body []byte) error {
+		var resp apiSpaceListResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, s := range resp.Results {
+			space := s.toSpace()
+			c.cacheSpace(space)
+			spaces = append(spaces, space)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spaces, nil
+}
+
+// lookupSpaceID resolves a space key to the numeric ID used by the v2 API.
+func (c *Client) lookupSpaceID(ctx context.Context, key string) (string, error) {
+	space, err := c.GetSpace(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return space.ID, nil
+}
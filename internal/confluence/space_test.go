@@ -0,0 +1,115 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+func TestClient_GetSpace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wiki/api/v2/spaces" || r.URL.Query().Get("keys") != "DOCS" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results": [{
+			"id": "65011", "key": "DOCS", "name": "Documentation", "type": "global",
+			"homepageId": "98304",
+			"description": {"plain": {"value": "Team docs", "representation": "plain"}}
+		}]}`))
+	}))
+	defer server.Close()
+
+	space, err := newTestClient(server).GetSpace(context.Background(), "DOCS")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Space{ID: "65011", Key: "DOCS", Name: "Documentation", Type: "global", HomepageID: "98304", Description: "Team docs"}
+	if *space != want {
+		t.Errorf("got %+v, want %+v", *space, want)
+	}
+}
+
+func TestClient_GetSpace_NotFound(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).GetSpace(context.Background(), "NOPE")
+
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Response.Error != httpclient.ErrTypeNotFound {
+		t.Fatalf("expected not_found error, got %v", err)
+	}
+}
+
+func TestClient_GetSpaceByID_Cached(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/wiki/api/v2/spaces/65011" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "65011", "key": "DOCS", "name": "Documentation"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	for i := 0; i < 3; i++ {
+		space, err := client.GetSpaceByID(context.Background(), "65011")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if space.Key != "DOCS" {
+			t.Errorf("expected key DOCS, got %s", space.Key)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	// Lookups by key are served from the same cache
+	if _, err := client.GetSpace(context.Background(), "DOCS"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected key lookup to be cached, got %d requests", requests)
+	}
+}
+
+func TestClient_ListSpaces_Paginates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			if r.URL.Query().Get("type") != "global" {
+				t.Errorf("expected type=global, got %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"id": "1", "key": "ENG"}},
+				"_links":  map[string]string{"next": "/wiki/api/v2/spaces?cursor=abc"},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]string{{"id": "2", "key": "OPS"}},
+		})
+	}))
+	defer server.Close()
+
+	spaces, err := newTestClient(server).ListSpaces(context.Background(), "global")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spaces) != 2 || spaces[0].Key != "ENG" || spaces[1].Key != "OPS" {
+		t.Errorf("unexpected spaces: %+v", spaces)
+	}
+}
@@ -0,0 +1,305 @@
+package confluence
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/jira"
+)
+
+// admonitionMacros maps admonition labels to Confluence macro names. Both
+// the "**Info:**" form produced by ToMarkdown and GitHub alert markers
+// ("[!NOTE]") are recognised.
+var admonitionMacros = map[string]string{
+	"info":      "info",
+	"note":      "note",
+	"tip":       "tip",
+	"warning":   "warning",
+	"important": "note",
+	"caution":   "warning",
+}
+
+var (
+	admonitionLabelRe = regexp.MustCompile(`(?i)^\*\*(info|note|tip|warning):\*\*\s*`)
+	admonitionAlertRe = regexp.MustCompile(`(?i)^\[!(info|note|tip|warning|important|caution)\]\s*`)
+	summaryRe         = regexp.MustCompile(`(?i)<summary>(.*?)</summary>`)
+	detailsOpenRe     = regexp.MustCompile(`(?i)<details`)
+	detailsCloseRe    = regexp.MustCompile(`(?i)</details>`)
+	codeFenceRe       = regexp.MustCompile("^```(\\w*)\\s*$")
+)
+
+// MarkdownToStorage converts Markdown to Confluence storage format (XHTML).
+// Markdown is parsed with the same parser used for Jira descriptions, and
+// Confluence constructs are produced for the forms ToMarkdown emits:
+// fenced code blocks become code macros carrying their language,
+// admonition blockquotes become info/warning/note/tip macros, and
+// <details> sections become expand macros.
+func MarkdownToStorage(markdown string) string {
+	return markdownToStorage(markdown, func(string) {})
+}
+
+// MarkdownWarnings reports Markdown constructs that MarkdownToStorage cannot
+// represent faithfully.
+func MarkdownWarnings(markdown string) []string {
+	var warnings []string
+	seen := make(map[string]bool)
+	markdownToStorage(markdown, func(msg string) {
+		if !seen[msg] {
+			seen[msg] = true
+			warnings = append(warnings, msg)
+		}
+	})
+	return warnings
+}
+
+// markdownToStorage converts markdown, passing lossy conversion notes to warn.
+func markdownToStorage(markdown string, warn func(string)) string {
+	var b strings.Builder
+	for _, block := range splitMarkdownBlocks(markdown) {
+		body := strings.Join(block.lines, "\n")
+		switch block.kind {
+		case "quote":
+			b.WriteString(renderQuote(block.lines, warn))
+		case "details":
+			b.WriteString(`<ac:structured-macro ac:name="expand">`)
+			if block.title != "" {
+				b.WriteString(`<ac:parameter ac:name="title">` + html.EscapeString(block.title) + `</ac:parameter>`)
+			}
+			b.WriteString("<ac:rich-text-body>" + markdownToStorage(body, warn) + "</ac:rich-text-body>")
+			b.WriteString("</ac:structured-macro>")
+		default:
+			for _, msg := range jira.MarkdownWarnings(body) {
+				warn(msg)
+			}
+			b.WriteString(renderStorage(jira.ParseMarkdownToADFNodes(body)))
+		}
+	}
+	return b.String()
+}
+
+// renderQuote renders blockquote lines (with the ">" markers removed) as an
+// admonition macro if the first line carries a label, or as a blockquote.
+func renderQuote(lines []string, warn func(string)) string {
+	macro, lines := splitAdmonition(lines)
+	body := markdownToStorage(strings.Join(lines, "\n"), warn)
+	if macro == "" {
+		return "<blockquote>" + body + "</blockquote>"
+	}
+	return `<ac:structured-macro ac:name="` + macro + `"><ac:rich-text-body>` + body + "</ac:rich-text-body></ac:structured-macro>"
+}
+
+// splitAdmonition returns the admonition macro named by a label on the
+// first line of a blockquote, if any, and the lines without the label.
+func splitAdmonition(lines []string) (string, []string) {
+	if len(lines) == 0 {
+		return "", lines
+	}
+	first := lines[0]
+	macro := ""
+	if m := admonitionLabelRe.FindStringSubmatch(first); m != nil {
+		macro = admonitionMacros[strings.ToLower(m[1])]
+		first = first[len(m[0]):]
+	} else if m := admonitionAlertRe.FindStringSubmatch(first); m != nil {
+		macro = admonitionMacros[strings.ToLower(m[1])]
+		first = first[len(m[0]):]
+	}
+	return macro, append([]string{first}, lines[1:]...)
+}
+
+// markdownBlock is a run of Markdown lines converted as one unit.
+type markdownBlock struct {
+	kind  string // "markdown", "quote" or "details"
+	lines []string
+	title string // summary of a details block
+}
+
+// splitMarkdownBlocks separates blockquotes and <details> sections from the
+// rest of the Markdown, which the ADF parser handles. Fenced code blocks are
+// never split.
+func splitMarkdownBlocks(markdown string) []markdownBlock {
+	var blocks []markdownBlock
+	current := markdownBlock{kind: "markdown"}
+	flush := func() {
+		if len(current.lines) > 0 {
+			blocks = append(blocks, current)
+		}
+		current = markdownBlock{kind: "markdown"}
+	}
+
+	lines := strings.Split(markdown, "\n")
+	inCodeBlock := false
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+
+		if codeFenceRe.MatchString(trimmed) {
+			inCodeBlock = !inCodeBlock || trimmed != "```"
+		}
+		if inCodeBlock || trimmed == "```" {
+			current.lines = append(current.lines, lines[i])
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			quote := markdownBlock{kind: "quote"}
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(t, ">") {
+					break
+				}
+				t = strings.TrimPrefix(t, ">")
+				quote.lines = append(quote.lines, strings.TrimPrefix(t, " "))
+			}
+			i--
+			blocks = append(blocks, quote)
+
+		case strings.HasPrefix(strings.ToLower(trimmed), "<details"):
+			flush()
+			details, end := readDetails(lines, i)
+			blocks = append(blocks, details)
+			i = end
+
+		default:
+			current.lines = append(current.lines, lines[i])
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// readDetails reads a <details> section starting at lines[start] and returns
+// it with the index of its closing line. Nested sections stay in the body.
+func readDetails(lines []string, start int) (markdownBlock, int) {
+	block := markdownBlock{kind: "details"}
+	depth := 0
+
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if i == start {
+			// Drop the opening tag itself
+			_, line, _ = strings.Cut(line, ">")
+			depth = 1
+		} else {
+			depth += len(detailsOpenRe.FindAllStringIndex(line, -1))
+		}
+
+		// A summary before any body content becomes the expand title
+		if block.title == "" && strings.TrimSpace(strings.Join(block.lines, "")) == "" {
+			if m := summaryRe.FindStringSubmatchIndex(line); m != nil {
+				block.title = html.UnescapeString(strings.TrimSpace(line[m[2]:m[3]]))
+				line = line[m[1]:]
+			}
+		}
+
+		// Tags are matched in the line itself: lowercasing can change the
+		// length of non-ASCII text, so offsets into a lowered copy are wrong.
+		closing := detailsCloseRe.FindAllStringIndex(line, -1)
+		depth -= len(closing)
+		if depth <= 0 {
+			idx := closing[len(closing)-1][0]
+			if before := line[:idx]; strings.TrimSpace(before) != "" {
+				block.lines = append(block.lines, before)
+			}
+			return block, i
+		}
+		block.lines = append(block.lines, line)
+	}
+
+	return block, len(lines) - 1
+}
+
+// renderStorage renders block-level ADF nodes as storage format.
+func renderStorage(nodes []jira.ADFNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		writeStorageBlock(&b, node)
+	}
+	return b.String()
+}
+
+func writeStorageBlock(b *strings.Builder, node jira.ADFNode) {
+	switch node.Type {
+	case "paragraph":
+		b.WriteString("<p>" + renderStorageInline(node.Content) + "</p>")
+	case "heading":
+		level := "1"
+		if n, ok := node.Attrs["level"].(int); ok && n >= 1 && n <= 6 {
+			level = strconv.Itoa(n)
+		}
+		b.WriteString("<h" + level + ">" + renderStorageInline(node.Content) + "</h" + level + ">")
+	case "bulletList", "orderedList":
+		tag := "ul"
+		if node.Type == "orderedList" {
+			tag = "ol"
+		}
+		b.WriteString("<" + tag + ">")
+		for _, item := range node.Content {
+			b.WriteString("<li>")
+			// A single paragraph is written inline to keep list markup compact
+			if len(item.Content) == 1 && item.Content[0].Type == "paragraph" {
+				b.WriteString(renderStorageInline(item.Content[0].Content))
+			} else {
+				b.WriteString(renderStorage(item.Content))
+			}
+			b.WriteString("</li>")
+		}
+		b.WriteString("</" + tag + ">")
+	case "codeBlock":
+		lang, _ := node.Attrs["language"].(string)
+		var code strings.Builder
+		for _, child := range node.Content {
+			code.WriteString(child.Text)
+		}
+		b.WriteString(codeMacro(lang, code.String()))
+	}
+}
+
+// renderStorageInline renders inline ADF text nodes with their marks.
+func renderStorageInline(nodes []jira.ADFNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		text := html.EscapeString(node.Text)
+		var href string
+		for _, mark := range node.Marks {
+			switch mark.Type {
+			case "code":
+				text = "<code>" + text + "</code>"
+			case "strong":
+				text = "<strong>" + text + "</strong>"
+			case "em":
+				text = "<em>" + text + "</em>"
+			case "strike":
+				text = "<s>" + text + "</s>"
+			case "link":
+				href, _ = mark.Attrs["href"].(string)
+			}
+		}
+		if href != "" {
+			text = `<a href="` + html.EscapeString(href) + `">` + text + "</a>"
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// codeMacro builds a Confluence code macro for the given language and code.
+func codeMacro(language, code string) string {
+	var b strings.Builder
+	b.WriteString(`<ac:structured-macro ac:name="code">`)
+	if language != "" {
+		b.WriteString(`<ac:parameter ac:name="language">` + html.EscapeString(language) + `</ac:parameter>`)
+	}
+	b.WriteString("<ac:plain-text-body>" + cdata(code) + "</ac:plain-text-body>")
+	b.WriteString("</ac:structured-macro>")
+	return b.String()
+}
+
+// cdata wraps text in a CDATA section, splitting any "]]>" sequences so
+// they cannot terminate the section early.
+func cdata(text string) string {
+	return "<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
@@ -0,0 +1,187 @@
+package confluence
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownToStorage(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "heading and paragraph",
+			input:    "## Title\n\nHello world",
+			expected: "<h2>Title</h2><p>Hello world</p>",
+		},
+		{
+			name:     "inline marks",
+			input:    "**bold** *italic* `code` [link](https://example.com?a=1&b=2)",
+			expected: `<p><strong>bold</strong> <em>italic</em> <code>code</code> <a href="https://example.com?a=1&amp;b=2">link</a></p>`,
+		},
+		{
+			name:     "escaping",
+			input:    "a < b & c",
+			expected: "<p>a &lt; b &amp; c</p>",
+		},
+		{
+			name:     "lists",
+			input:    "- a\n- b\n\n1. one",
+			expected: "<ul><li>a</li><li>b</li></ul><ol><li>one</li></ol>",
+		},
+		{
+			name:     "code block with language",
+			input:    "```go\nx := 1\n```",
+			expected: `<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">go</ac:parameter><ac:plain-text-body><![CDATA[x := 1]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "code block containing CDATA terminator",
+			input:    "```\na]]>b\n```",
+			expected: `<ac:structured-macro ac:name="code"><ac:plain-text-body><![CDATA[a]]]]><![CDATA[>b]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MarkdownToStorage(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMarkdownToStorage_RoundTrip(t *testing.T) {
+	input := "# Title\n\nSome **bold** text.\n\n- a\n- b\n\n```python\nprint('hi')\n```"
+
+	markdown, err := ToMarkdown(MarkdownToStorage(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if markdown != input {
+		t.Errorf("round trip mismatch:\nexpected:\n%s\ngot:\n%s", input, markdown)
+	}
+}
+
+func TestMarkdownToStorage_Admonitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "label form",
+			input:    "> **Info:** Read *this*",
+			expected: `<ac:structured-macro ac:name="info"><ac:rich-text-body><p>Read <em>this</em></p></ac:rich-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "alert form",
+			input:    "> [!TIP]\n> Use the cache",
+			expected: `<ac:structured-macro ac:name="tip"><ac:rich-text-body><p>Use the cache</p></ac:rich-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "caution maps to warning",
+			input:    "> [!CAUTION] Destructive",
+			expected: `<ac:structured-macro ac:name="warning"><ac:rich-text-body><p>Destructive</p></ac:rich-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "plain blockquote",
+			input:    "> Quoted\n>\n> - item",
+			expected: "<blockquote><p>Quoted</p><ul><li>item</li></ul></blockquote>",
+		},
+		{
+			name:     "quote marker inside code block",
+			input:    "```\n> not a quote\n```",
+			expected: `<ac:structured-macro ac:name="code"><ac:plain-text-body><![CDATA[> not a quote]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MarkdownToStorage(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMarkdownToStorage_Details(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "multi-line",
+			input:    "<details>\n<summary>More &amp; less</summary>\n\nHidden **text**\n</details>\n\nAfter",
+			expected: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">More &amp; less</ac:parameter><ac:rich-text-body><p>Hidden <strong>text</strong></p></ac:rich-text-body></ac:structured-macro><p>After</p>`,
+		},
+		{
+			name:     "single line",
+			input:    "<details><summary>T</summary>Body</details>",
+			expected: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">T</ac:parameter><ac:rich-text-body><p>Body</p></ac:rich-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "nested",
+			input:    "<details>\n<summary>Outer</summary>\n\n<details><summary>Inner</summary>deep</details>\n</details>",
+			expected: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">Outer</ac:parameter><ac:rich-text-body><ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">Inner</ac:parameter><ac:rich-text-body><p>deep</p></ac:rich-text-body></ac:structured-macro></ac:rich-text-body></ac:structured-macro>`,
+		},
+		{
+			// Lowercasing these letters changes their length in bytes
+			name:     "non-ASCII text before the closing tag",
+			input:    "<details><summary>T</summary>" + strings.Repeat("Ⱥ", 10) + "</DETAILS>",
+			expected: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">T</ac:parameter><ac:rich-text-body><p>` + strings.Repeat("Ⱥ", 10) + `</p></ac:rich-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "non-ASCII text that shrinks when lowercased",
+			input:    "<details>\n" + strings.Repeat("İ", 10) + "</details>\n\nAfter",
+			expected: `<ac:structured-macro ac:name="expand"><ac:rich-text-body><p>` + strings.Repeat("İ", 10) + `</p></ac:rich-text-body></ac:structured-macro><p>After</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MarkdownToStorage(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMarkdownToStorage_MacroRoundTrip(t *testing.T) {
+	inputs := []string{
+		"> **Info:** Important note",
+		"> **Warning:** Be careful",
+		"<details>\n<summary>Click to expand</summary>\n\nHidden content\n</details>",
+	}
+
+	for _, input := range inputs {
+		markdown, err := ToMarkdown(MarkdownToStorage(input))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if markdown != input {
+			t.Errorf("round trip mismatch:\nexpected: %q\ngot:      %q", input, markdown)
+		}
+	}
+}
+
+func TestMarkdownWarnings(t *testing.T) {
+	if warnings := MarkdownWarnings("> **Info:** x\n\n<details><summary>T</summary>y</details>"); len(warnings) != 0 {
+		t.Errorf("expected no warnings for supported constructs, got %v", warnings)
+	}
+
+	warnings := MarkdownWarnings("> **Note:** | a | b |")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "tables") {
+		t.Errorf("expected table warning from inside admonition, got %v", warnings)
+	}
+}
@@ -0,0 +1,463 @@
+package confluence
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/httpclient"
+	"gopkg.in/yaml.v3"
+)
+
+// SyncStateFile is the file in the synced directory that maps Markdown
+// files to the pages created for them.
+const SyncStateFile = ".atl-sync.json"
+
+// SyncOptions controls a push of a directory of Markdown files.
+type SyncOptions struct {
+	Dir      string
+	SpaceKey string
+	RootID   string // page the directory is published under
+	DryRun   bool   // plan only; change nothing
+	Prune    bool   // archive pages whose source files were deleted
+}
+
+// SyncAction is one planned or performed change.
+type SyncAction struct {
+	Action     string   `json:"action"` // create, update, unchanged or archive
+	Path       string   `json:"path"`
+	Title      string   `json:"title,omitempty"`
+	ID         string   `json:"id,omitempty"`
+	ParentPath string   `json:"parentPath,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// SyncPlan is the CLI output of a sync push.
+type SyncPlan struct {
+	Space   string        `json:"space"`
+	Root    string        `json:"root"`
+	DryRun  bool          `json:"dryRun"`
+	Actions []*SyncAction `json:"actions"`
+}
+
+// Write writes the plan as JSON to the given writer.
+func (p *SyncPlan) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// syncState is the content of SyncStateFile.
+type syncState struct {
+	Space string                     `json:"space"`
+	Root  string                     `json:"root"`
+	Pages map[string]*syncStateEntry `json:"pages"`
+}
+
+// syncStateEntry records the page for a path and the hash of what was last
+// uploaded to it.
+type syncStateEntry struct {
+	ID   string `json:"id"`
+	Hash string `json:"hash"`
+}
+
+// syncEntry is a page to be synced. Directories become pages too: their
+// index.md if they have one, or an empty page titled after the directory.
+type syncEntry struct {
+	path       string // slash-separated; directories without index.md end in "/"
+	parentPath string // "" for pages directly under the root page
+	title      string // "" keeps the current title (top-level index.md only)
+	markdown   string
+	root       bool // top-level index.md, synced to the root page itself
+	depth      int
+}
+
+// SyncPush publishes each Markdown file in opts.Dir as a page under
+// opts.RootID, mirroring the directory hierarchy. Pages are only uploaded
+// when their content, title or position changed since the last push.
+func (c *Client) SyncPush(ctx context.Context, opts SyncOptions) (*SyncPlan, error) {
+	if err := ValidateSpaceKey(opts.SpaceKey); err != nil {
+		return nil, err
+	}
+	if err := ValidatePageID(opts.RootID); err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	}
+
+	state, err := readSyncState(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if state.Root != "" && (state.Root != opts.RootID || state.Space != opts.SpaceKey) {
+		return nil, fmt.Errorf("%s was synced to page %s in space %s; remove it to sync elsewhere",
+			SyncStateFile, state.Root, state.Space)
+	}
+	state.Root, state.Space = opts.RootID, opts.SpaceKey
+
+	entries, err := collectSyncEntries(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]string, len(entries))
+	for _, e := range entries {
+		titles[e.path] = e.title
+	}
+
+	plan := &SyncPlan{Space: opts.SpaceKey, Root: opts.RootID, DryRun: opts.DryRun, Actions: []*SyncAction{}}
+	save := func() error {
+		if opts.DryRun {
+			return nil
+		}
+		return writeSyncState(opts.Dir, state)
+	}
+
+	for _, e := range entries {
+		storage := rewriteSyncLinks(MarkdownToStorage(e.markdown), e.path, titles)
+		hash := syncHash(e.title, e.parentPath, storage)
+
+		action := &SyncAction{Path: e.path, Title: e.title, ParentPath: e.parentPath, Warnings: MarkdownWarnings(e.markdown)}
+		plan.Actions = append(plan.Actions, action)
+
+		prev := state.Pages[e.path]
+		switch {
+		case e.root:
+			action.ID = opts.RootID
+			action.Action = "update"
+		case prev == nil:
+			action.Action = "create"
+		default:
+			action.ID = prev.ID
+			action.Action = "update"
+		}
+		if prev != nil && prev.Hash == hash {
+			action.Action = "unchanged"
+		}
+		if opts.DryRun || action.Action == "unchanged" {
+			continue
+		}
+
+		id, err := c.pushSyncEntry(ctx, opts, state, e, action.ID, storage)
+		if err != nil {
+			save()
+			return nil, fmt.Errorf("failed to sync %s: %w", e.path, err)
+		}
+		if id != action.ID {
+			action.Action = "create"
+		}
+		action.ID = id
+		state.Pages[e.path] = &syncStateEntry{ID: id, Hash: hash}
+	}
+
+	if opts.Prune {
+		present := make(map[string]bool, len(entries))
+		for _, e := range entries {
+			present[e.path] = true
+		}
+
+		var ids []string
+		for _, p := range sortedKeys(state.Pages) {
+			// The root page is never archived, even if its index.md is gone
+			if present[p] || state.Pages[p].ID == opts.RootID {
+				continue
+			}
+			plan.Actions = append(plan.Actions, &SyncAction{Action: "archive", Path: p, ID: state.Pages[p].ID})
+			ids = append(ids, state.Pages[p].ID)
+		}
+		if !opts.DryRun && len(ids) > 0 {
+			if err := c.ArchivePages(ctx, ids); err != nil {
+				save()
+				return nil, fmt.Errorf("failed to archive deleted pages: %w", err)
+			}
+			for _, p := range sortedKeys(state.Pages) {
+				if !present[p] && state.Pages[p].ID != opts.RootID {
+					delete(state.Pages, p)
+				}
+			}
+		}
+	}
+
+	if err := save(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// pushSyncEntry creates or updates the page for e and returns its ID. A page
+// recorded in the state file that no longer exists is created again.
+func (c *Client) pushSyncEntry(ctx context.Context, opts SyncOptions, state *syncState, e syncEntry, id, storage string) (string, error) {
+	parentID := opts.RootID
+	if e.parentPath != "" {
+		parent := state.Pages[e.parentPath]
+		if parent == nil {
+			return "", fmt.Errorf("parent %s has no page", e.parentPath)
+		}
+		parentID = parent.ID
+	}
+	if e.root {
+		parentID = ""
+	}
+
+	if id != "" {
+		_, err := c.UpdatePage(ctx, id, &PageUpdate{
+			Title:    e.title,
+			Body:     storage,
+			Message:  "Synced from " + e.path,
+			ParentID: parentID,
+		})
+		var apiErr *httpclient.APIError
+		if err == nil || e.root || !errors.As(err, &apiErr) || apiErr.Response.Error != httpclient.ErrTypeNotFound {
+			return id, err
+		}
+	}
+
+	created, err := c.CreatePage(ctx, &NewPage{
+		SpaceKey: opts.SpaceKey,
+		Title:    e.title,
+		ParentID: parentID,
+		Body:     storage,
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// collectSyncEntries finds the Markdown files under dir and orders them so
+// that every page comes after its parent. Hidden files and directories are
+// skipped.
+func collectSyncEntries(dir string) ([]syncEntry, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".md") {
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	// dirPage returns the path of the page standing for a directory
+	dirPage := func(d string) string {
+		if d == "." {
+			return ""
+		}
+		if files[d+"/index.md"] {
+			return d + "/index.md"
+		}
+		return d + "/"
+	}
+	depth := func(d string) int {
+		if d == "." {
+			return 0
+		}
+		return strings.Count(d, "/") + 1
+	}
+
+	var entries []syncEntry
+	placeholders := make(map[string]bool)
+	var addPlaceholders func(d string)
+	addPlaceholders = func(d string) {
+		if d == "." || files[d+"/index.md"] || placeholders[d] {
+			return
+		}
+		placeholders[d] = true
+		entries = append(entries, syncEntry{
+			path:       d + "/",
+			parentPath: dirPage(path.Dir(d)),
+			title:      titleFromName(path.Base(d)),
+			depth:      depth(d),
+		})
+		addPlaceholders(path.Dir(d))
+	}
+
+	for p := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		title, markdown := splitSyncFile(string(data))
+
+		d := path.Dir(p)
+		e := syncEntry{path: p, markdown: markdown, title: title}
+		if path.Base(p) == "index.md" {
+			e.root = d == "."
+			e.parentPath = dirPage(path.Dir(d))
+			e.depth = depth(d)
+			if title == "" && !e.root {
+				e.title = titleFromName(path.Base(d))
+			}
+			addPlaceholders(path.Dir(d))
+		} else {
+			e.parentPath = dirPage(d)
+			e.depth = depth(d) + 1
+			if title == "" {
+				e.title = titleFromName(strings.TrimSuffix(path.Base(p), path.Ext(p)))
+			}
+			addPlaceholders(d)
+		}
+		if e.root {
+			e.parentPath = ""
+		}
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].depth != entries[j].depth {
+			return entries[i].depth < entries[j].depth
+		}
+		return entries[i].path < entries[j].path
+	})
+	return entries, nil
+}
+
+var leadingHeadingRe = regexp.MustCompile(`^#\s+(.+?)\s*#*\s*(?:\n|$)`)
+
+// splitSyncFile returns the page title and Markdown body of a file. The
+// title comes from a "title" front matter field, or else from a leading
+// level-1 heading, which is then removed since Confluence shows the title
+// above the page.
+func splitSyncFile(content string) (string, string) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	if strings.HasPrefix(content, "---\n") {
+		if end := strings.Index(content[4:], "\n---"); end >= 0 {
+			var fm struct {
+				Title string `yaml:"title"`
+			}
+			if yaml.Unmarshal([]byte(content[4:4+end]), &fm) == nil {
+				rest := content[4+end+4:]
+				if i := strings.IndexByte(rest, '\n'); i >= 0 {
+					rest = rest[i+1:]
+				} else {
+					rest = ""
+				}
+				content = rest
+				if fm.Title != "" {
+					return fm.Title, strings.TrimLeft(content, "\n")
+				}
+			}
+		}
+	}
+
+	content = strings.TrimLeft(content, "\n")
+	if m := leadingHeadingRe.FindStringSubmatch(content); m != nil {
+		return m[1], strings.TrimLeft(content[len(m[0]):], "\n")
+	}
+	return "", content
+}
+
+// titleFromName turns a file or directory name into a page title.
+func titleFromName(name string) string {
+	return strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(name))
+}
+
+var anchorTagRe = regexp.MustCompile(`(?s)<a href="([^"]*)">(.*?)</a>`)
+
+// rewriteSyncLinks turns links from the file at from to other synced files
+// into Confluence page links, so they keep working once published.
+func rewriteSyncLinks(storage, from string, titles map[string]string) string {
+	return anchorTagRe.ReplaceAllStringFunc(storage, func(match string) string {
+		m := anchorTagRe.FindStringSubmatch(match)
+		href := html.UnescapeString(m[1])
+		if strings.Contains(href, ":") || strings.HasPrefix(href, "/") || strings.HasPrefix(href, "#") {
+			return match
+		}
+
+		target, anchor, _ := strings.Cut(href, "#")
+		if unescaped, err := url.PathUnescape(target); err == nil {
+			target = unescaped
+		}
+		target = path.Join(path.Dir(from), target)
+		if strings.HasPrefix(target, "../") || target == ".." {
+			return match
+		}
+
+		title, ok := "", false
+		for _, candidate := range []string{target, target + "/index.md", target + "/"} {
+			if title, ok = titles[candidate]; ok {
+				break
+			}
+		}
+		if !ok || title == "" {
+			return match
+		}
+
+		var b strings.Builder
+		b.WriteString("<ac:link")
+		if anchor != "" {
+			b.WriteString(` ac:anchor="` + html.EscapeString(anchor) + `"`)
+		}
+		b.WriteString(`><ri:page ri:content-title="` + html.EscapeString(title) + `"/>`)
+		b.WriteString("<ac:link-body>" + m[2] + "</ac:link-body></ac:link>")
+		return b.String()
+	})
+}
+
+// syncHash identifies what would be uploaded for a page.
+func syncHash(title, parentPath, storage string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + parentPath + "\x00" + storage))
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedKeys(m map[string]*syncStateEntry) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readSyncState loads the state file of a previous push, if any.
+func readSyncState(dir string) (*syncState, error) {
+	state := &syncState{Pages: make(map[string]*syncStateEntry)}
+	data, err := os.ReadFile(filepath.Join(dir, SyncStateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	if state.Pages == nil {
+		state.Pages = make(map[string]*syncStateEntry)
+	}
+	return state, nil
+}
+
+// writeSyncState saves the state file for the next push.
+func writeSyncState(dir string, state *syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(dir, SyncStateFile), strings.NewReader(string(data)+"\n"))
+}
@@ -0,0 +1,275 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+// writeSyncDir creates files (slash-separated path -> content) under a new
+// temporary directory.
+func writeSyncDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSplitSyncFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantTitle string
+		wantBody  string
+	}{
+		{"front matter", "---\ntitle: Setup guide\nid: \"1\"\n---\n\nBody", "Setup guide", "Body"},
+		{"leading heading", "# Setup guide\n\nBody", "Setup guide", "Body"},
+		{"no title", "Body only", "", "Body only"},
+		{"front matter without title", "---\nlabels: [a]\n---\n# Heading\nBody", "Heading", "Body"},
+		{"second-level heading kept", "## Section\nBody", "", "## Section\nBody"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := splitSyncFile(tt.content)
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("got (%q, %q), want (%q, %q)", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestCollectSyncEntries(t *testing.T) {
+	dir := writeSyncDir(t, map[string]string{
+		"index.md":              "Root content",
+		"getting-started.md":    "# Getting started\n",
+		"guides/index.md":       "# Guides\n",
+		"guides/deploy.md":      "Deploy",
+		"reference/api/auth.md": "Auth",
+		".github/readme.md":     "hidden",
+		"notes.txt":             "not markdown",
+	})
+
+	entries, err := collectSyncEntries(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%s<%s>%q", e.path, e.parentPath, e.title))
+	}
+	want := []string{
+		`index.md<>""`,
+		`getting-started.md<>"Getting started"`,
+		`guides/index.md<>"Guides"`,
+		`reference/<>"reference"`,
+		`guides/deploy.md<guides/index.md>"deploy"`,
+		`reference/api/<reference/>"api"`,
+		`reference/api/auth.md<reference/api/>"auth"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !entries[0].root {
+		t.Error("expected top-level index.md to be the root page")
+	}
+}
+
+func TestRewriteSyncLinks(t *testing.T) {
+	titles := map[string]string{
+		"guides/index.md":  "Guides",
+		"guides/deploy.md": "Deploying",
+		"faq.md":           "FAQ",
+	}
+
+	tests := []struct {
+		name    string
+		storage string
+		want    string
+	}{
+		{
+			"sibling file",
+			`<a href="deploy.md">deploy</a>`,
+			`<ac:link><ri:page ri:content-title="Deploying"/><ac:link-body>deploy</ac:link-body></ac:link>`,
+		},
+		{
+			"parent directory with anchor",
+			`<a href="../faq.md#billing">FAQ</a>`,
+			`<ac:link ac:anchor="billing"><ri:page ri:content-title="FAQ"/><ac:link-body>FAQ</ac:link-body></ac:link>`,
+		},
+		{
+			"directory link",
+			`<a href="../guides">all guides</a>`,
+			`<ac:link><ri:page ri:content-title="Guides"/><ac:link-body>all guides</ac:link-body></ac:link>`,
+		},
+		{"external link", `<a href="https://example.com/a.md">x</a>`, `<a href="https://example.com/a.md">x</a>`},
+		{"unknown file", `<a href="missing.md">x</a>`, `<a href="missing.md">x</a>`},
+		{"outside the directory", `<a href="../../x.md">x</a>`, `<a href="../../x.md">x</a>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteSyncLinks(tt.storage, "guides/deploy.md", titles); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// syncTestServer records the pages created, updated and archived.
+type syncTestServer struct {
+	*httptest.Server
+	nextID   int
+	created  []createPageRequest
+	updated  []updatePageRequest
+	archived []int64
+}
+
+func newSyncTestServer(t *testing.T) *syncTestServer {
+	s := &syncTestServer{nextID: 100}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/wiki/api/v2/spaces":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"id": "9", "key": "ENG"}},
+			})
+		case r.Method == "POST" && r.URL.Path == "/wiki/api/v2/pages":
+			var req createPageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			s.created = append(s.created, req)
+			s.nextID++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": fmt.Sprint(s.nextID), "title": req.Title, "version": map[string]int{"number": 1},
+			})
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/wiki/api/v2/pages/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": strings.TrimPrefix(r.URL.Path, "/wiki/api/v2/pages/"), "title": "Current",
+				"version": map[string]int{"number": 1},
+			})
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/wiki/api/v2/pages/"):
+			var req updatePageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			s.updated = append(s.updated, req)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": req.ID, "title": req.Title, "version": map[string]int{"number": req.Version.Number},
+			})
+		case r.Method == "POST" && r.URL.Path == "/wiki/rest/api/content/archive":
+			var req archiveRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			for _, p := range req.Pages {
+				s.archived = append(s.archived, p.ID)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id": "task"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
+
+func TestClient_SyncPush(t *testing.T) {
+	server := newSyncTestServer(t)
+	defer server.Close()
+	client := newTestClient(server.Server)
+
+	dir := writeSyncDir(t, map[string]string{
+		"guides/index.md":  "# Guides\nSee [deploying](deploy.md).",
+		"guides/deploy.md": "# Deploying\nSteps",
+		"old.md":           "# Old\n",
+	})
+	opts := SyncOptions{Dir: dir, SpaceKey: "ENG", RootID: "1"}
+
+	// Dry run changes nothing
+	plan, err := client.SyncPush(context.Background(), SyncOptions{Dir: dir, SpaceKey: "ENG", RootID: "1", DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Actions) != 3 || plan.Actions[0].Action != "create" || len(server.created) != 0 {
+		t.Fatalf("unexpected dry run: %+v, %d created", plan.Actions, len(server.created))
+	}
+	if _, err := os.Stat(filepath.Join(dir, SyncStateFile)); err == nil {
+		t.Error("expected dry run not to write the state file")
+	}
+
+	// First push creates every page, parents first
+	if _, err := client.SyncPush(context.Background(), opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(server.created) != 3 {
+		t.Fatalf("expected 3 pages created, got %d", len(server.created))
+	}
+	guides, deploy := server.created[0], server.created[2]
+	if guides.Title != "Guides" || guides.ParentID != "1" {
+		t.Errorf("unexpected guides page: %+v", guides)
+	}
+	if deploy.Title != "Deploying" || deploy.ParentID != "101" {
+		t.Errorf("expected deploy under guides (101), got %+v", deploy)
+	}
+	if !strings.Contains(guides.Body.Value, `<ri:page ri:content-title="Deploying"/>`) {
+		t.Errorf("expected link to become a page link, got %s", guides.Body.Value)
+	}
+
+	// Unchanged files are not uploaded again
+	plan, err = client.SyncPush(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, a := range plan.Actions {
+		if a.Action != "unchanged" {
+			t.Errorf("expected %s to be unchanged, got %s", a.Path, a.Action)
+		}
+	}
+	if len(server.updated) != 0 || len(server.created) != 3 {
+		t.Errorf("expected no uploads, got %d updates", len(server.updated))
+	}
+
+	// An edited file is updated in place, and a deleted one archived with --prune
+	os.WriteFile(filepath.Join(dir, "guides", "deploy.md"), []byte("# Deploying\nNew steps"), 0o644)
+	os.Remove(filepath.Join(dir, "old.md"))
+	opts.Prune = true
+	plan, err = client.SyncPush(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(server.updated) != 1 || server.updated[0].ID != "103" || server.updated[0].ParentID != "101" {
+		t.Errorf("expected deploy page to be updated, got %+v", server.updated)
+	}
+	if len(server.archived) != 1 || server.archived[0] != 102 {
+		t.Errorf("expected old page to be archived, got %v", server.archived)
+	}
+	if last := plan.Actions[len(plan.Actions)-1]; last.Action != "archive" || last.Path != "old.md" {
+		t.Errorf("expected archive action, got %+v", last)
+	}
+}
+
+func TestClient_SyncPush_DifferentRoot(t *testing.T) {
+	dir := writeSyncDir(t, map[string]string{
+		SyncStateFile: `{"space": "ENG", "root": "1", "pages": {}}`,
+	})
+	client := NewClient(&config.Config{Site: "test.atlassian.net"}, false)
+
+	_, err := client.SyncPush(context.Background(), SyncOptions{Dir: dir, SpaceKey: "ENG", RootID: "2", DryRun: true})
+	if err == nil || !strings.Contains(err.Error(), "was synced to page 1") {
+		t.Errorf("expected root mismatch error, got %v", err)
+	}
+}
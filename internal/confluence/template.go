@@ -0,0 +1,287 @@
+package confluence
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/martin/atl-cli/internal/jira"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateExt is the file extension of page templates.
+const TemplateExt = ".md"
+
+// PageTemplateFrontmatter contains the YAML frontmatter of a page template.
+type PageTemplateFrontmatter struct {
+	Version     int      `yaml:"version"`
+	Description string   `yaml:"description"`
+	Space       string   `yaml:"space"`
+	Parent      string   `yaml:"parent"`
+	Title       string   `yaml:"title"`
+	Labels      []string `yaml:"labels"`
+}
+
+// PageTemplate is a parsed page template: frontmatter and a Markdown body,
+// both of which may use Go text/template variables.
+type PageTemplate struct {
+	Frontmatter PageTemplateFrontmatter
+	Body        string
+}
+
+// ParsedPageTemplate is a page template with variables applied.
+type ParsedPageTemplate struct {
+	Space  string
+	Parent string
+	Title  string
+	Labels []string
+	Body   string // Markdown
+}
+
+// LoadPageTemplate loads and parses a page template file.
+func LoadPageTemplate(path string) (*PageTemplate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+	return ParsePageTemplate(string(content))
+}
+
+// ParsePageTemplate parses template content into frontmatter and body.
+func ParsePageTemplate(content string) (*PageTemplate, error) {
+	frontmatter, body, err := jira.SplitTemplateFrontmatter(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var fm PageTemplateFrontmatter
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		return nil, fmt.Errorf("failed to parse template frontmatter: %w", err)
+	}
+	if fm.Version != 1 {
+		return nil, fmt.Errorf("unsupported template version: %d (expected 1)", fm.Version)
+	}
+
+	return &PageTemplate{
+		Frontmatter: fm,
+		Body:        body,
+	}, nil
+}
+
+// Apply applies variables to the template. Every variable the template
+// uses must be set: unlike Jira templates, which render a missing variable
+// as "<no value>", this is an error, since it would end up in a page title.
+func (t *PageTemplate) Apply(vars map[string]string) (*ParsedPageTemplate, error) {
+	var missing []string
+	for _, name := range t.Variables() {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template variables not set: %s", strings.Join(missing, ", "))
+	}
+
+	parsed := &ParsedPageTemplate{}
+	fields := []struct {
+		name string
+		text string
+		dest *string
+	}{
+		{"space", t.Frontmatter.Space, &parsed.Space},
+		{"parent", t.Frontmatter.Parent, &parsed.Parent},
+		{"title", t.Frontmatter.Title, &parsed.Title},
+		{"body", t.Body, &parsed.Body},
+	}
+	for _, f := range fields {
+		value, err := applyPageTemplateVars(f.text, vars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply variables to %s: %w", f.name, err)
+		}
+		*f.dest = value
+	}
+	parsed.Space = strings.TrimSpace(parsed.Space)
+	parsed.Parent = strings.TrimSpace(parsed.Parent)
+	parsed.Title = strings.TrimSpace(parsed.Title)
+	parsed.Body = strings.TrimSpace(parsed.Body)
+
+	for _, label := range t.Frontmatter.Labels {
+		value, err := applyPageTemplateVars(label, vars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply variables to label: %w", err)
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			parsed.Labels = append(parsed.Labels, value)
+		}
+	}
+	return parsed, nil
+}
+
+// Variables returns the names of the variables the template uses, sorted.
+func (t *PageTemplate) Variables() []string {
+	texts := append([]string{t.Frontmatter.Space, t.Frontmatter.Parent, t.Frontmatter.Title, t.Body}, t.Frontmatter.Labels...)
+	var names []string
+	for _, text := range texts {
+		tmpl, err := template.New("").Parse(text)
+		if err != nil || tmpl.Tree == nil {
+			continue
+		}
+		names = appendTemplateFields(names, tmpl.Tree.Root)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// appendTemplateFields appends the top-level fields ({{.name}}) referred to
+// under a template parse node.
+func appendTemplateFields(names []string, node parse.Node) []string {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return names
+		}
+		for _, c := range n.Nodes {
+			names = appendTemplateFields(names, c)
+		}
+	case *parse.ActionNode:
+		names = appendTemplateFields(names, n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return names
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				names = appendTemplateFields(names, arg)
+			}
+		}
+	case *parse.FieldNode:
+		names = append(names, n.Ident[0])
+	case *parse.IfNode:
+		names = appendTemplateFields(names, n.Pipe)
+		names = appendTemplateFields(names, n.List)
+		names = appendTemplateFields(names, n.ElseList)
+	case *parse.RangeNode:
+		names = appendTemplateFields(names, n.Pipe)
+		names = appendTemplateFields(names, n.List)
+		names = appendTemplateFields(names, n.ElseList)
+	case *parse.WithNode:
+		names = appendTemplateFields(names, n.Pipe)
+		names = appendTemplateFields(names, n.List)
+		names = appendTemplateFields(names, n.ElseList)
+	}
+	return names
+}
+
+// applyPageTemplateVars applies Go template variables to a string, failing
+// on variables that are not defined.
+func applyPageTemplateVars(text string, vars map[string]string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// TemplateInfo describes a template found on the template search path.
+type TemplateInfo struct {
+	Name        string   `json:"name"`
+	Path        string   `json:"path"`
+	Description string   `json:"description,omitempty"`
+	Space       string   `json:"space,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Variables   []string `json:"variables"`
+	// Error explains why a template file could not be parsed.
+	Error string `json:"error,omitempty"`
+}
+
+// TemplateList is the templates found on the search path.
+type TemplateList []*TemplateInfo
+
+// Write writes the templates as a JSON array to the given writer.
+func (l TemplateList) Write(w interface{ Write([]byte) (int, error) }) error {
+	if l == nil {
+		l = TemplateList{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// ListTemplates returns the templates in the given directories, in order
+// of name. A template in an earlier directory hides one with the same name
+// in a later one. Directories that do not exist are skipped, and templates
+// that cannot be parsed are listed with the error.
+func ListTemplates(dirs []string) (TemplateList, error) {
+	var list TemplateList
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template directory: %w", err)
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), TemplateExt)
+			if !ok || entry.IsDir() || seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			info := &TemplateInfo{Name: name, Path: filepath.Join(dir, entry.Name()), Variables: []string{}}
+			tmpl, err := LoadPageTemplate(info.Path)
+			if err != nil {
+				info.Error = err.Error()
+			} else {
+				info.Description = tmpl.Frontmatter.Description
+				info.Space = tmpl.Frontmatter.Space
+				info.Title = tmpl.Frontmatter.Title
+				info.Labels = tmpl.Frontmatter.Labels
+				info.Variables = tmpl.Variables()
+			}
+			list = append(list, info)
+		}
+	}
+	slices.SortFunc(list, func(a, b *TemplateInfo) int { return strings.Compare(a.Name, b.Name) })
+	return list, nil
+}
+
+// FindTemplate returns the file of a template given by path or by name. A
+// name is looked up in dirs, with or without the .md extension.
+func FindTemplate(name string, dirs []string) (string, error) {
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+	if strings.ContainsRune(name, filepath.Separator) || strings.ContainsRune(name, '/') {
+		return "", fmt.Errorf("template file %s not found", name)
+	}
+
+	file := name
+	if !strings.HasSuffix(file, TemplateExt) {
+		file += TemplateExt
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, file)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("template %q not found (searched %s)", name, strings.Join(dirs, ", "))
+}
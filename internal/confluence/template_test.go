@@ -0,0 +1,170 @@
+package confluence
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const postmortemTemplate = `---
+version: 1
+description: Incident postmortem
+space: ENG
+parent: "12345"
+title: "Postmortem: {{.incident}}"
+labels:
+  - postmortem
+  - "{{.team}}"
+---
+
+## Summary
+
+{{.incident}} happened on {{.date}}.
+`
+
+func TestParsePageTemplate(t *testing.T) {
+	tmpl, err := ParsePageTemplate(postmortemTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fm := tmpl.Frontmatter
+	if fm.Space != "ENG" || fm.Parent != "12345" || fm.Title != "Postmortem: {{.incident}}" || fm.Description != "Incident postmortem" {
+		t.Errorf("unexpected frontmatter: %+v", fm)
+	}
+	if len(fm.Labels) != 2 {
+		t.Errorf("expected 2 labels, got %v", fm.Labels)
+	}
+	if !strings.HasPrefix(tmpl.Body, "\n## Summary") {
+		t.Errorf("unexpected body: %q", tmpl.Body)
+	}
+}
+
+func TestParsePageTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no frontmatter", "# Title\n"},
+		{"unclosed frontmatter", "---\nversion: 1\ntitle: x\n"},
+		{"wrong version", "---\nversion: 2\n---\nBody"},
+		{"invalid YAML", "---\nversion: [1\n---\nBody"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePageTemplate(tt.content); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestPageTemplate_Apply(t *testing.T) {
+	tmpl, err := ParsePageTemplate(postmortemTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed, err := tmpl.Apply(map[string]string{"incident": "INC-42", "team": "payments", "date": "2026-10-01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.Title != "Postmortem: INC-42" || parsed.Space != "ENG" || parsed.Parent != "12345" {
+		t.Errorf("unexpected result: %+v", parsed)
+	}
+	if strings.Join(parsed.Labels, ",") != "postmortem,payments" {
+		t.Errorf("unexpected labels: %v", parsed.Labels)
+	}
+	if parsed.Body != "## Summary\n\nINC-42 happened on 2026-10-01." {
+		t.Errorf("unexpected body: %q", parsed.Body)
+	}
+
+	if _, err := tmpl.Apply(map[string]string{"incident": "INC-42"}); err == nil || !strings.Contains(err.Error(), "date, team") {
+		t.Errorf("expected error naming the missing variable, got %v", err)
+	}
+}
+
+func TestPageTemplate_Variables(t *testing.T) {
+	tmpl, err := ParsePageTemplate(postmortemTemplate + "{{if .followUp}}Follow-up: {{.followUp}}{{end}}\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := strings.Join(tmpl.Variables(), ",")
+	if got != "date,followUp,incident,team" {
+		t.Errorf("variables = %s", got)
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	team := t.TempDir()
+	personal := t.TempDir()
+	write := func(dir, name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(team, "postmortem.md", postmortemTemplate)
+	write(team, "notes.txt", "not a template")
+	write(personal, "postmortem.md", "---\nversion: 1\ntitle: Hidden\n---\n")
+	write(personal, "broken.md", "no frontmatter")
+	write(personal, "rfc.md", "---\nversion: 1\ntitle: \"RFC: {{.name}}\"\n---\n")
+
+	list, err := ListTemplates([]string{team, filepath.Join(team, "missing"), personal})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, info := range list {
+		names = append(names, info.Name)
+	}
+	if strings.Join(names, ",") != "broken,postmortem,rfc" {
+		t.Fatalf("unexpected templates: %v", names)
+	}
+	if list[0].Error == "" {
+		t.Error("expected an error for the broken template")
+	}
+	if list[1].Path != filepath.Join(team, "postmortem.md") || list[1].Description != "Incident postmortem" {
+		t.Errorf("earlier directory should win: %+v", list[1])
+	}
+	if strings.Join(list[2].Variables, ",") != "name" {
+		t.Errorf("unexpected variables: %v", list[2].Variables)
+	}
+}
+
+func TestFindTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rfc.md")
+	if err := os.WriteFile(path, []byte("---\nversion: 1\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: path, want: path},
+		{name: "rfc", want: path},
+		{name: "rfc.md", want: path},
+		{name: "postmortem", wantErr: true},
+		{name: filepath.Join(dir, "missing.md"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindTemplate(tt.name, []string{filepath.Join(dir, "none"), dir})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
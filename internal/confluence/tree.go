@@ -0,0 +1,246 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PageNode is a page in a page tree, with enough metadata for a caller to
+// decide which pages to fetch next.
+type PageNode struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Position    int    `json:"position"`
+	HasChildren bool   `json:"hasChildren"`
+	// ChildCount is only set on nodes whose children were listed.
+	ChildCount *int        `json:"childCount,omitempty"`
+	Children   []*PageNode `json:"children,omitempty"`
+}
+
+// Write writes the node (and any expanded children) as JSON to the given writer.
+func (n *PageNode) Write(w interface{ Write([]byte) (int, error) }) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(n)
+}
+
+// PageNodeList is a list of page nodes written as a JSON array.
+type PageNodeList []*PageNode
+
+// Write writes the nodes as a JSON array to the given writer.
+func (l PageNodeList) Write(w interface{ Write([]byte) (int, error) }) error {
+	if l == nil {
+		l = PageNodeList{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// apiChildrenResponse represents a page of /pages/{id}/children results.
+type apiChildrenResponse struct {
+	Results []struct {
+		ID            string `json:"id"`
+		Title         string `json:"title"`
+		Status        string `json:"status"`
+		ChildPosition int    `json:"childPosition"`
+	} `json:"results"`
+}
+
+// apiAncestorsResponse represents a page of /pages/{id}/ancestors results.
+type apiAncestorsResponse struct {
+	Results []struct {
+		ID string `json:"id"`
+	} `json:"results"`
+}
+
+// apiPageSummary is a page fetched without its body.
+type apiPageSummary struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Position int    `json:"position"`
+	ParentID string `json:"parentId"`
+	SpaceID  string `json:"spaceId"`
+	Version  struct {
+		Number    int    `json:"number"`
+		CreatedAt string `json:"createdAt"`
+	} `json:"version"`
+}
+
+// GetChildren returns the direct children of a page in position order, each
+// marked with whether it has children of its own.
+func (c *Client) GetChildren(ctx context.Context, id string) (PageNodeList, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+
+	children, err := c.listChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if err := c.checkChildren(ctx, child); err != nil {
+			return nil, err
+		}
+	}
+	return children, nil
+}
+
+// GetTree returns a page with its descendants expanded to the given depth,
+// where depth 1 includes only direct children. Nodes at the depth limit
+// report whether they have children without listing them.
+func (c *Client) GetTree(ctx context.Context, id string, depth int) (*PageNode, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+	if depth < 1 {
+		return nil, fmt.Errorf("depth must be at least 1")
+	}
+
+	root, err := c.pageNode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.expandTree(ctx, root, depth); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// GetAncestors returns the ancestors of a page from the space root down to
+// its parent.
+func (c *Client) GetAncestors(ctx context.Context, id string) (PageNodeList, error) {
+	if err := ValidatePageID(id); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	path := fmt.Sprintf("/wiki/api/v2/pages/%s/ancestors?limit=250", id)
+	err := c.getPaged(ctx, path, func(body []byte) error {
+		var resp apiAncestorsResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, r := range resp.Results {
+			ids = append(ids, r.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The ancestors endpoint returns only IDs, so each one is looked up.
+	// Every ancestor has at least one child: the next one down the path.
+	ancestors := PageNodeList{}
+	for _, ancestorID := range ids {
+		node, err := c.pageNode(ctx, ancestorID)
+		if err != nil {
+			return nil, err
+		}
+		node.HasChildren = true
+		ancestors = append(ancestors, node)
+	}
+	return ancestors, nil
+}
+
+// expandTree fills in the children of node down to depth levels.
+func (c *Client) expandTree(ctx context.Context, node *PageNode, depth int) error {
+	children, err := c.listChildren(ctx, node.ID)
+	if err != nil {
+		return err
+	}
+	count := len(children)
+	node.ChildCount = &count
+	node.HasChildren = count > 0
+	if count > 0 {
+		node.Children = children
+	}
+
+	for _, child := range children {
+		if depth > 1 {
+			err = c.expandTree(ctx, child, depth-1)
+		} else {
+			err = c.checkChildren(ctx, child)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkChildren sets whether node has children, fetching at most one of
+// them rather than the whole listing.
+func (c *Client) checkChildren(ctx context.Context, node *PageNode) error {
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/pages/%s/children?limit=1", c.cfg.BaseURL(), url.PathEscape(node.ID))
+	body, err := c.doJSON(ctx, "GET", reqURL, nil, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	var resp apiChildrenResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	node.HasChildren = len(resp.Results) > 0
+	return nil
+}
+
+// listChildren fetches all direct children of a page, following cursors.
+func (c *Client) listChildren(ctx context.Context, id string) (PageNodeList, error) {
+	children := PageNodeList{}
+	path := fmt.Sprintf("/wiki/api/v2/pages/%s/children?limit=250", url.PathEscape(id))
+	err := c.getPaged(ctx, path, func(body []byte) error {
+		var resp apiChildrenResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, r := range resp.Results {
+			children = append(children, &PageNode{
+				ID:       r.ID,
+				Title:    r.Title,
+				Status:   r.Status,
+				Position: r.ChildPosition,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+// pageNode fetches a page's tree metadata.
+func (c *Client) pageNode(ctx context.Context, id string) (*PageNode, error) {
+	resp, err := c.pageSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &PageNode{
+		ID:       resp.ID,
+		Title:    resp.Title,
+		Status:   resp.Status,
+		Position: resp.Position,
+	}, nil
+}
+
+// pageSummary fetches a page's metadata without its body.
+func (c *Client) pageSummary(ctx context.Context, id string) (*apiPageSummary, error) {
+	reqURL := fmt.Sprintf("%s/wiki/api/v2/pages/%s", c.cfg.BaseURL(), url.PathEscape(id))
+	body, err := c.doJSON(ctx, "GET", reqURL, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiPageSummary
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &resp, nil
+}
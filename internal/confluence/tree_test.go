@@ -0,0 +1,174 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+// newTreeTestServer serves this page tree:
+//
+//	1 Root
+//	├── 2 Service A
+//	│   └── 4 Runbook
+//	│       └── 5 Alerts
+//	└── 3 Service B
+//
+// The children of page 1 are split across two cursor pages.
+func newTreeTestServer(t *testing.T) *httptest.Server {
+	titles := map[string]string{"1": "Root", "2": "Service A", "3": "Service B", "4": "Runbook", "5": "Alerts"}
+	children := map[string][]string{"1": {"2", "3"}, "2": {"4"}, "4": {"5"}}
+
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/wiki/api/v2/pages/"), "/")
+		id := parts[0]
+
+		switch {
+		case len(parts) == 1:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": id, "title": titles[id], "status": "current", "position": 7,
+			})
+
+		case parts[1] == "children":
+			ids := children[id]
+			var next string
+			if r.URL.Query().Get("limit") == "1" {
+				// An existence check: one child is enough
+				if len(ids) > 1 {
+					ids, next = ids[:1], "/wiki/api/v2/pages/"+id+"/children?cursor=more"
+				}
+			} else if id == "1" {
+				if r.URL.Query().Get("cursor") == "" {
+					ids, next = ids[:1], "/wiki/api/v2/pages/1/children?cursor=page2"
+				} else {
+					ids = ids[1:]
+				}
+			}
+			results := []map[string]interface{}{}
+			for i, childID := range ids {
+				results = append(results, map[string]interface{}{
+					"id": childID, "title": titles[childID], "status": "current", "childPosition": i,
+				})
+			}
+			resp := map[string]interface{}{"results": results}
+			if next != "" {
+				resp["_links"] = map[string]string{"next": next}
+			}
+			json.NewEncoder(w).Encode(resp)
+
+		case parts[1] == "ancestors":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"id": "1", "type": "page"}, {"id": "2", "type": "page"}},
+			})
+
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestClient_GetChildren(t *testing.T) {
+	server := newTreeTestServer(t)
+	defer server.Close()
+
+	children, err := newTestClient(server).GetChildren(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(children) != 2 {
+		t.Fatalf("expected 2 children across cursor pages, got %d", len(children))
+	}
+	if children[0].Title != "Service A" || !children[0].HasChildren || children[0].ChildCount != nil || children[0].Status != "current" {
+		t.Errorf("unexpected first child: %+v", children[0])
+	}
+	if children[1].Title != "Service B" || children[1].HasChildren {
+		t.Errorf("unexpected second child: %+v", children[1])
+	}
+	if children[0].Children != nil {
+		t.Error("expected children to list direct children only")
+	}
+}
+
+func TestClient_GetTree_Depth(t *testing.T) {
+	server := newTreeTestServer(t)
+	defer server.Close()
+
+	tree, err := newTestClient(server).GetTree(context.Background(), "1", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tree.Title != "Root" || tree.ChildCount == nil || *tree.ChildCount != 2 || !tree.HasChildren || tree.Position != 7 {
+		t.Errorf("unexpected root: %+v", tree)
+	}
+	serviceA := tree.Children[0]
+	if len(serviceA.Children) != 1 || serviceA.Children[0].Title != "Runbook" {
+		t.Fatalf("expected Runbook under Service A, got %+v", serviceA.Children)
+	}
+
+	// The depth limit stops expansion but says whether there is more
+	runbook := serviceA.Children[0]
+	if !runbook.HasChildren || runbook.ChildCount != nil || runbook.Children != nil {
+		t.Errorf("expected unexpanded node with children, got %+v", runbook)
+	}
+	if serviceB := tree.Children[1]; serviceB.HasChildren || serviceB.ChildCount == nil || *serviceB.ChildCount != 0 {
+		t.Errorf("expected expanded leaf with no children, got %+v", serviceB)
+	}
+}
+
+func TestClient_GetTree_InvalidDepth(t *testing.T) {
+	client := NewClient(&config.Config{Site: "test.atlassian.net"}, false)
+	if _, err := client.GetTree(context.Background(), "1", 0); err == nil {
+		t.Error("expected error for depth 0")
+	}
+}
+
+func TestClient_GetAncestors(t *testing.T) {
+	server := newTreeTestServer(t)
+	defer server.Close()
+
+	ancestors, err := newTestClient(server).GetAncestors(context.Background(), "4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, a := range ancestors {
+		titles = append(titles, a.Title)
+	}
+	if strings.Join(titles, " > ") != "Root > Service A" {
+		t.Errorf("unexpected breadcrumb: %v", titles)
+	}
+	if !ancestors[0].HasChildren {
+		t.Errorf("expected root to have children: %+v", ancestors[0])
+	}
+}
+
+func TestPageNode_Write(t *testing.T) {
+	node := &PageNode{ID: "1", Title: "Root", Status: "current"}
+
+	var buf strings.Builder
+	if err := node.Write(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(buf.String()), &parsed); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	for _, field := range []string{"id", "title", "status", "position", "hasChildren"} {
+		if _, ok := parsed[field]; !ok {
+			t.Errorf("expected field %q in output", field)
+		}
+	}
+	if _, ok := parsed["children"]; ok {
+		t.Error("expected children to be omitted for a leaf")
+	}
+}
@@ -0,0 +1,43 @@
+package confluence
+
+import "fmt"
+
+// PageUpdate describes new content for an existing page.
+type PageUpdate struct {
+	Title         string // optional; the current title is kept if empty
+	Body          string // storage format
+	Message       string // optional version message
+	MinorEdit     bool   // suppress watcher notifications
+	ExpectVersion int    // optional; fail with a conflict unless the page is at this version
+	ParentID      string // optional; moves the page under this parent
+}
+
+// Validate checks that the update has the fields required by the API.
+func (u *PageUpdate) Validate() error {
+	if u.ExpectVersion < 0 {
+		return fmt.Errorf("expected version must be positive, got %d", u.ExpectVersion)
+	}
+	if u.ParentID != "" {
+		if err := ValidatePageID(u.ParentID); err != nil {
+			return fmt.Errorf("invalid parent: %w", err)
+		}
+	}
+	return nil
+}
+
+// updatePageRequest is the request body for PUT /wiki/api/v2/pages/{id}.
+type updatePageRequest struct {
+	ID       string      `json:"id"`
+	Status   string      `json:"status"`
+	Title    string      `json:"title"`
+	ParentID string      `json:"parentId,omitempty"`
+	Body     pageBody    `json:"body"`
+	Version  pageVersion `json:"version"`
+}
+
+// pageVersion is the version block submitted with an update.
+type pageVersion struct {
+	Number    int    `json:"number"`
+	Message   string `json:"message,omitempty"`
+	MinorEdit bool   `json:"minorEdit,omitempty"`
+}
@@ -0,0 +1,136 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+	"github.com/martin/atl-cli/internal/httpclient"
+)
+
+// newUpdateTestServer serves a page at currentVersion and hands PUT requests
+// to onPut.
+func newUpdateTestServer(t *testing.T, currentVersion int, onPut func(w http.ResponseWriter, req updatePageRequest)) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":      "123",
+				"title":   "Runbook",
+				"spaceId": "65011",
+				"version": map[string]interface{}{"number": currentVersion},
+			})
+		case "PUT":
+			var req updatePageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			onPut(w, req)
+		}
+	}))
+}
+
+func newTestClient(server *httptest.Server) *Client {
+	client := NewClient(&config.Config{
+		Site:  strings.TrimPrefix(server.URL, "https://"),
+		Email: "test@example.com",
+		Token: "test-token",
+	}, false)
+	client.SetHTTPClient(server.Client())
+	return client
+}
+
+func TestClient_UpdatePage_Success(t *testing.T) {
+	server := newUpdateTestServer(t, 4, func(w http.ResponseWriter, req updatePageRequest) {
+		if req.Version.Number != 5 {
+			t.Errorf("expected version 5, got %d", req.Version.Number)
+		}
+		if req.Version.Message != "Sync from CI" || !req.Version.MinorEdit {
+			t.Errorf("unexpected version block: %+v", req.Version)
+		}
+		if req.Title != "Runbook" {
+			t.Errorf("expected current title to be kept, got %q", req.Title)
+		}
+		if req.Body.Value != "<p>New</p>" {
+			t.Errorf("unexpected body: %q", req.Body.Value)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      req.ID,
+			"title":   req.Title,
+			"version": map[string]interface{}{"number": req.Version.Number},
+		})
+	})
+	defer server.Close()
+
+	updated, err := newTestClient(server).UpdatePage(context.Background(), "123", &PageUpdate{
+		Body:      "<p>New</p>",
+		Message:   "Sync from CI",
+		MinorEdit: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Version != 5 {
+		t.Errorf("expected version 5, got %d", updated.Version)
+	}
+	if !strings.HasSuffix(updated.URL, "pageId=123") {
+		t.Errorf("expected fallback URL, got %s", updated.URL)
+	}
+}
+
+func TestClient_UpdatePage_ExpectVersionMismatch(t *testing.T) {
+	server := newUpdateTestServer(t, 7, func(w http.ResponseWriter, req updatePageRequest) {
+		t.Error("expected no PUT request when the version does not match")
+	})
+	defer server.Close()
+
+	_, err := newTestClient(server).UpdatePage(context.Background(), "123", &PageUpdate{
+		Body:          "<p>New</p>",
+		ExpectVersion: 6,
+	})
+
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *httpclient.APIError, got %v", err)
+	}
+	if apiErr.Response.Error != httpclient.ErrTypeConflict {
+		t.Errorf("expected conflict error, got %s", apiErr.Response.Error)
+	}
+	if !strings.Contains(apiErr.Response.Message, "version 7") {
+		t.Errorf("expected message to mention current version, got %q", apiErr.Response.Message)
+	}
+}
+
+func TestClient_UpdatePage_ConcurrentEdit(t *testing.T) {
+	server := newUpdateTestServer(t, 2, func(w http.ResponseWriter, req updatePageRequest) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []map[string]interface{}{
+				{"status": 409, "title": "Version must be incremented on update. Current Version is: 3"},
+			},
+		})
+	})
+	defer server.Close()
+
+	_, err := newTestClient(server).UpdatePage(context.Background(), "123", &PageUpdate{Body: "<p>x</p>"})
+
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.Response.Error != httpclient.ErrTypeConflict {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestClient_UpdatePage_InvalidID(t *testing.T) {
+	client := NewClient(&config.Config{Site: "test.atlassian.net"}, false)
+
+	_, err := client.UpdatePage(context.Background(), "abc", &PageUpdate{})
+	if err == nil || !strings.Contains(err.Error(), "numeric") {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
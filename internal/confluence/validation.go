@@ -8,6 +8,10 @@ import (
 // pageIDPattern matches valid Confluence page IDs (numeric only).
 var pageIDPattern = regexp.MustCompile(`^[0-9]+$`)
 
+// spaceKeyPattern matches Confluence space keys: alphanumeric global keys
+// like "ENG", or personal space keys starting with "~".
+var spaceKeyPattern = regexp.MustCompile(`^(?:[A-Za-z0-9]+|~[A-Za-z0-9:_-]+)$`)
+
 // ValidatePageID validates that a string is a valid Confluence page ID.
 func ValidatePageID(id string) error {
 	if id == "" {
@@ -20,3 +24,49 @@ func ValidatePageID(id string) error {
 
 	return nil
 }
+
+// ValidateSpaceKey validates that a string is a valid Confluence space key.
+func ValidateSpaceKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("space key cannot be empty")
+	}
+
+	if !spaceKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid space key: %q (expected format: ENG)", key)
+	}
+
+	return nil
+}
+
+// labelPattern matches Confluence label names: no whitespace, and no colon
+// since that separates a label's prefix from its name.
+var labelPattern = regexp.MustCompile(`^[^\s:]+$`)
+
+// ValidateLabel validates that a string is a valid Confluence label name.
+func ValidateLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("label cannot be empty")
+	}
+
+	if !labelPattern.MatchString(label) || len(label) > 255 {
+		return fmt.Errorf("invalid label: %q (no spaces or colons, at most 255 characters)", label)
+	}
+
+	return nil
+}
+
+// propertyKeyPattern matches content property keys.
+var propertyKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ValidatePropertyKey validates that a string is a valid content property key.
+func ValidatePropertyKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("property key cannot be empty")
+	}
+
+	if !propertyKeyPattern.MatchString(key) || len(key) > 255 {
+		return fmt.Errorf("invalid property key: %q (letters, digits, '.', '_' and '-' only)", key)
+	}
+
+	return nil
+}
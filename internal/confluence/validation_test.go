@@ -34,3 +34,79 @@ func TestValidatePageID(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSpaceKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"uppercase", "ENG", false},
+		{"alphanumeric", "TEAM2", false},
+		{"lowercase", "docs", false},
+		{"personal space", "~557058:f5b2c7a0-1234", false},
+
+		{"empty", "", true},
+		{"with space", "EN G", true},
+		{"with dash", "ENG-1", true},
+		{"tilde only", "~", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSpaceKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSpaceKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		label   string
+		wantErr bool
+	}{
+		{"simple", "runbook", false},
+		{"with dashes", "owner-team-x", false},
+		{"unicode", "größe", false},
+
+		{"empty", "", true},
+		{"with space", "two words", true},
+		{"with prefix", "global:runbook", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLabel(tt.label)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLabel(%q) error = %v, wantErr %v", tt.label, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePropertyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"simple", "owner", false},
+		{"dotted", "com.acme.review-state", false},
+
+		{"empty", "", true},
+		{"with space", "review state", true},
+		{"with slash", "a/b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePropertyKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePropertyKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+}
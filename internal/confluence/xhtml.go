@@ -0,0 +1,298 @@
+package confluence
+
+import (
+	"encoding/xml"
+	"html"
+	"io"
+	"strings"
+)
+
+// storageNode is a node of a parsed storage format document. Element names
+// keep their namespace prefix ("ac:structured-macro", "ri:page"); text
+// nodes have an empty name.
+type storageNode struct {
+	name     string
+	attrs    []storageAttr
+	text     string
+	children []*storageNode
+}
+
+type storageAttr struct {
+	name  string
+	value string
+}
+
+// parseStorage parses storage format into a tree under a root node. The
+// decoder is lenient like a browser: HTML entities are understood, void
+// elements need not be closed, and mismatched end tags are repaired. CDATA
+// sections become plain text, so a "]]>" split across sections is joined.
+func parseStorage(storage string) (*storageNode, error) {
+	d := xml.NewDecoder(strings.NewReader(storage))
+	d.Strict = false
+	d.AutoClose = storageAutoClose
+	d.Entity = xml.HTMLEntity
+
+	root := &storageNode{name: "#root"}
+	stack := []*storageNode{root}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		top := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &storageNode{name: qualifiedName(t.Name)}
+			for _, a := range t.Attr {
+				node.attrs = append(node.attrs, storageAttr{name: qualifiedName(a.Name), value: a.Value})
+			}
+			top.children = append(top.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			name := qualifiedName(t.Name)
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		case xml.CharData:
+			if n := len(top.children); n > 0 && top.children[n-1].name == "" {
+				top.children[n-1].text += string(t)
+			} else {
+				top.children = append(top.children, &storageNode{text: string(t)})
+			}
+		}
+	}
+
+	return root, nil
+}
+
+// qualifiedName returns a name with its prefix, as written in the source.
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// attr returns the value of the named attribute, or "".
+func (n *storageNode) attr(name string) string {
+	for _, a := range n.attrs {
+		if a.name == name {
+			return a.value
+		}
+	}
+	return ""
+}
+
+// child returns the first direct child element with the given name.
+func (n *storageNode) child(name string) *storageNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// textContent returns the concatenated text of the node and its descendants.
+func (n *storageNode) textContent() string {
+	if n.name == "" {
+		return n.text
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(c.textContent())
+	}
+	return b.String()
+}
+
+// writeStorage writes the node's children back as storage format. Text is
+// escaped rather than wrapped in CDATA sections, which parseStorage reads
+// the same way.
+func (n *storageNode) writeStorage(b *strings.Builder) {
+	for _, c := range n.children {
+		if c.name == "" {
+			b.WriteString(html.EscapeString(c.text))
+			continue
+		}
+		b.WriteString("<" + c.name)
+		for _, a := range c.attrs {
+			b.WriteString(" " + a.name + `="` + html.EscapeString(a.value) + `"`)
+		}
+		if len(c.children) == 0 || voidElements[c.name] {
+			// Anything the decoder nested in a void element follows it
+			b.WriteString("/>")
+			c.writeStorage(b)
+			continue
+		}
+		b.WriteString(">")
+		c.writeStorage(b)
+		b.WriteString("</" + c.name + ">")
+	}
+}
+
+// isConfluence reports whether the node is a Confluence ac: or ri: element.
+func (n *storageNode) isConfluence() bool {
+	return strings.HasPrefix(n.name, "ac:") || strings.HasPrefix(n.name, "ri:")
+}
+
+// storageAutoClose lists the void elements the decoder closes by itself.
+// The decoder matches local names only, so xml.HTMLAutoClose cannot be used:
+// its "link" would also close every ac:link.
+var storageAutoClose = []string{"br", "hr", "img", "col", "wbr", "area"}
+
+// voidElements are HTML elements written without an end tag.
+var voidElements = map[string]bool{
+	"br": true, "hr": true, "img": true, "col": true, "wbr": true, "area": true,
+}
+
+// storageRenderer renders a parsed storage document as plain HTML. refs may
+// be nil, in which case references are rendered without resolving them.
+type storageRenderer struct {
+	refs *References
+}
+
+// renderChildren renders the children of n as HTML.
+func (r *storageRenderer) renderChildren(b *strings.Builder, n *storageNode) {
+	for _, c := range n.children {
+		r.render(b, c)
+	}
+}
+
+// render renders a node as plain HTML, converting Confluence elements into
+// HTML the Markdown converter understands.
+func (r *storageRenderer) render(b *strings.Builder, n *storageNode) {
+	switch {
+	case n.name == "":
+		b.WriteString(html.EscapeString(n.text))
+	case n.isConfluence():
+		r.renderConfluenceElement(b, n)
+	default:
+		b.WriteString("<" + n.name)
+		for _, a := range n.attrs {
+			b.WriteString(" " + a.name + `="` + html.EscapeString(a.value) + `"`)
+		}
+		if voidElements[n.name] {
+			// Anything the decoder nested in a void element follows it
+			b.WriteString("/>")
+			r.renderChildren(b, n)
+			return
+		}
+		b.WriteString(">")
+		r.renderChildren(b, n)
+		b.WriteString("</" + n.name + ">")
+	}
+}
+
+// renderConfluenceElement renders an ac: or ri: element.
+func (r *storageRenderer) renderConfluenceElement(b *strings.Builder, n *storageNode) {
+	switch n.name {
+	case "ac:structured-macro", "ac:macro":
+		macro := r.newMacro(n)
+		handler, ok := MacroHandlers[macro.Name]
+		if !ok {
+			handler = placeholderMacro
+		}
+		b.WriteString(handler(macro))
+	case "ac:link":
+		r.renderLink(b, n)
+	case "ac:image":
+		r.renderImage(b, n)
+	case "ac:task-list":
+		r.renderTaskList(b, n)
+	case "ac:parameter", "ac:placeholder":
+		// Parameters belong to their macro and placeholders are editor hints
+	default:
+		if strings.HasPrefix(n.name, "ri:") {
+			// Resource identifiers carry no text of their own
+			return
+		}
+		// Layouts and other containers keep their content
+		r.renderChildren(b, n)
+	}
+}
+
+// renderLink renders an ac:link as a user mention, an attachment link or
+// its link text.
+func (r *storageRenderer) renderLink(b *strings.Builder, n *storageNode) {
+	if user := n.child("ri:user"); user != nil {
+		b.WriteString(html.EscapeString("@" + r.refs.userName(user)))
+		return
+	}
+
+	var text strings.Builder
+	if body := n.child("ac:plain-text-link-body"); body != nil {
+		text.WriteString(html.EscapeString(body.textContent()))
+	} else if body := n.child("ac:link-body"); body != nil {
+		r.renderChildren(&text, body)
+	}
+
+	if att := n.child("ri:attachment"); att != nil {
+		filename := att.attr("ri:filename")
+		if text.Len() == 0 {
+			text.WriteString(html.EscapeString(filename))
+		}
+		b.WriteString(`<a href="` + html.EscapeString(r.refs.attachmentURL(att)) + `">` + text.String() + "</a>")
+		return
+	}
+
+	switch page := n.child("ri:page"); {
+	case text.Len() > 0:
+		b.WriteString(text.String())
+	case page != nil && page.attr("ri:content-title") != "":
+		b.WriteString(html.EscapeString(page.attr("ri:content-title")))
+	default:
+		b.WriteString("CFPLACEHOLDER:LINK::")
+	}
+}
+
+// renderImage renders an ac:image as an img element pointing at the
+// attachment download URL or the external image.
+func (r *storageRenderer) renderImage(b *strings.Builder, n *storageNode) {
+	var src, name string
+	if att := n.child("ri:attachment"); att != nil {
+		src, name = r.refs.attachmentURL(att), att.attr("ri:filename")
+	} else if u := n.child("ri:url"); u != nil {
+		src = u.attr("ri:value")
+	}
+	if src == "" {
+		return
+	}
+
+	alt := n.attr("ac:alt")
+	if alt == "" {
+		alt = name
+	}
+	b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(alt) + `"`)
+	if title := n.attr("ac:title"); title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	b.WriteString("/>")
+}
+
+// renderTaskList renders an ac:task-list as a list of GFM checkboxes.
+func (r *storageRenderer) renderTaskList(b *strings.Builder, n *storageNode) {
+	b.WriteString("<ul>")
+	for _, task := range n.children {
+		if task.name != "ac:task" {
+			continue
+		}
+		b.WriteString("<li>")
+		if status := task.child("ac:task-status"); status != nil && strings.TrimSpace(status.textContent()) == "complete" {
+			b.WriteString("CFPLACEHOLDER:TASKDONE: ")
+		} else {
+			b.WriteString("CFPLACEHOLDER:TASKOPEN: ")
+		}
+		if body := task.child("ac:task-body"); body != nil {
+			r.renderChildren(b, body)
+		}
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+}
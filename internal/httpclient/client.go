@@ -17,10 +17,13 @@ const (
 
 // Client is an HTTP client configured for Atlassian API requests.
 type Client struct {
-	http  *http.Client
-	email string
-	token string
-	debug bool
+	http *http.Client
+	// transfer is used for file transfers, which may take longer than
+	// DefaultTimeout in total.
+	transfer *http.Client
+	email    string
+	token    string
+	debug    bool
 }
 
 // New creates a new HTTP client with the given credentials.
@@ -29,12 +32,23 @@ func New(email, token string, debug bool) *Client {
 		http: &http.Client{
 			Timeout: DefaultTimeout,
 		},
-		email: email,
-		token: token,
-		debug: debug,
+		transfer: newTransferClient(),
+		email:    email,
+		token:    token,
+		debug:    debug,
 	}
 }
 
+// newTransferClient returns a client without a total timeout, since that
+// would include streaming the body. Connecting, the TLS handshake and
+// waiting for the response headers are still bounded, and the request
+// context can cancel a transfer at any point.
+func newTransferClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = DefaultTimeout
+	return &http.Client{Transport: transport}
+}
+
 // NewRequest creates a new HTTP request with authentication headers.
 // Returns an error if the URL is not HTTPS.
 func (c *Client) NewRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
@@ -61,6 +75,17 @@ func (c *Client) NewRequest(ctx context.Context, method, url string, body io.Rea
 // Do executes an HTTP request and returns the response.
 // Debug output is written to stderr if debug mode is enabled.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.do(c.http, req)
+}
+
+// DoTransfer executes a request that uploads or downloads a file. Unlike
+// Do, the request has no total timeout, so large files are not cut off
+// mid-stream; use the request context to bound it.
+func (c *Client) DoTransfer(req *http.Request) (*http.Response, error) {
+	return c.do(c.transfer, req)
+}
+
+func (c *Client) do(client *http.Client, req *http.Request) (*http.Response, error) {
 	// Ensure auth is set (in case request was created externally)
 	if req.Header.Get("Authorization") == "" {
 		req.SetBasicAuth(c.email, c.token)
@@ -71,7 +96,7 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 		DebugRequest(req)
 	}
 
-	resp, err := c.http.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		if c.debug {
 			DebugError(err)
@@ -92,7 +117,11 @@ func (c *Client) IsDebug() bool {
 	return c.debug
 }
 
-// SetHTTPClient sets the underlying HTTP client (for testing).
+// SetHTTPClient sets the underlying HTTP client (for testing). File
+// transfers use a copy of it without the total timeout.
 func (c *Client) SetHTTPClient(httpClient *http.Client) {
 	c.http = httpClient
+	transfer := *httpClient
+	transfer.Timeout = 0
+	c.transfer = &transfer
 }
@@ -3,6 +3,7 @@ package httpclient
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -57,6 +58,53 @@ func TestClient_Do_Timeout(t *testing.T) {
 	}
 }
 
+func TestClient_DoTransfer_NoTotalTimeout(t *testing.T) {
+	// The body takes longer to stream than the client's total timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 4; i++ {
+			w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{}
+	client.SetHTTPClient(&http.Client{Timeout: 100 * time.Millisecond})
+
+	req, _ := http.NewRequest("GET", server.URL+"/file", nil)
+	resp, err := client.DoTransfer(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || len(data) != 20 {
+		t.Errorf("expected the whole body, got %d bytes, %v", len(data), err)
+	}
+
+	req, _ = http.NewRequest("GET", server.URL+"/file", nil)
+	resp, err = client.Do(req)
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	if err == nil {
+		t.Error("expected Do to time out while reading the body")
+	}
+}
+
+func TestNewTransferClient(t *testing.T) {
+	client := newTransferClient()
+	if client.Timeout != 0 {
+		t.Errorf("expected no total timeout, got %v", client.Timeout)
+	}
+	if transport := client.Transport.(*http.Transport); transport.ResponseHeaderTimeout != DefaultTimeout {
+		t.Errorf("expected a response header timeout of %v, got %v", DefaultTimeout, transport.ResponseHeaderTimeout)
+	}
+}
+
 func TestClient_Do_AuthError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -15,6 +15,7 @@ const (
 	ErrTypeAuth       = "auth_error"
 	ErrTypePermission = "permission_error"
 	ErrTypeNotFound   = "not_found"
+	ErrTypeConflict   = "conflict"
 	ErrTypeRateLimit  = "rate_limit"
 	ErrTypeTimeout    = "timeout"
 	ErrTypeServer     = "server_error"
@@ -26,6 +27,7 @@ type ErrorResponse struct {
 	Error      string `json:"error"`
 	Message    string `json:"message"`
 	RetryAfter int    `json:"retryAfter,omitempty"` // Only for rate_limit errors
+	Path       string `json:"path,omitempty"`       // JSON pointer into an invalid document
 }
 
 // NewErrorResponse creates an ErrorResponse from an HTTP response.
@@ -90,6 +92,14 @@ func NewValidationError(message string) *ErrorResponse {
 	}
 }
 
+// NewConflictError creates a conflict error response.
+func NewConflictError(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Error:   ErrTypeConflict,
+		Message: message,
+	}
+}
+
 // NewTimeoutError creates a timeout error response.
 func NewTimeoutError() *ErrorResponse {
 	return &ErrorResponse{
@@ -110,6 +120,16 @@ func (e *ErrorResponse) String() string {
 	return fmt.Sprintf("%s: %s", e.Error, e.Message)
 }
 
+// APIError is a Go error carrying a structured ErrorResponse, so callers can
+// report the original error type instead of a flattened message.
+type APIError struct {
+	Response *ErrorResponse
+}
+
+func (e *APIError) Error() string {
+	return e.Response.String()
+}
+
 func mapStatusToErrorType(status int) string {
 	switch status {
 	case http.StatusUnauthorized:
@@ -118,6 +138,8 @@ func mapStatusToErrorType(status int) string {
 		return ErrTypePermission
 	case http.StatusNotFound:
 		return ErrTypeNotFound
+	case http.StatusConflict:
+		return ErrTypeConflict
 	case http.StatusTooManyRequests:
 		return ErrTypeRateLimit
 	default:
@@ -136,6 +158,8 @@ func defaultMessage(errType string) string {
 		return "Access denied - check your permissions"
 	case ErrTypeNotFound:
 		return "Resource not found"
+	case ErrTypeConflict:
+		return "Conflict - the resource was modified by someone else"
 	case ErrTypeRateLimit:
 		return "Rate limit exceeded"
 	case ErrTypeServer:
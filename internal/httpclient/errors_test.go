@@ -0,0 +1,57 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewErrorResponse_StatusMapping(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected string
+	}{
+		{http.StatusUnauthorized, ErrTypeAuth},
+		{http.StatusForbidden, ErrTypePermission},
+		{http.StatusNotFound, ErrTypeNotFound},
+		{http.StatusConflict, ErrTypeConflict},
+		{http.StatusTooManyRequests, ErrTypeRateLimit},
+		{http.StatusBadGateway, ErrTypeServer},
+		{http.StatusBadRequest, ErrTypeUnknown},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		if got := NewErrorResponse(resp).Error; got != tt.expected {
+			t.Errorf("status %d: expected %s, got %s", tt.status, tt.expected, got)
+		}
+	}
+}
+
+func TestNewErrorResponse_ConfluenceMessage(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusConflict,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(`{"errors":[{"title":"Version must be incremented"}]}`)),
+	}
+
+	errResp := NewErrorResponse(resp)
+	if errResp.Message != "Version must be incremented" {
+		t.Errorf("unexpected message: %q", errResp.Message)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	var err error = &APIError{Response: NewConflictError("page changed")}
+
+	if err.Error() != "conflict: page changed" {
+		t.Errorf("unexpected error string: %q", err.Error())
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Response.Error != ErrTypeConflict {
+		t.Errorf("expected to unwrap conflict APIError, got %v", err)
+	}
+}
@@ -0,0 +1,326 @@
+package jira
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// adfSchemaJSON is a hand-maintained condensation of the published ADF
+// schema (@atlaskit/adf-schema, dist/json-schema/v1/full.json), reduced to
+// the node, attribute and mark rules that the validator checks. It is not
+// generated; TestADFSchemaMatchesUpstream compares it with a copy of the
+// published schema.
+//
+//go:embed adf_schema.json
+var adfSchemaJSON []byte
+
+// adfAttrSpec describes the constraints on a single node or mark attribute.
+type adfAttrSpec struct {
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Enum     []string `json:"enum"`
+	Minimum  *float64 `json:"minimum"`
+	Maximum  *float64 `json:"maximum"`
+}
+
+// adfNodeSpec describes what a node type may contain.
+type adfNodeSpec struct {
+	Content    []string               `json:"content"`
+	MinContent int                    `json:"minContent"`
+	MaxContent int                    `json:"maxContent"`
+	Attrs      map[string]adfAttrSpec `json:"attrs"`
+	Marks      []string               `json:"marks"`
+	TextMarks  []string               `json:"textMarks"` // nil means text children keep their default marks
+	Text       bool                   `json:"text"`
+}
+
+// adfMarkSpec describes a mark type and which marks it may be combined with.
+type adfMarkSpec struct {
+	Attrs       map[string]adfAttrSpec `json:"attrs"`
+	AllowedWith []string               `json:"allowedWith"` // nil means any combination is allowed
+}
+
+// adfSchema is the parsed form of adf_schema.json.
+type adfSchema struct {
+	Groups map[string][]string    `json:"groups"`
+	Nodes  map[string]adfNodeSpec `json:"nodes"`
+	Marks  map[string]adfMarkSpec `json:"marks"`
+}
+
+var adfSchemaDef = mustLoadADFSchema()
+
+func mustLoadADFSchema() *adfSchema {
+	var schema adfSchema
+	if err := json.Unmarshal(adfSchemaJSON, &schema); err != nil {
+		panic(fmt.Sprintf("invalid embedded ADF schema: %v", err))
+	}
+	return &schema
+}
+
+// ADFValidationError reports the first part of an ADF document that does not
+// conform to the schema.
+type ADFValidationError struct {
+	Path    string // JSON pointer to the offending node, mark or attribute
+	Message string
+}
+
+func (e *ADFValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("invalid ADF at %s: %s", path, e.Message)
+}
+
+// ValidateADF checks an ADF document against the embedded ADF schema.
+// It returns an *ADFValidationError describing the first violation found,
+// or nil if the document is valid. A nil document is considered valid.
+func ValidateADF(doc *ADFDoc) error {
+	if doc == nil {
+		return nil
+	}
+	if doc.Type != "doc" {
+		return adfError("/type", "root node must be of type doc, got %q", doc.Type)
+	}
+	if doc.Version != 1 {
+		return adfError("/version", "unsupported version %d (expected 1)", doc.Version)
+	}
+	root := ADFNode{Type: "doc", Content: doc.Content}
+	return validateADFContent(root, adfSchemaDef.Nodes["doc"], "")
+}
+
+// validateADFNode validates a single node and its descendants.
+// textMarks restricts the marks allowed on text nodes when non-nil.
+func validateADFNode(node ADFNode, path string, textMarks []string) error {
+	spec, ok := adfSchemaDef.Nodes[node.Type]
+	if !ok || node.Type == "doc" {
+		return adfError(path+"/type", "unknown node type %q", node.Type)
+	}
+
+	if err := validateADFAttrs(node.Type+" node", node.Attrs, spec.Attrs, path+"/attrs"); err != nil {
+		return err
+	}
+
+	if spec.Text && node.Text == "" {
+		return adfError(path+"/text", "text node must have non-empty text")
+	}
+	if !spec.Text && node.Text != "" {
+		return adfError(path+"/text", "%s node cannot have text", node.Type)
+	}
+
+	allowedMarks := spec.Marks
+	if node.Type == "text" && textMarks != nil {
+		allowedMarks = textMarks
+	}
+	if err := validateADFMarks(node, allowedMarks, path+"/marks"); err != nil {
+		return err
+	}
+
+	return validateADFContent(node, spec, path)
+}
+
+// validateADFContent checks the children of node against spec.
+func validateADFContent(node ADFNode, spec adfNodeSpec, path string) error {
+	if len(spec.Content) == 0 {
+		if len(node.Content) > 0 {
+			return adfError(path+"/content", "%s node cannot have content", node.Type)
+		}
+		return nil
+	}
+	if len(node.Content) < spec.MinContent {
+		return adfError(path+"/content", "%s node requires at least %d child node(s)", node.Type, spec.MinContent)
+	}
+	if spec.MaxContent > 0 && len(node.Content) > spec.MaxContent {
+		return adfError(path+"/content", "%s node allows at most %d child node(s)", node.Type, spec.MaxContent)
+	}
+
+	allowed := expandADFContent(spec.Content)
+	for i, child := range node.Content {
+		childPath := path + "/content/" + strconv.Itoa(i)
+		if _, known := adfSchemaDef.Nodes[child.Type]; known && !allowed[child.Type] {
+			return adfError(childPath, "%s node is not allowed inside %s", child.Type, node.Type)
+		}
+		if err := validateADFNode(child, childPath, spec.TextMarks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ADFAllowsChild reports whether the ADF schema lets a node of type parent
+// contain a node of type child.
+func ADFAllowsChild(parent, child string) bool {
+	spec, ok := adfSchemaDef.Nodes[parent]
+	return ok && expandADFContent(spec.Content)[child]
+}
+
+// expandADFContent resolves @group references into a set of node types.
+func expandADFContent(content []string) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, entry := range content {
+		if group, ok := strings.CutPrefix(entry, "@"); ok {
+			for _, name := range adfSchemaDef.Groups[group] {
+				allowed[name] = true
+			}
+			continue
+		}
+		allowed[entry] = true
+	}
+	return allowed
+}
+
+// validateADFMarks checks mark types, attributes and combinations on a node.
+func validateADFMarks(node ADFNode, allowed []string, path string) error {
+	seen := make(map[string]bool, len(node.Marks))
+	for i, mark := range node.Marks {
+		markPath := path + "/" + strconv.Itoa(i)
+		spec, ok := adfSchemaDef.Marks[mark.Type]
+		if !ok {
+			return adfError(markPath+"/type", "unknown mark type %q", mark.Type)
+		}
+		if !containsString(allowed, mark.Type) {
+			return adfError(markPath, "%s mark is not allowed on %s node", mark.Type, node.Type)
+		}
+		if seen[mark.Type] && mark.Type != "annotation" {
+			return adfError(markPath, "duplicate %s mark", mark.Type)
+		}
+		seen[mark.Type] = true
+		if err := validateADFAttrs(mark.Type+" mark", mark.Attrs, spec.Attrs, markPath+"/attrs"); err != nil {
+			return err
+		}
+	}
+
+	// Check combinations once every mark is known to be valid on its own.
+	for i, mark := range node.Marks {
+		spec := adfSchemaDef.Marks[mark.Type]
+		if spec.AllowedWith == nil {
+			continue
+		}
+		for _, other := range node.Marks {
+			if other.Type != mark.Type && !containsString(spec.AllowedWith, other.Type) {
+				return adfError(path+"/"+strconv.Itoa(i), "%s mark cannot be combined with %s mark", mark.Type, other.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// validateADFAttrs checks attribute values against their specs.
+// owner names the node or mark in error messages.
+func validateADFAttrs(owner string, attrs map[string]interface{}, specs map[string]adfAttrSpec, path string) error {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		spec, ok := specs[key]
+		if !ok {
+			return adfError(path+"/"+key, "unknown attribute %q for %s", key, owner)
+		}
+		if msg := checkADFAttrValue(spec, attrs[key]); msg != "" {
+			return adfError(path+"/"+key, "attribute %q of %s %s", key, owner, msg)
+		}
+	}
+
+	required := make([]string, 0, len(specs))
+	for key, spec := range specs {
+		if spec.Required {
+			required = append(required, key)
+		}
+	}
+	sort.Strings(required)
+	for _, key := range required {
+		if _, ok := attrs[key]; !ok {
+			return adfError(path, "%s is missing required attribute %q", owner, key)
+		}
+	}
+	return nil
+}
+
+// checkADFAttrValue returns a description of why value violates spec,
+// or an empty string if it is valid.
+func checkADFAttrValue(spec adfAttrSpec, value interface{}) string {
+	switch spec.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if len(spec.Enum) > 0 && !containsString(spec.Enum, s) {
+			return fmt.Sprintf("must be one of %s", strings.Join(quoteAll(spec.Enum), ", "))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	case "integer", "number":
+		n, ok := adfNumber(value)
+		if !ok {
+			return "must be a number"
+		}
+		if spec.Type == "integer" && n != math.Trunc(n) {
+			return "must be an integer"
+		}
+		if spec.Minimum != nil && n < *spec.Minimum {
+			return fmt.Sprintf("must be at least %g", *spec.Minimum)
+		}
+		if spec.Maximum != nil && n > *spec.Maximum {
+			return fmt.Sprintf("must be at most %g", *spec.Maximum)
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return "must be an object"
+		}
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			return "must be an array"
+		}
+	}
+	return ""
+}
+
+// adfNumber converts numeric attribute values, which are Go ints when built
+// by this package and float64 when decoded from JSON.
+func adfNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func adfError(path, format string, args ...interface{}) *ADFValidationError {
+	return &ADFValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(list []string) []string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = strconv.Quote(s)
+	}
+	return quoted
+}
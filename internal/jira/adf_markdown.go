@@ -0,0 +1,345 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adfPanelLabels maps ADF panel types to the labels used when rendering them
+// as Markdown blockquotes.
+var adfPanelLabels = map[string]string{
+	"info":    "Info",
+	"note":    "Note",
+	"tip":     "Tip",
+	"warning": "Warning",
+	"error":   "Error",
+	"success": "Success",
+	"custom":  "Note",
+}
+
+// ADFToMarkdown renders an Atlassian Document Format document as Markdown.
+// The second return value lists content that could not be represented in
+// Markdown and was simplified or dropped.
+func ADFToMarkdown(doc *ADFDoc) (string, []string) {
+	if doc == nil {
+		return "", nil
+	}
+	r := &adfMarkdownRenderer{seen: make(map[string]bool)}
+	return strings.TrimSpace(r.blocks(doc.Content)), r.warnings
+}
+
+// adfMarkdownRenderer accumulates warnings while rendering a document.
+type adfMarkdownRenderer struct {
+	warnings []string
+	seen     map[string]bool
+}
+
+// warn records a lossy conversion once per distinct message.
+func (r *adfMarkdownRenderer) warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if r.seen[msg] {
+		return
+	}
+	r.seen[msg] = true
+	r.warnings = append(r.warnings, msg)
+}
+
+// blocks renders a sequence of block nodes separated by blank lines.
+func (r *adfMarkdownRenderer) blocks(nodes []ADFNode) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if s := r.block(node); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (r *adfMarkdownRenderer) block(node ADFNode) string {
+	switch node.Type {
+	case "paragraph":
+		return r.inline(node.Content)
+	case "heading":
+		level := 1
+		if n, ok := adfNumber(node.Attrs["level"]); ok && n >= 1 && n <= 6 {
+			level = int(n)
+		}
+		return strings.Repeat("#", level) + " " + r.inline(node.Content)
+	case "bulletList":
+		return r.list(node.Content, func(int) string { return "- " })
+	case "orderedList":
+		start := 1
+		if n, ok := adfNumber(node.Attrs["order"]); ok {
+			start = int(n)
+		}
+		return r.list(node.Content, func(i int) string { return strconv.Itoa(start+i) + ". " })
+	case "taskList":
+		return r.taskList(node.Content)
+	case "decisionList":
+		return r.list(node.Content, func(int) string { return "- " })
+	case "codeBlock":
+		lang, _ := node.Attrs["language"].(string)
+		return "```" + lang + "\n" + adfPlainText(node.Content) + "\n```"
+	case "blockquote":
+		return quoteMarkdown(r.blocks(node.Content))
+	case "rule":
+		return "---"
+	case "panel":
+		panelType, _ := node.Attrs["panelType"].(string)
+		label, ok := adfPanelLabels[panelType]
+		if !ok {
+			label = "Note"
+		}
+		return quoteMarkdown("**" + label + ":** " + r.blocks(node.Content))
+	case "expand", "nestedExpand":
+		title, _ := node.Attrs["title"].(string)
+		if title == "" {
+			title = "Expand"
+		}
+		return "<details>\n<summary>" + title + "</summary>\n\n" + r.blocks(node.Content) + "\n</details>"
+	case "table":
+		return r.table(node)
+	case "mediaSingle", "mediaGroup":
+		return r.blocks(node.Content)
+	case "caption":
+		return r.inline(node.Content)
+	case "media":
+		return r.media(node)
+	case "blockCard", "embedCard":
+		if url, ok := node.Attrs["url"].(string); ok && url != "" {
+			return "<" + url + ">"
+		}
+		r.warn("%s without a URL was dropped", node.Type)
+		return ""
+	case "layoutSection", "layoutColumn":
+		r.warn("column layout was flattened")
+		return r.blocks(node.Content)
+	case "extension", "bodiedExtension":
+		key, _ := node.Attrs["extensionKey"].(string)
+		r.warn("extension %q was not rendered", key)
+		if len(node.Content) > 0 {
+			return r.blocks(node.Content)
+		}
+		return "[Extension: " + key + "]"
+	default:
+		r.warn("unsupported node type %q was simplified", node.Type)
+		if len(node.Content) > 0 {
+			return r.blocks(node.Content)
+		}
+		return r.inline([]ADFNode{node})
+	}
+}
+
+// list renders list items with the marker returned by marker(i). Content
+// after the first line of an item is indented to align with the marker.
+func (r *adfMarkdownRenderer) list(items []ADFNode, marker func(int) string) string {
+	lines := make([]string, 0, len(items))
+	for i, item := range items {
+		var body string
+		if item.Type == "decisionItem" {
+			body = r.inline(item.Content)
+		} else {
+			parts := make([]string, 0, len(item.Content))
+			for _, child := range item.Content {
+				if s := r.block(child); s != "" {
+					parts = append(parts, s)
+				}
+			}
+			body = strings.Join(parts, "\n")
+		}
+		m := marker(i)
+		lines = append(lines, m+indentMarkdown(body, strings.Repeat(" ", len(m))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// taskList renders task items as GFM checkboxes.
+func (r *adfMarkdownRenderer) taskList(items []ADFNode) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Type == "taskList" {
+			lines = append(lines, indentMarkdown("  "+r.taskList(item.Content), "  "))
+			continue
+		}
+		box := "[ ]"
+		if state, _ := item.Attrs["state"].(string); state == "DONE" {
+			box = "[x]"
+		}
+		lines = append(lines, "- "+box+" "+r.inline(item.Content))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// table renders a GFM table. The first row is always used as the header.
+func (r *adfMarkdownRenderer) table(node ADFNode) string {
+	var rows [][]string
+	width := 0
+	for _, row := range node.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			text := strings.ReplaceAll(r.blocks(cell.Content), "\n\n", " ")
+			text = strings.ReplaceAll(text, "\n", " ")
+			cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+		}
+		if len(cells) > width {
+			width = len(cells)
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, cells := range rows {
+		for len(cells) < width {
+			cells = append(cells, "")
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |")
+		if i == 0 {
+			b.WriteString("\n|" + strings.Repeat(" --- |", width))
+		}
+		if i < len(rows)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// media renders external images; attachments cannot be addressed offline.
+func (r *adfMarkdownRenderer) media(node ADFNode) string {
+	alt, _ := node.Attrs["alt"].(string)
+	if url, ok := node.Attrs["url"].(string); ok && url != "" {
+		return "![" + alt + "](" + url + ")"
+	}
+	r.warn("media attachments were replaced with placeholders")
+	return "[Media]"
+}
+
+// inline renders inline nodes as a single Markdown string.
+func (r *adfMarkdownRenderer) inline(nodes []ADFNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			b.WriteString(r.markedText(node))
+		case "hardBreak":
+			b.WriteString("  \n")
+		case "mention":
+			text, _ := node.Attrs["text"].(string)
+			if text == "" {
+				text, _ = node.Attrs["id"].(string)
+			}
+			if !strings.HasPrefix(text, "@") {
+				text = "@" + text
+			}
+			b.WriteString(text)
+		case "emoji":
+			if text, ok := node.Attrs["text"].(string); ok && text != "" {
+				b.WriteString(text)
+			} else {
+				shortName, _ := node.Attrs["shortName"].(string)
+				b.WriteString(shortName)
+			}
+		case "date":
+			b.WriteString(adfDate(node.Attrs["timestamp"]))
+		case "status":
+			text, _ := node.Attrs["text"].(string)
+			color, _ := node.Attrs["color"].(string)
+			b.WriteString("[STATUS: " + strings.TrimSpace(color+" "+text) + "]")
+		case "inlineCard":
+			if url, ok := node.Attrs["url"].(string); ok && url != "" {
+				b.WriteString("<" + url + ">")
+			} else {
+				r.warn("inline card without a URL was dropped")
+			}
+		case "placeholder":
+			text, _ := node.Attrs["text"].(string)
+			b.WriteString(text)
+		case "mediaInline":
+			b.WriteString(r.media(node))
+		case "inlineExtension":
+			key, _ := node.Attrs["extensionKey"].(string)
+			r.warn("extension %q was not rendered", key)
+			b.WriteString("[Extension: " + key + "]")
+		default:
+			r.warn("unsupported inline node %q was dropped", node.Type)
+		}
+	}
+	return b.String()
+}
+
+// markedText wraps a text node in the Markdown syntax for its marks.
+func (r *adfMarkdownRenderer) markedText(node ADFNode) string {
+	text := node.Text
+	var href string
+	for _, mark := range node.Marks {
+		switch mark.Type {
+		case "code":
+			text = "`" + text + "`"
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			href, _ = mark.Attrs["href"].(string)
+		default:
+			r.warn("%s formatting is not representable in Markdown", mark.Type)
+		}
+	}
+	if href != "" {
+		text = "[" + text + "](" + href + ")"
+	}
+	return text
+}
+
+// adfPlainText concatenates the text of inline nodes without formatting.
+func adfPlainText(nodes []ADFNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		if node.Type == "hardBreak" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(node.Text)
+	}
+	return b.String()
+}
+
+// adfDate formats an ADF date timestamp (milliseconds since the epoch).
+func adfDate(value interface{}) string {
+	s, _ := value.(string)
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return s
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
+
+// quoteMarkdown prefixes every line with a blockquote marker.
+func quoteMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentMarkdown indents every line after the first.
+func indentMarkdown(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,126 @@
+package jira
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestADFToMarkdown_Nil(t *testing.T) {
+	markdown, warnings := ADFToMarkdown(nil)
+	if markdown != "" || len(warnings) != 0 {
+		t.Errorf("expected empty output, got %q %v", markdown, warnings)
+	}
+}
+
+func TestADFToMarkdown_RoundTrip(t *testing.T) {
+	inputs := []string{
+		"# Title",
+		"Some **bold**, *italic* and `code` text.",
+		"See [the docs](https://example.com).",
+		"- one\n- two",
+		"1. first\n2. second",
+		"```go\nfmt.Println(\"hi\")\n```",
+		"First paragraph\n\nSecond paragraph",
+	}
+
+	for _, input := range inputs {
+		markdown, warnings := ADFToMarkdown(TextToADF(input))
+		if markdown != input {
+			t.Errorf("round trip mismatch:\ninput:  %q\noutput: %q", input, markdown)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("unexpected warnings for %q: %v", input, warnings)
+		}
+	}
+}
+
+func TestADFToMarkdown_Nodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected string
+	}{
+		{
+			name:     "panel",
+			json:     `{"type":"panel","attrs":{"panelType":"warning"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Careful"}]}]}`,
+			expected: "> **Warning:** Careful",
+		},
+		{
+			name:     "expand",
+			json:     `{"type":"expand","attrs":{"title":"More"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Hidden"}]}]}`,
+			expected: "<details>\n<summary>More</summary>\n\nHidden\n</details>",
+		},
+		{
+			name:     "task list",
+			json:     `{"type":"taskList","attrs":{"localId":"a"},"content":[{"type":"taskItem","attrs":{"localId":"b","state":"DONE"},"content":[{"type":"text","text":"Done"}]},{"type":"taskItem","attrs":{"localId":"c","state":"TODO"},"content":[{"type":"text","text":"Open"}]}]}`,
+			expected: "- [x] Done\n- [ ] Open",
+		},
+		{
+			name:     "nested list",
+			json:     `{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]},{"type":"orderedList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}]}]}]}`,
+			expected: "- a\n  1. b",
+		},
+		{
+			name:     "table",
+			json:     `{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"A"}]}]},{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"B"}]}]}]},{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1|2"}]}]},{"type":"tableCell","content":[]}]}]}`,
+			expected: "| A | B |\n| --- | --- |\n| 1\\|2 |  |",
+		},
+		{
+			name:     "inline nodes",
+			json:     `{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"1","text":"@Jane"}},{"type":"text","text":" "},{"type":"status","attrs":{"text":"DONE","color":"green"}},{"type":"text","text":" "},{"type":"date","attrs":{"timestamp":"1700000000000"}}]}`,
+			expected: "@Jane [STATUS: green DONE] 2023-11-14",
+		},
+		{
+			name:     "media with caption",
+			json:     `{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"external","url":"https://example.com/a.png","alt":"A"}},{"type":"caption","content":[{"type":"text","text":"Architecture"}]}]}`,
+			expected: "![A](https://example.com/a.png)\n\nArchitecture",
+		},
+		{
+			name:     "inline media",
+			json:     `{"type":"paragraph","content":[{"type":"text","text":"See "},{"type":"mediaInline","attrs":{"id":"f1","collection":"c"}}]}`,
+			expected: "See [Media]",
+		},
+		{
+			name:     "blockquote",
+			json:     `{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"One"}]},{"type":"paragraph","content":[{"type":"text","text":"Two"}]}]}`,
+			expected: "> One\n>\n> Two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node ADFNode
+			if err := json.Unmarshal([]byte(tt.json), &node); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			markdown, _ := ADFToMarkdown(&ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{node}})
+			if markdown != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, markdown)
+			}
+		})
+	}
+}
+
+func TestADFToMarkdown_Warnings(t *testing.T) {
+	doc := &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+		makeParagraph([]ADFNode{
+			makeMarkedText("under", []ADFMark{{Type: "underline"}}),
+			makeMarkedText("lined", []ADFMark{{Type: "underline"}}),
+		}),
+		{Type: "mediaSingle", Content: []ADFNode{
+			{Type: "media", Attrs: map[string]interface{}{"type": "file", "id": "abc", "collection": "x"}},
+		}},
+	}}
+
+	markdown, warnings := ADFToMarkdown(doc)
+	if !strings.Contains(markdown, "underlined") || !strings.Contains(markdown, "[Media]") {
+		t.Errorf("unexpected markdown: %q", markdown)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 deduplicated warnings, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], "underline") {
+		t.Errorf("expected underline warning, got %q", warnings[0])
+	}
+}
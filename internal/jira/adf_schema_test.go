@@ -0,0 +1,205 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// upstreamADFSchemaFile is the vendored copy of the published ADF schema
+// (@atlaskit/adf-schema, dist/json-schema/v1/full.json) that
+// adf_schema.json is checked against.
+const upstreamADFSchemaFile = "testdata/adf-schema-full.json"
+
+// envUpstreamADFSchema names another copy of the published schema to check
+// against instead, such as a newer release.
+const envUpstreamADFSchema = "ADF_UPSTREAM_SCHEMA"
+
+// upstreamADFSchema is the part of the published JSON schema needed to
+// find which node types each node may contain.
+type upstreamADFSchema struct {
+	Definitions map[string]json.RawMessage `json:"definitions"`
+}
+
+// upstreamADFDef is a JSON schema definition. Only the keywords that lead
+// to node types are decoded.
+type upstreamADFDef struct {
+	Ref        string            `json:"$ref"`
+	AnyOf      []json.RawMessage `json:"anyOf"`
+	OneOf      []json.RawMessage `json:"oneOf"`
+	AllOf      []json.RawMessage `json:"allOf"`
+	Items      json.RawMessage   `json:"items"`
+	Properties struct {
+		Type struct {
+			Enum []string `json:"enum"`
+		} `json:"type"`
+		Content json.RawMessage `json:"content"`
+	} `json:"properties"`
+}
+
+// upstreamTypes returns the node or mark types a definition accepts,
+// following references, combinators and array items.
+func (s *upstreamADFSchema) upstreamTypes(raw json.RawMessage, seen map[string]bool) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	if raw[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil
+		}
+		var types []string
+		for _, item := range items {
+			types = append(types, s.upstreamTypes(item, seen)...)
+		}
+		return types
+	}
+
+	var def upstreamADFDef
+	if err := json.Unmarshal(raw, &def); err != nil {
+		return nil
+	}
+	if def.Ref != "" {
+		name := strings.TrimPrefix(def.Ref, "#/definitions/")
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+		return s.upstreamTypes(s.Definitions[name], seen)
+	}
+	if len(def.Properties.Type.Enum) > 0 {
+		return def.Properties.Type.Enum
+	}
+	var types []string
+	for _, list := range [][]json.RawMessage{def.AnyOf, def.OneOf, def.AllOf} {
+		for _, sub := range list {
+			types = append(types, s.upstreamTypes(sub, seen)...)
+		}
+	}
+	return append(types, s.upstreamTypes(def.Items, seen)...)
+}
+
+// compareADFSchema checks the condensed schema against the published one
+// and returns the differences that would let the validator accept documents
+// Atlassian rejects: unknown nodes and marks, and children the published
+// schema does not allow.
+func compareADFSchema(condensed *adfSchema, upstreamJSON []byte) ([]string, error) {
+	var upstream upstreamADFSchema
+	if err := json.Unmarshal(upstreamJSON, &upstream); err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]string) // node type -> allowed child types
+	marks := make(map[string]bool)
+	for name, raw := range upstream.Definitions {
+		var def upstreamADFDef
+		// Variants that refine a base node through allOf have no type enum
+		// of their own; the base definition covers them.
+		if err := json.Unmarshal(raw, &def); err != nil || len(def.Properties.Type.Enum) != 1 {
+			continue
+		}
+		typ := def.Properties.Type.Enum[0]
+		if strings.HasSuffix(name, "_mark") {
+			marks[typ] = true
+			continue
+		}
+		// Variants of a node (such as paragraph_with_no_marks_node) are
+		// merged, so a child allowed by any of them counts as allowed.
+		children[typ] = append(children[typ], upstream.upstreamTypes(def.Properties.Content, map[string]bool{})...)
+	}
+
+	var problems []string
+	for typ, spec := range condensed.Nodes {
+		allowed, ok := children[typ]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("node %s is not in the published schema", typ))
+			continue
+		}
+		for _, child := range spec.Content {
+			names := []string{child}
+			if group, ok := strings.CutPrefix(child, "@"); ok {
+				names = condensed.Groups[group]
+			}
+			for _, name := range names {
+				if !slices.Contains(allowed, name) {
+					problems = append(problems, fmt.Sprintf("node %s allows %s, which the published schema does not", typ, name))
+				}
+			}
+		}
+	}
+	for typ := range condensed.Marks {
+		if !marks[typ] {
+			problems = append(problems, fmt.Sprintf("mark %s is not in the published schema", typ))
+		}
+	}
+	slices.Sort(problems)
+	return problems, nil
+}
+
+func TestCompareADFSchema(t *testing.T) {
+	// A fragment in the layout of full.json
+	upstream := `{"definitions": {
+		"doc": {"type": "object", "properties": {"type": {"enum": ["doc"]},
+			"content": {"type": "array", "items": {"$ref": "#/definitions/block_content"}}}},
+		"block_content": {"anyOf": [{"$ref": "#/definitions/paragraph_node"}, {"$ref": "#/definitions/paragraph_with_no_marks_node"}]},
+		"paragraph_node": {"type": "object", "properties": {"type": {"enum": ["paragraph"]},
+			"content": {"type": "array", "items": {"$ref": "#/definitions/inline_node"}}}},
+		"paragraph_with_no_marks_node": {"allOf": [{"$ref": "#/definitions/paragraph_node"}]},
+		"inline_node": {"anyOf": [{"$ref": "#/definitions/text_node"}]},
+		"text_node": {"type": "object", "properties": {"type": {"enum": ["text"]}}},
+		"strong_mark": {"type": "object", "properties": {"type": {"enum": ["strong"]}}}
+	}}`
+
+	condensed := &adfSchema{
+		Groups: map[string][]string{"block": {"paragraph", "heading"}},
+		Nodes: map[string]adfNodeSpec{
+			"doc":       {Content: []string{"@block"}},
+			"paragraph": {Content: []string{"text"}},
+			"text":      {},
+			"heading":   {},
+		},
+		Marks: map[string]adfMarkSpec{"strong": {}, "em": {}},
+	}
+	problems, err := compareADFSchema(condensed, []byte(upstream))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "mark em is not in the published schema\n" +
+		"node doc allows heading, which the published schema does not\n" +
+		"node heading is not in the published schema"
+	if got := strings.Join(problems, "\n"); got != want {
+		t.Errorf("problems:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestADFSchemaMatchesUpstream checks the hand-maintained adf_schema.json
+// against the vendored copy of the published schema, which is updated with:
+//
+//	curl -o internal/jira/testdata/adf-schema-full.json https://unpkg.com/@atlaskit/adf-schema/dist/json-schema/v1/full.json
+//
+// ADF_UPSTREAM_SCHEMA checks against another copy instead.
+func TestADFSchemaMatchesUpstream(t *testing.T) {
+	path := upstreamADFSchemaFile
+	if env := os.Getenv(envUpstreamADFSchema); env != "" {
+		path = env
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && path == upstreamADFSchemaFile {
+		t.Skipf("%s has not been vendored", upstreamADFSchemaFile)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := compareADFSchema(adfSchemaDef, data)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
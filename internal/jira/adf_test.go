@@ -0,0 +1,221 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateADF_NilDoc(t *testing.T) {
+	if err := ValidateADF(nil); err != nil {
+		t.Errorf("expected nil document to be valid, got %v", err)
+	}
+}
+
+func TestValidateADF_MarkdownOutputIsValid(t *testing.T) {
+	inputs := []string{
+		"Hello world",
+		"# Title\n\nSome **bold** and *italic* and `code` text.",
+		"- one\n- two\n\n1. first\n2. second",
+		"```go\nfmt.Println(\"hi\")\n```",
+		"```\n```",
+		"See [the docs](https://example.com) and ***both***.",
+	}
+
+	for _, input := range inputs {
+		doc := TextToADF(input)
+		if err := ValidateADF(doc); err != nil {
+			t.Errorf("TextToADF(%q) produced invalid ADF: %v", input, err)
+		}
+	}
+}
+
+func TestValidateADF_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *ADFDoc
+		path    string
+		message string
+	}{
+		{
+			name:    "wrong root type",
+			doc:     &ADFDoc{Type: "paragraph", Version: 1},
+			path:    "/type",
+			message: "root node must be of type doc",
+		},
+		{
+			name:    "wrong version",
+			doc:     &ADFDoc{Type: "doc", Version: 2},
+			path:    "/version",
+			message: "unsupported version",
+		},
+		{
+			name: "unknown node type",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{Type: "paragraph"},
+				{Type: "banner"},
+			}},
+			path:    "/content/1/type",
+			message: `unknown node type "banner"`,
+		},
+		{
+			name: "inline node at block level",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				makeTextNode("loose"),
+			}},
+			path:    "/content/0",
+			message: "text node is not allowed inside doc",
+		},
+		{
+			name: "heading missing level",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{Type: "heading", Content: []ADFNode{makeTextNode("Title")}},
+			}},
+			path:    "/content/0/attrs",
+			message: `missing required attribute "level"`,
+		},
+		{
+			name: "heading level out of range",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				makeHeading(7, []ADFNode{makeTextNode("Title")}),
+			}},
+			path:    "/content/0/attrs/level",
+			message: "must be at most 6",
+		},
+		{
+			name: "empty text node",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				makeParagraph([]ADFNode{makeTextNode("")}),
+			}},
+			path:    "/content/0/content/0/text",
+			message: "non-empty text",
+		},
+		{
+			name: "empty list",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{Type: "bulletList"},
+			}},
+			path:    "/content/0/content",
+			message: "at least 1 child",
+		},
+		{
+			name: "paragraph directly in list",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{Type: "bulletList", Content: []ADFNode{makeParagraph(nil)}},
+			}},
+			path:    "/content/0/content/0",
+			message: "paragraph node is not allowed inside bulletList",
+		},
+		{
+			name: "link without href",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				makeParagraph([]ADFNode{makeMarkedText("x", []ADFMark{{Type: "link"}})}),
+			}},
+			path:    "/content/0/content/0/marks/0/attrs",
+			message: `missing required attribute "href"`,
+		},
+		{
+			name: "code combined with strong",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				makeParagraph([]ADFNode{makeMarkedText("x", []ADFMark{{Type: "strong"}, {Type: "code"}})}),
+			}},
+			path:    "/content/0/content/0/marks/1",
+			message: "code mark cannot be combined with strong mark",
+		},
+		{
+			name: "marks inside code block",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{Type: "codeBlock", Content: []ADFNode{makeMarkedText("x", []ADFMark{{Type: "strong"}})}},
+			}},
+			path:    "/content/0/content/0/marks/0",
+			message: "strong mark is not allowed on text node",
+		},
+		{
+			name: "invalid panel type",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{
+					Type:    "panel",
+					Attrs:   map[string]interface{}{"panelType": "danger"},
+					Content: []ADFNode{makeParagraph(nil)},
+				},
+			}},
+			path:    "/content/0/attrs/panelType",
+			message: "must be one of",
+		},
+		{
+			name: "unknown attribute",
+			doc: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{Type: "rule", Attrs: map[string]interface{}{"style": "dashed"}},
+			}},
+			path:    "/content/0/attrs/style",
+			message: `unknown attribute "style"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateADF(tt.doc)
+			if err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+			var adfErr *ADFValidationError
+			if !errors.As(err, &adfErr) {
+				t.Fatalf("expected *ADFValidationError, got %T", err)
+			}
+			if adfErr.Path != tt.path {
+				t.Errorf("expected path %q, got %q", tt.path, adfErr.Path)
+			}
+			if !strings.Contains(adfErr.Message, tt.message) {
+				t.Errorf("expected message containing %q, got %q", tt.message, adfErr.Message)
+			}
+		})
+	}
+}
+
+func TestValidateADF_DecodedJSON(t *testing.T) {
+	// Attribute numbers decoded from JSON arrive as float64
+	data := `{"type":"doc","version":1,"content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Hi"}]},
+		{"type":"status","attrs":{"text":"DONE","color":"green"}}
+	]}`
+
+	var doc ADFDoc
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	err := ValidateADF(&doc)
+	if err == nil {
+		t.Fatal("expected error for inline status node at block level")
+	}
+	if !strings.Contains(err.Error(), "/content/1") {
+		t.Errorf("expected error to point at /content/1, got %v", err)
+	}
+
+	doc.Content = doc.Content[:1]
+	if err := ValidateADF(&doc); err != nil {
+		t.Errorf("expected valid document, got %v", err)
+	}
+}
+
+func TestValidateADF_Media(t *testing.T) {
+	data := `{"type":"doc","version":1,"content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"See "},
+			{"type":"mediaInline","attrs":{"id":"f1","collection":"contentId-1","type":"file"}}
+		]},
+		{"type":"mediaSingle","attrs":{"layout":"center"},"content":[
+			{"type":"media","attrs":{"type":"file","id":"f2","collection":"contentId-1"}},
+			{"type":"caption","content":[{"type":"text","text":"Architecture"}]}
+		]}
+	]}`
+
+	var doc ADFDoc
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if err := ValidateADF(&doc); err != nil {
+		t.Errorf("expected valid document, got %v", err)
+	}
+}
@@ -93,8 +93,7 @@ func (c *Client) CheckConnectivity(ctx context.Context) error {
 }
 
 func (c *Client) handleError(resp *http.Response) error {
-	errResp := httpclient.NewErrorResponse(resp)
-	return fmt.Errorf("%s: %s", errResp.Error, errResp.Message)
+	return &httpclient.APIError{Response: httpclient.NewErrorResponse(resp)}
 }
 
 // SetHTTPClient sets the underlying HTTP client (for testing).
@@ -116,6 +115,11 @@ func (c *Client) CreateIssue(ctx context.Context, req *CreateIssueRequest) (*Cre
 		}
 	}
 
+	// Validate the description document before sending it
+	if err := ValidateADF(req.Fields.Description); err != nil {
+		return nil, err
+	}
+
 	// Serialize request body
 	body, err := json.Marshal(req)
 	if err != nil {
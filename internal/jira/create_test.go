@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -374,6 +375,36 @@ func TestClient_CreateIssue_InvalidParent(t *testing.T) {
 	}
 }
 
+func TestClient_CreateIssue_InvalidDescription(t *testing.T) {
+	cfg := &config.Config{
+		Site:  "test.atlassian.net",
+		Email: "test@example.com",
+		Token: "test-token",
+	}
+
+	client := NewClient(cfg, false)
+
+	req := &CreateIssueRequest{
+		Fields: CreateIssueFields{
+			Project:   ProjectRef{Key: "TEST"},
+			IssueType: IssueType{Name: "Story"},
+			Summary:   "Test story",
+			Description: &ADFDoc{Type: "doc", Version: 1, Content: []ADFNode{
+				{Type: "heading", Content: []ADFNode{{Type: "text", Text: "No level"}}},
+			}},
+		},
+	}
+
+	_, err := client.CreateIssue(context.Background(), req)
+	var adfErr *ADFValidationError
+	if !errors.As(err, &adfErr) {
+		t.Fatalf("expected *ADFValidationError, got: %v", err)
+	}
+	if adfErr.Path != "/content/0/attrs" {
+		t.Errorf("expected path /content/0/attrs, got %q", adfErr.Path)
+	}
+}
+
 func TestClient_CreateIssue_APIError(t *testing.T) {
 	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
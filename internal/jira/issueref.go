@@ -0,0 +1,37 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+// ParseIssueRef returns the issue key referred to by ref, which is either an
+// issue key or a link to an issue on the configured site, such as
+// https://acme.atlassian.net/browse/PROJ-1?focusedCommentId=10001 or a board
+// URL with a selectedIssue parameter. Site-relative links, such as
+// /browse/PROJ-1, are resolved against the configured site.
+func ParseIssueRef(ref string, cfg *config.Config) (string, error) {
+	if !strings.Contains(ref, "/") {
+		return ref, ValidateIssueKey(ref)
+	}
+
+	u, err := cfg.ParseSiteURL(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var key string
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) == 2 && segments[0] == "browse" {
+		key = segments[1]
+	} else {
+		key = u.Query().Get("selectedIssue")
+	}
+
+	if err := ValidateIssueKey(key); err != nil {
+		return "", fmt.Errorf("unrecognized Jira issue URL: %q", ref)
+	}
+	return key, nil
+}
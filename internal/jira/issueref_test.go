@@ -0,0 +1,46 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/martin/atl-cli/internal/config"
+)
+
+func TestParseIssueRef(t *testing.T) {
+	cfg := &config.Config{Site: "acme.atlassian.net"}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{name: "issue key", ref: "PROJ-1", want: "PROJ-1"},
+		{name: "browse URL", ref: "https://acme.atlassian.net/browse/PROJ-1", want: "PROJ-1"},
+		{name: "browse URL with query", ref: "https://acme.atlassian.net/browse/PROJ-1?focusedCommentId=10001", want: "PROJ-1"},
+		{name: "browse URL without scheme", ref: "acme.atlassian.net/browse/CST2-42", want: "CST2-42"},
+		{name: "site-relative browse URL", ref: "/browse/PROJ-1?focusedCommentId=10001", want: "PROJ-1"},
+		{name: "board URL", ref: "https://acme.atlassian.net/jira/software/projects/PROJ/boards/1?selectedIssue=PROJ-7", want: "PROJ-7"},
+		{name: "invalid key", ref: "proj-1", wantErr: "invalid issue key format"},
+		{name: "other site", ref: "https://other.atlassian.net/browse/PROJ-1", wantErr: "does not match the configured site"},
+		{name: "project URL", ref: "https://acme.atlassian.net/browse/PROJ", wantErr: "unrecognized Jira issue URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIssueRef(tt.ref, cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseIssueRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -293,3 +293,59 @@ func makeCodeBlock(language string, lines []string) ADFNode {
 	}
 	return node
 }
+
+// Markdown constructs that ParseMarkdownToADFNodes does not understand and
+// passes through as plain paragraph text.
+var (
+	tableRowRe    = regexp.MustCompile(`^\|.*\|$`)
+	blockquoteRe  = regexp.MustCompile(`^>`)
+	imageRe       = regexp.MustCompile(`!\[[^\]]*\]\([^)]+\)`)
+	nestedItemRe  = regexp.MustCompile(`^\s+(?:[-*]|\d+\.)\s+`)
+	thematicRe    = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+	strikeRe      = regexp.MustCompile(`~~[^~]+~~`)
+	htmlBlockRe   = regexp.MustCompile(`^<[a-zA-Z][^>]*>`)
+	markdownLossy = []struct {
+		re      *regexp.Regexp
+		trimmed bool // match against the trimmed line
+		message string
+	}{
+		{tableRowRe, true, "tables are not supported and were kept as plain text"},
+		{blockquoteRe, true, "blockquotes are not supported and were kept as plain text"},
+		{imageRe, false, "images are not supported and were kept as plain text"},
+		{nestedItemRe, false, "nested lists were flattened"},
+		{thematicRe, true, "horizontal rules are not supported and were kept as plain text"},
+		{strikeRe, false, "strikethrough is not supported and was kept as plain text"},
+		{htmlBlockRe, true, "raw HTML is not supported and was kept as plain text"},
+	}
+)
+
+// MarkdownWarnings reports Markdown constructs in text that
+// ParseMarkdownToADFNodes cannot represent faithfully. Content inside
+// fenced code blocks is ignored.
+func MarkdownWarnings(text string) []string {
+	var warnings []string
+	seen := make(map[string]bool)
+	inCodeBlock := false
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if codeFenceRe.MatchString(trimmed) {
+			inCodeBlock = !inCodeBlock || trimmed != "```"
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		for _, lossy := range markdownLossy {
+			subject := line
+			if lossy.trimmed {
+				subject = trimmed
+			}
+			if !seen[lossy.message] && lossy.re.MatchString(subject) {
+				seen[lossy.message] = true
+				warnings = append(warnings, lossy.message)
+			}
+		}
+	}
+	return warnings
+}
@@ -2,6 +2,7 @@ package jira
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -578,3 +579,35 @@ func assertCodeBlockText(t *testing.T, node ADFNode, expected string) {
 		t.Errorf("expected code text %q, got %q", expected, node.Content[0].Text)
 	}
 }
+
+// --- Lossy Conversion Warnings ---
+
+func TestMarkdownWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"supported only", "# Title\n\n- item\n\n**bold**", nil},
+		{"table", "| a | b |\n|---|---|", []string{"tables"}},
+		{"blockquote", "> quoted", []string{"blockquotes"}},
+		{"image", "See ![diagram](d.png)", []string{"images"}},
+		{"nested list", "- a\n  - b", []string{"nested lists"}},
+		{"inside code block", "```\n| a | b |\n> x\n```", nil},
+		{"multiple", "> x\n\n> y\n\n---", []string{"blockquotes", "horizontal rules"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := MarkdownWarnings(tt.input)
+			if len(warnings) != len(tt.expected) {
+				t.Fatalf("expected %d warnings, got %v", len(tt.expected), warnings)
+			}
+			for i, want := range tt.expected {
+				if !strings.HasPrefix(warnings[i], want) {
+					t.Errorf("warning %d: expected prefix %q, got %q", i, want, warnings[i])
+				}
+			}
+		})
+	}
+}
@@ -44,22 +44,31 @@ func LoadTemplate(path string) (*Template, error) {
 	return ParseTemplate(string(content))
 }
 
-// ParseTemplate parses template content into frontmatter and body.
-func ParseTemplate(content string) (*Template, error) {
+// SplitTemplateFrontmatter splits template content into its YAML
+// frontmatter, between "---" lines, and the body that follows. Confluence
+// page templates use the same layout.
+func SplitTemplateFrontmatter(content string) (frontmatter, body string, err error) {
 	// Check for YAML frontmatter delimiters
 	if !strings.HasPrefix(content, "---\n") {
-		return nil, fmt.Errorf("template must start with YAML frontmatter (---)")
+		return "", "", fmt.Errorf("template must start with YAML frontmatter (---)")
 	}
 
 	// Find the closing delimiter
 	rest := content[4:] // Skip opening "---\n"
 	endIdx := strings.Index(rest, "\n---")
 	if endIdx == -1 {
-		return nil, fmt.Errorf("template frontmatter not properly closed (missing ---)")
+		return "", "", fmt.Errorf("template frontmatter not properly closed (missing ---)")
 	}
 
-	frontmatterYAML := rest[:endIdx]
-	body := strings.TrimPrefix(rest[endIdx+4:], "\n") // Skip "\n---" and optional newline
+	return rest[:endIdx], strings.TrimPrefix(rest[endIdx+4:], "\n"), nil // Skip "\n---" and optional newline
+}
+
+// ParseTemplate parses template content into frontmatter and body.
+func ParseTemplate(content string) (*Template, error) {
+	frontmatterYAML, body, err := SplitTemplateFrontmatter(content)
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse YAML frontmatter
 	var fm TemplateFrontmatter
@@ -279,3 +279,16 @@ func TestLoadTemplate_NotFound(t *testing.T) {
 		t.Error("expected error for non-existent file")
 	}
 }
+
+func TestSplitTemplateFrontmatter(t *testing.T) {
+	frontmatter, body, err := SplitTemplateFrontmatter("---\nversion: 1\n---\n## Body\n---\nmore")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frontmatter != "version: 1" {
+		t.Errorf("frontmatter = %q", frontmatter)
+	}
+	if body != "## Body\n---\nmore" {
+		t.Errorf("body = %q", body)
+	}
+}